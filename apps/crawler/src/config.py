@@ -5,6 +5,7 @@ Loads configuration from config/project.config.yaml and environment variables.
 This provides a single source of truth for fork customization.
 """
 
+import ipaddress
 import os
 import yaml
 from dataclasses import dataclass
@@ -150,6 +151,9 @@ class CrawlerConfig:
     retry_backoff_multiplier: float
     fallback_protocol_versions: List[int]
     require_version_for_save: bool
+    denylist: List[str]  # CIDR ranges never crawled or reported (on top of bogons)
+    revisit_window_minutes: int  # Sliding window for backing off unreachable nodes
+    store_path: Optional[str]  # SQLite peer store for resumable passes and history (None disables)
 
     # Alerts (optional)
     alerts_enabled: bool
@@ -189,6 +193,9 @@ def load_crawler_config_from_yaml() -> dict:
             'retryBackoffMultiplier': 2,
             'fallbackProtocolVersions': [],
             'requireVersionForSave': True,
+            'denylist': [],
+            'revisitWindowMinutes': 360,
+            'storePath': './data/crawler/peers.db',
         }
 
     with open(config_path, 'r') as f:
@@ -259,6 +266,21 @@ def load_config() -> CrawlerConfig:
     ))
     fallback_protocol_versions = crawler_yaml.get('fallbackProtocolVersions', [])
     require_version_for_save = crawler_yaml.get('requireVersionForSave', True)
+    revisit_window_minutes = int(os.getenv(
+        "REVISIT_WINDOW_MINUTES",
+        str(crawler_yaml.get('revisitWindowMinutes', 360))
+    ))
+
+    # Denylist: YAML ranges plus comma-separated CRAWLER_DENYLIST
+    denylist = list(crawler_yaml.get('denylist') or [])
+    denylist += [n.strip() for n in os.getenv("CRAWLER_DENYLIST", "").split(",") if n.strip()]
+
+    # Peer store: relative paths resolve from the project root like GeoIP;
+    # an empty CRAWLER_STORE_PATH disables it
+    store_path = os.getenv("CRAWLER_STORE_PATH", crawler_yaml.get('storePath', './data/crawler/peers.db')) or None
+    if store_path and not os.path.isabs(store_path):
+        project_root = Path(find_dotenv('.env', usecwd=True)).parent if find_dotenv('.env', usecwd=True) else Path.cwd()
+        store_path = str(project_root / store_path)
 
     # Validate configuration
     if max_retries < 0:
@@ -276,6 +298,15 @@ def load_config() -> CrawlerConfig:
     if retry_backoff_multiplier < 1:
         raise ValueError(f"retryBackoffMultiplier must be >= 1, got {retry_backoff_multiplier}")
 
+    if revisit_window_minutes < 0:
+        raise ValueError(f"revisitWindowMinutes must be >= 0, got {revisit_window_minutes}")
+
+    for net in denylist:
+        try:
+            ipaddress.ip_network(net, strict=False)
+        except ValueError:
+            raise ValueError(f"denylist entries must be IPs or CIDR ranges, got {net!r}")
+
     # Validate protocol versions are integers
     if not isinstance(fallback_protocol_versions, list):
         fallback_protocol_versions = []
@@ -318,6 +349,9 @@ def load_config() -> CrawlerConfig:
         retry_backoff_multiplier=retry_backoff_multiplier,
         fallback_protocol_versions=fallback_protocol_versions,
         require_version_for_save=require_version_for_save,
+        denylist=denylist,
+        revisit_window_minutes=revisit_window_minutes,
+        store_path=store_path,
 
         # Alerts
         alerts_enabled=alerts_enabled,
@@ -10,10 +10,13 @@ P2P network crawler with:
 """
 
 import asyncio
+import ipaddress
 import socket
 import time
 import re
 import os
+import sqlite3
+from collections import deque
 from dataclasses import dataclass, field, replace
 from typing import Dict, List, Set, Optional
 from datetime import datetime, timedelta, timezone
@@ -32,10 +35,33 @@ from .protocol import (
 )
 from .geoip import GeoIPLookup
 from .database import Database
+from .store import PeerStore
 from .rpc import RPCClient
 
 logger = structlog.get_logger()
 
+# Special-purpose ranges that never belong on the map (RFC 6890 and friends).
+# Private and loopback ranges are handled by _is_valid_ip instead, because
+# development mode allows them.
+BOGON_NETWORKS = [
+    "0.0.0.0/8",
+    "100.64.0.0/10",
+    "169.254.0.0/16",
+    "192.0.0.0/24",
+    "192.0.2.0/24",
+    "198.18.0.0/15",
+    "198.51.100.0/24",
+    "203.0.113.0/24",
+    "224.0.0.0/4",
+    "240.0.0.0/4",
+    "::/128",
+    "100::/64",
+    "2001:2::/48",
+    "2001:db8::/32",
+    "fe80::/10",
+    "ff00::/8",
+]
+
 
 @dataclass
 class NodeInfo:
@@ -67,6 +93,21 @@ class Crawler:
         self.pending: Set[str] = set()
         self.crawled: Set[str] = set()
 
+        # Denylist: bogons plus configured ranges (e.g. known sybil operators)
+        self.denylist = [
+            ipaddress.ip_network(net, strict=False)
+            for net in BOGON_NETWORKS + config.denylist
+        ]
+
+        # Revisit scheduling: recent failure times per IP:port, kept for
+        # revisit_window_minutes. Persists across passes, unlike crawled.
+        self.failures: Dict[str, deque] = {}
+
+        # Planned shutdown dates per IP:port (verify retire), from the
+        # database. Retired nodes get no more snapshots, so their uptime
+        # record ends at the shutdown instead of decaying.
+        self.retiring: Dict[str, datetime] = {}
+
         # Rate limiting
         self.semaphore = asyncio.Semaphore(config.max_concurrent)
 
@@ -74,6 +115,18 @@ class Crawler:
         self.geoip = GeoIPLookup(config.geoip_db_path)
         self.db = Database(config.supabase_url, config.supabase_key, config.chain)
 
+        # Peer store (optional): checkpoints of the pass in progress and the
+        # reachability history, surviving restarts
+        self.store: Optional[PeerStore] = None
+        self._pass_id: Optional[int] = None
+        if config.store_path:
+            try:
+                self.store = PeerStore(config.store_path)
+                self.failures = self.store.load_failures()
+                logger.info("Peer store opened", path=config.store_path, revisits=len(self.failures))
+            except sqlite3.Error as e:
+                logger.error("Failed to open peer store", path=config.store_path, error=str(e))
+
         # RPC client (if configured)
         self.rpc: Optional[RPCClient] = None
         if config.rpc_host and config.rpc_user and config.rpc_pass:
@@ -99,6 +152,8 @@ class Crawler:
             "peers_from_config": 0,
             "peers_from_p2p": 0,
             "peers_from_db": 0,
+            "denied": 0,
+            "revisits_deferred": 0,
         }
 
         # Dynamic version from database (fetched from web API)
@@ -147,6 +202,94 @@ class Crawler:
         """Create a unique key for a node."""
         return f"{ip}:{port}"
 
+    def _enqueue(self, ip: str, port: int) -> bool:
+        """
+        Dedupe layer for all seed sources: queue IP:port for this pass unless
+        it is already queued or crawled, denylisted, or not yet due for a
+        revisit. Returns True if the node was queued.
+        """
+        key = self._node_key(ip, port)
+        if key in self.pending or key in self.crawled:
+            return False
+
+        if self._is_denied(ip):
+            self.stats["denied"] += 1
+            return False
+
+        if not self._is_due(key):
+            self.stats["revisits_deferred"] += 1
+            return False
+
+        self.pending.add(key)
+        return True
+
+    def _is_denied(self, ip: str) -> bool:
+        """Check an IP against the bogon list and configured denylist."""
+        try:
+            addr = ipaddress.ip_address(ip)
+        except ValueError:
+            return True
+
+        if addr.version == 6 and addr.ipv4_mapped:
+            addr = addr.ipv4_mapped
+
+        return any(addr in net for net in self.denylist)
+
+    def _record_visit(self, key: str, reachable: bool) -> None:
+        """Track failures inside the sliding revisit window."""
+        window = self.config.revisit_window_minutes * 60
+        if reachable or window <= 0:
+            self.failures.pop(key, None)
+            return
+
+        now = time.time()
+        recent = self.failures.setdefault(key, deque())
+        recent.append(now)
+        while recent and now - recent[0] > window:
+            recent.popleft()
+
+    def _track_retirement(self, key: str, retiring_at) -> None:
+        """Remember (or forget, once cancelled) a node's planned shutdown."""
+        if not retiring_at:
+            self.retiring.pop(key, None)
+            return
+
+        try:
+            if isinstance(retiring_at, str):
+                retiring_at = datetime.fromisoformat(retiring_at.replace('Z', '+00:00'))
+            if retiring_at.tzinfo is None:
+                retiring_at = retiring_at.replace(tzinfo=timezone.utc)
+            self.retiring[key] = retiring_at
+        except (TypeError, ValueError) as e:
+            logger.debug("Failed to parse retiring_at", key=key, error=str(e))
+
+    def _is_retired(self, key: str) -> bool:
+        """Whether the node's planned shutdown date has passed."""
+        retiring_at = self.retiring.get(key)
+        return retiring_at is not None and retiring_at <= datetime.now(timezone.utc)
+
+    def _is_due(self, key: str) -> bool:
+        """
+        Decide whether a node should be visited this pass. Each failure in
+        the window doubles the wait (one crawl interval after the first),
+        capped at the window itself, so dead nodes stop eating connection
+        slots while recovered nodes are picked up again within the window.
+        """
+        recent = self.failures.get(key)
+        if not recent:
+            return True
+
+        window = self.config.revisit_window_minutes * 60
+        now = time.time()
+        while recent and now - recent[0] > window:
+            recent.popleft()
+        if not recent:
+            del self.failures[key]
+            return True
+
+        delay = min(window, self.config.interval_minutes * 60 * 2 ** (len(recent) - 1))
+        return now - recent[-1] >= delay
+
     async def _connect_and_handshake(
         self,
         ip: str,
@@ -512,6 +655,7 @@ class Crawler:
             node_info = await self._connect_and_handshake(ip, port)
 
             self.crawled.add(key)
+            self._record_visit(key, node_info is not None)
 
             if node_info:
                 self.stats["connections_successful"] += 1
@@ -536,11 +680,9 @@ class Crawler:
 
                 # Add discovered peers to pending
                 for peer in node_info.peers:
-                    peer_key = self._node_key(peer.ip, peer.port)
-                    if peer_key not in self.crawled and peer_key not in self.pending:
-                        # Validate IP
-                        if self._is_valid_ip(peer.ip):
-                            self.pending.add(peer_key)
+                    # Validate IP
+                    if self._is_valid_ip(peer.ip):
+                        self._enqueue(peer.ip, peer.port)
 
             else:
                 self.stats["connections_failed"] += 1
@@ -571,6 +713,69 @@ class Crawler:
                     self.nodes[key] = node_info_unreachable
                     self.stats["nodes_discovered"] += 1
 
+            self._store_visit(key)
+
+    def _store_visit(self, key: str) -> None:
+        """Record a visit in the peer store, committed at the next checkpoint."""
+        if not self.store or self._pass_id is None:
+            return
+        try:
+            self.store.record_handshake(self._pass_id, self.nodes[key])
+        except sqlite3.Error as e:
+            logger.warning("Failed to record visit", key=key, error=str(e))
+
+    def _checkpoint(self) -> None:
+        """Persist the queue and revisit schedule so a restart resumes here."""
+        if not self.store or self._pass_id is None:
+            return
+        try:
+            self.store.checkpoint(self._pass_id, self.pending, self.failures)
+        except sqlite3.Error as e:
+            logger.warning("Failed to checkpoint crawl pass", error=str(e))
+
+    def _resume_pass(self) -> List[str]:
+        """
+        Start a pass in the peer store. When the previous run stopped mid-pass,
+        take over its visited nodes so they aren't crawled again, and return
+        its queue.
+        """
+        if not self.store:
+            return []
+        try:
+            self._pass_id, resumed = self.store.begin_pass()
+            if not resumed:
+                return []
+            visited = self.store.visited(self._pass_id)
+            pending = self.store.pending(self._pass_id)
+        except sqlite3.Error as e:
+            logger.warning("Failed to start crawl pass in peer store", error=str(e))
+            self._pass_id = None
+            return []
+
+        for row in visited:
+            key = self._node_key(row["ip"], row["port"])
+            self.crawled.add(key)
+            self.nodes[key] = NodeInfo(
+                ip=row["ip"],
+                port=row["port"],
+                version=row["user_agent"],
+                user_agent=row["user_agent"],
+                protocol_version=row["protocol_version"],
+                services=row["services"],
+                start_height=row["start_height"],
+                latency_ms=row["latency_ms"],
+                status=row["status"],
+                first_seen=datetime.fromisoformat(row["first_seen"]),
+                last_seen=datetime.fromisoformat(row["checked_at"]),
+            )
+        logger.info(
+            "Resuming interrupted crawl pass",
+            pass_id=self._pass_id,
+            visited=len(self.crawled),
+            pending=len(pending),
+        )
+        return pending
+
     def _is_valid_ip(self, ip: str) -> bool:
         """Check if an IP is valid and not private (unless in development mode)."""
         try:
@@ -725,6 +930,7 @@ class Crawler:
 
                 key = self._node_key(str(ip), port)
                 status = node.get("status", "unknown")
+                self._track_retirement(key, node.get("retiring_at"))
 
                 # Re-crawl logic based on node status
                 last_seen = node.get("last_seen")
@@ -760,8 +966,7 @@ class Crawler:
                 else:
                     should_crawl = True
 
-                if should_crawl:
-                    self.pending.add(key)
+                if should_crawl and self._enqueue(str(ip), port):
                     self.stats["peers_from_db"] += 1
 
                     # CRITICAL: Populate self.nodes with existing DB data
@@ -820,9 +1025,7 @@ class Crawler:
 
             rpc_peers = await self.rpc.get_all_peers()
             for ip, port in rpc_peers:
-                if self._is_valid_ip(ip):
-                    key = self._node_key(ip, port)
-                    self.pending.add(key)
+                if self._is_valid_ip(ip) and self._enqueue(ip, port):
                     self.stats["peers_from_rpc"] += 1
 
             logger.info("Seeded from RPC", count=len(rpc_peers))
@@ -950,9 +1153,8 @@ class Crawler:
         seed_ips = await self._resolve_dns_seeds()
 
         for ip in seed_ips:
-            key = self._node_key(ip, self.chain_config.p2p_port)
-            self.pending.add(key)
-            self.stats["peers_from_dns"] += 1
+            if self._enqueue(ip, self.chain_config.p2p_port):
+                self.stats["peers_from_dns"] += 1
 
         logger.info("Seeded from DNS", count=len(seed_ips))
 
@@ -992,12 +1194,9 @@ class Crawler:
                     ip = node_addr
                     port = self.chain_config.p2p_port
 
-                if self._is_valid_ip(ip):
-                    key = self._node_key(ip, port)
-                    if key not in self.crawled and key not in self.pending:
-                        self.pending.add(key)
-                        self.stats["peers_from_config"] += 1
-                        count += 1
+                if self._is_valid_ip(ip) and self._enqueue(ip, port):
+                    self.stats["peers_from_config"] += 1
+                    count += 1
 
             except Exception as e:
                 logger.warning("Failed to parse seed node", addr=node_addr, error=str(e))
@@ -1016,6 +1215,7 @@ class Crawler:
                 tasks.append(self._crawl_node(ip, int(port)))
 
             await asyncio.gather(*tasks, return_exceptions=True)
+            self._checkpoint()
 
             logger.info(
                 "Crawl progress",
@@ -1051,6 +1251,11 @@ class Crawler:
         self.stats["peers_from_config"] = 0
         self.stats["peers_from_p2p"] = 0
         self.stats["peers_from_db"] = 0
+        self.stats["denied"] = 0
+        self.stats["revisits_deferred"] = 0
+
+        # Pick up an interrupted pass from the peer store
+        resumed_pending = self._resume_pass()
 
         # Seed from database first (re-crawl known nodes)
         await self._seed_from_database()
@@ -1067,6 +1272,12 @@ class Crawler:
         # Seed from configured seed nodes (guaranteed discovery)
         await self._seed_from_config()
 
+        # The rest of the interrupted pass's queue, after the seeds so that
+        # known nodes carry their database data
+        for key in resumed_pending:
+            if key not in self.crawled and not self._is_denied(key.rsplit(":", 1)[0]):
+                self.pending.add(key)
+
         if not self.pending:
             logger.error("No seed nodes found from any source!")
             return
@@ -1078,10 +1289,13 @@ class Crawler:
             from_rpc=self.stats["peers_from_rpc"],
             from_dns=self.stats["peers_from_dns"],
             from_config=self.stats["peers_from_config"],
+            denied=self.stats["denied"],
+            revisits_deferred=self.stats["revisits_deferred"],
         )
 
         # Crawl all pending nodes
         await self._crawl_pending()
+        self._finish_pass()
 
         logger.info(
             "Crawl pass complete",
@@ -1101,6 +1315,20 @@ class Crawler:
         # Cleanup stale nodes
         await self._prune_stale_nodes()
 
+    def _finish_pass(self) -> None:
+        """Close the pass in the peer store, keeping it as history."""
+        if not self.store or self._pass_id is None:
+            return
+        try:
+            self.store.finish_pass(
+                self._pass_id,
+                total_nodes=len(self.nodes),
+                reachable_nodes=sum(1 for n in self.nodes.values() if n.status == "up"),
+            )
+        except sqlite3.Error as e:
+            logger.warning("Failed to finish crawl pass in peer store", error=str(e))
+        self._pass_id = None
+
     async def run(self) -> None:
         """Run continuous crawler (runs indefinitely)."""
         logger.info(
@@ -1135,9 +1363,15 @@ class Crawler:
         logger.info("Saving nodes to database", count=len(self.nodes))
 
         skipped_no_version = 0
+        skipped_denied = 0
         debug_count = 0
         for key, node in self.nodes.items():
             try:
+                # Filter: Never report bogon or denylisted addresses
+                if self._is_denied(node.ip):
+                    skipped_denied += 1
+                    continue
+
                 # Filter: Skip nodes without version data if configured
                 if self.config.require_version_for_save:
                     if not node.user_agent or not node.protocol_version:
@@ -1213,8 +1447,9 @@ class Crawler:
                 # Upsert node and get the node_id
                 node_id = await self.db.upsert_node(node_data)
 
-                # Create snapshot for historical tracking
-                if node_id:
+                # Create snapshot for historical tracking. Retired nodes
+                # keep the record they had at their planned shutdown.
+                if node_id and not self._is_retired(key):
                     is_online = node.status == "up"
                     await self.db.create_node_snapshot(
                         node_id=node_id,
@@ -1228,8 +1463,9 @@ class Crawler:
 
         logger.info(
             "Nodes saved to database",
-            saved=len(self.nodes) - skipped_no_version,
+            saved=len(self.nodes) - skipped_no_version - skipped_denied,
             skipped_no_version=skipped_no_version,
+            skipped_denied=skipped_denied,
             require_version_for_save=self.config.require_version_for_save
         )
 
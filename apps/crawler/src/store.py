@@ -0,0 +1,283 @@
+"""
+Peer Store
+
+Durable SQLite record of everything the crawler sees: each discovered peer
+with its first/last sighting, and every handshake result per crawl pass.
+The pass in progress (its queue and the revisit schedule) is checkpointed
+after each batch, so a restarted crawler resumes where it stopped instead
+of starting over. Passes and handshakes double as historical reachability
+data, exported with:
+
+    python -m src.store export --db data/crawler/peers.db [--since ISO] [--format csv|json]
+    python -m src.store passes --db data/crawler/peers.db [--since ISO] [--format csv|json]
+    python -m src.store snapshot --db data/crawler/peers.db backup.db
+"""
+
+import argparse
+import csv
+import json
+import os
+import sqlite3
+import sys
+from collections import deque
+from datetime import datetime, timezone
+from typing import Dict, Iterable, List, Optional, Tuple
+
+SCHEMA = """
+CREATE TABLE IF NOT EXISTS peers (
+    ip TEXT NOT NULL,
+    port INTEGER NOT NULL,
+    first_seen TEXT NOT NULL,
+    last_seen TEXT NOT NULL,
+    last_status TEXT NOT NULL,
+    user_agent TEXT,
+    protocol_version INTEGER,
+    services INTEGER,
+    start_height INTEGER,
+    latency_ms REAL,
+    PRIMARY KEY (ip, port)
+);
+
+CREATE TABLE IF NOT EXISTS passes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    started_at TEXT NOT NULL,
+    finished_at TEXT,
+    total_nodes INTEGER,
+    reachable_nodes INTEGER
+);
+
+CREATE TABLE IF NOT EXISTS handshakes (
+    pass_id INTEGER NOT NULL REFERENCES passes(id) ON DELETE CASCADE,
+    ip TEXT NOT NULL,
+    port INTEGER NOT NULL,
+    checked_at TEXT NOT NULL,
+    status TEXT NOT NULL,
+    user_agent TEXT,
+    protocol_version INTEGER,
+    services INTEGER,
+    start_height INTEGER,
+    latency_ms REAL,
+    PRIMARY KEY (pass_id, ip, port)
+);
+CREATE INDEX IF NOT EXISTS handshakes_peer ON handshakes (ip, port, checked_at);
+
+-- Queue of the unfinished pass
+CREATE TABLE IF NOT EXISTS pending (
+    pass_id INTEGER NOT NULL REFERENCES passes(id) ON DELETE CASCADE,
+    ip TEXT NOT NULL,
+    port INTEGER NOT NULL,
+    PRIMARY KEY (pass_id, ip, port)
+);
+
+-- Revisit schedule: recent failure times (unix seconds) per peer
+CREATE TABLE IF NOT EXISTS failures (
+    ip TEXT NOT NULL,
+    port INTEGER NOT NULL,
+    failed_at REAL NOT NULL
+);
+"""
+
+
+def _now() -> str:
+    return datetime.now(timezone.utc).isoformat()
+
+
+def _split_key(key: str) -> Tuple[str, int]:
+    ip, port = key.rsplit(":", 1)
+    return ip, int(port)
+
+
+class PeerStore:
+    """SQLite store of discovered peers, crawl passes and handshakes."""
+
+    def __init__(self, path: str):
+        directory = os.path.dirname(path)
+        if directory:
+            os.makedirs(directory, exist_ok=True)
+        self.path = path
+        self.conn = sqlite3.connect(path)
+        self.conn.row_factory = sqlite3.Row
+        # WAL lets snapshots and exports read while the crawler writes
+        self.conn.execute("PRAGMA journal_mode=WAL")
+        self.conn.execute("PRAGMA foreign_keys=ON")
+        self.conn.executescript(SCHEMA)
+
+    def close(self) -> None:
+        self.conn.close()
+
+    # Crawl passes
+
+    def begin_pass(self) -> Tuple[int, bool]:
+        """
+        Start a crawl pass, or resume the one a previous run left unfinished.
+        Returns the pass id and whether it was resumed.
+        """
+        row = self.conn.execute(
+            "SELECT id FROM passes WHERE finished_at IS NULL ORDER BY id DESC LIMIT 1"
+        ).fetchone()
+        if row:
+            return row["id"], True
+
+        with self.conn:
+            cur = self.conn.execute("INSERT INTO passes (started_at) VALUES (?)", (_now(),))
+        return cur.lastrowid, False
+
+    def finish_pass(self, pass_id: int, total_nodes: int, reachable_nodes: int) -> None:
+        with self.conn:
+            self.conn.execute(
+                "UPDATE passes SET finished_at = ?, total_nodes = ?, reachable_nodes = ? WHERE id = ?",
+                (_now(), total_nodes, reachable_nodes, pass_id),
+            )
+            self.conn.execute("DELETE FROM pending WHERE pass_id = ?", (pass_id,))
+
+    def visited(self, pass_id: int) -> List[sqlite3.Row]:
+        """Handshake results recorded so far in a pass."""
+        return self.conn.execute(
+            "SELECT h.*, p.first_seen FROM handshakes h JOIN peers p USING (ip, port) WHERE h.pass_id = ?",
+            (pass_id,),
+        ).fetchall()
+
+    def pending(self, pass_id: int) -> List[str]:
+        """The queue of a pass at its last checkpoint, as IP:port keys."""
+        rows = self.conn.execute(
+            "SELECT ip, port FROM pending WHERE pass_id = ?", (pass_id,)
+        ).fetchall()
+        return [f"{row['ip']}:{row['port']}" for row in rows]
+
+    # Recording
+
+    def record_handshake(self, pass_id: int, node) -> None:
+        """
+        Record a visit to a peer (a crawler NodeInfo). Committed with the
+        next checkpoint.
+        """
+        checked_at = node.last_seen.isoformat()
+        user_agent = node.user_agent or node.version
+        self.conn.execute(
+            """
+            INSERT INTO peers (ip, port, first_seen, last_seen, last_status, user_agent,
+                               protocol_version, services, start_height, latency_ms)
+            VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+            ON CONFLICT (ip, port) DO UPDATE SET
+                last_seen = excluded.last_seen,
+                last_status = excluded.last_status,
+                user_agent = COALESCE(excluded.user_agent, peers.user_agent),
+                protocol_version = COALESCE(excluded.protocol_version, peers.protocol_version),
+                services = COALESCE(excluded.services, peers.services),
+                start_height = COALESCE(excluded.start_height, peers.start_height),
+                latency_ms = excluded.latency_ms
+            """,
+            (node.ip, node.port, node.first_seen.isoformat(), checked_at, node.status, user_agent,
+             node.protocol_version, node.services, node.start_height, node.latency_ms),
+        )
+        self.conn.execute(
+            """
+            INSERT OR REPLACE INTO handshakes (pass_id, ip, port, checked_at, status, user_agent,
+                                               protocol_version, services, start_height, latency_ms)
+            VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+            """,
+            (pass_id, node.ip, node.port, checked_at, node.status, user_agent,
+             node.protocol_version, node.services, node.start_height, node.latency_ms),
+        )
+
+    def checkpoint(self, pass_id: int, pending: Iterable[str], failures: Dict[str, deque]) -> None:
+        """Persist the queue and revisit schedule along with recorded visits."""
+        with self.conn:
+            self.conn.execute("DELETE FROM pending WHERE pass_id = ?", (pass_id,))
+            self.conn.executemany(
+                "INSERT OR IGNORE INTO pending (pass_id, ip, port) VALUES (?, ?, ?)",
+                [(pass_id, *_split_key(key)) for key in pending],
+            )
+            self.conn.execute("DELETE FROM failures")
+            self.conn.executemany(
+                "INSERT INTO failures (ip, port, failed_at) VALUES (?, ?, ?)",
+                [(*_split_key(key), t) for key, times in failures.items() for t in times],
+            )
+
+    def load_failures(self) -> Dict[str, deque]:
+        failures: Dict[str, deque] = {}
+        for row in self.conn.execute("SELECT ip, port, failed_at FROM failures ORDER BY failed_at"):
+            failures.setdefault(f"{row['ip']}:{row['port']}", deque()).append(row["failed_at"])
+        return failures
+
+    # History
+
+    def reachability(self, since: Optional[str] = None) -> List[Dict]:
+        """Per-peer reachability over the handshakes since an ISO time."""
+        rows = self.conn.execute(
+            """
+            SELECT p.ip, p.port, p.first_seen, p.last_seen, p.last_status, p.user_agent,
+                   COUNT(h.pass_id) AS checks,
+                   COALESCE(SUM(h.status = 'up'), 0) AS up_checks
+            FROM peers p
+            LEFT JOIN handshakes h ON h.ip = p.ip AND h.port = p.port AND h.checked_at >= ?
+            GROUP BY p.ip, p.port
+            ORDER BY p.ip, p.port
+            """,
+            (since or "",),
+        ).fetchall()
+        result = []
+        for row in rows:
+            entry = dict(row)
+            entry["uptime"] = round(row["up_checks"] / row["checks"], 4) if row["checks"] else None
+            result.append(entry)
+        return result
+
+    def passes(self, since: Optional[str] = None) -> List[Dict]:
+        rows = self.conn.execute(
+            "SELECT * FROM passes WHERE started_at >= ? ORDER BY id", (since or "",)
+        ).fetchall()
+        return [dict(row) for row in rows]
+
+    def snapshot(self, dest: str) -> None:
+        """Consistent copy of the store, safe while the crawler runs."""
+        target = sqlite3.connect(dest)
+        try:
+            self.conn.backup(target)
+        finally:
+            target.close()
+
+
+def _write(rows: List[Dict], fmt: str, out) -> None:
+    if fmt == "json":
+        json.dump(rows, out, indent=2)
+        out.write("\n")
+        return
+    if not rows:
+        return
+    writer = csv.DictWriter(out, fieldnames=list(rows[0].keys()))
+    writer.writeheader()
+    writer.writerows(rows)
+
+
+def main(argv: Optional[List[str]] = None) -> int:
+    parser = argparse.ArgumentParser(prog="python -m src.store", description="Export crawler history")
+    parser.add_argument("--db", default=os.getenv("CRAWLER_STORE_PATH", "./data/crawler/peers.db"))
+    commands = parser.add_subparsers(dest="command", required=True)
+    for name, help_text in (("export", "per-peer reachability"), ("passes", "crawl passes")):
+        cmd = commands.add_parser(name, help=help_text)
+        cmd.add_argument("--since", help="only handshakes/passes from this ISO time on")
+        cmd.add_argument("--format", choices=("csv", "json"), default="csv")
+    snapshot = commands.add_parser("snapshot", help="copy the store to a file")
+    snapshot.add_argument("dest")
+    args = parser.parse_args(argv)
+
+    if not os.path.exists(args.db):
+        print(f"No crawler store at {args.db}", file=sys.stderr)
+        return 1
+    store = PeerStore(args.db)
+    try:
+        if args.command == "export":
+            _write(store.reachability(args.since), args.format, sys.stdout)
+        elif args.command == "passes":
+            _write(store.passes(args.since), args.format, sys.stdout)
+        else:
+            store.snapshot(args.dest)
+            print(f"Snapshot written to {args.dest}", file=sys.stderr)
+    finally:
+        store.close()
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
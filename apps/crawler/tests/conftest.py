@@ -0,0 +1,6 @@
+"""Make the crawler package importable as `src` when running pytest."""
+
+import os
+import sys
+
+sys.path.insert(0, os.path.abspath(os.path.join(os.path.dirname(__file__), "..")))
@@ -0,0 +1,73 @@
+"""
+Tests for planned shutdowns (verify retire): retired nodes stop getting
+uptime snapshots so their record ends at the shutdown date.
+"""
+
+import asyncio
+import unittest
+from datetime import datetime, timedelta, timezone
+from unittest import mock
+
+from tests.test_scheduling import make_crawler
+
+
+def db_node(ip, retiring_at=None):
+    now = datetime.now(timezone.utc).isoformat()
+    return {
+        "ip": ip,
+        "port": 22556,
+        "status": "up",
+        "version": "/Dingocoin:1.18.0/",
+        "protocol_version": 70015,
+        "last_seen": now,
+        "first_seen": now,
+        "retiring_at": retiring_at,
+    }
+
+
+class RetirementTest(unittest.TestCase):
+    def test_track_retirement(self):
+        crawler = make_crawler()
+        past = (datetime.now(timezone.utc) - timedelta(days=1)).isoformat().replace("+00:00", "Z")
+        future = (datetime.now(timezone.utc) + timedelta(days=1)).isoformat()
+
+        crawler._track_retirement("a", past)
+        crawler._track_retirement("b", future)
+        crawler._track_retirement("c", "next tuesday")
+        self.assertTrue(crawler._is_retired("a"))
+        self.assertFalse(crawler._is_retired("b"))
+        self.assertFalse(crawler._is_retired("c"))
+        self.assertFalse(crawler._is_retired("unknown"))
+
+        # Cancelled shutdowns are forgotten
+        crawler._track_retirement("a", None)
+        self.assertFalse(crawler._is_retired("a"))
+
+    def test_retired_nodes_get_no_snapshots(self):
+        crawler = make_crawler()
+        crawler.config.require_version_for_save = False
+        crawler.chain_config.p2p_port = 22556
+        past = (datetime.now(timezone.utc) - timedelta(hours=1)).isoformat()
+        future = (datetime.now(timezone.utc) + timedelta(days=30)).isoformat()
+
+        crawler.db.get_all_nodes = mock.AsyncMock(return_value=[
+            db_node("8.8.8.8", retiring_at=past),
+            db_node("9.9.9.9", retiring_at=future),
+            db_node("1.1.1.1"),
+        ])
+        crawler.db.upsert_node = mock.AsyncMock(side_effect=lambda data: "id-" + data["ip"])
+        crawler.db.create_node_snapshot = mock.AsyncMock(return_value=True)
+        crawler.geoip.lookup.return_value = {}
+        crawler._parse_version = mock.Mock(return_value={})
+        crawler._process_alerts = mock.AsyncMock()
+
+        asyncio.run(crawler._seed_from_database())
+        asyncio.run(crawler._save_to_database())
+
+        self.assertEqual(crawler.db.upsert_node.await_count, 3)
+        snapshotted = {c.kwargs["node_id"] for c in crawler.db.create_node_snapshot.await_args_list}
+        self.assertEqual(snapshotted, {"id-9.9.9.9", "id-1.1.1.1"})
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,170 @@
+"""
+Tests for the crawler's seed dedupe layer: bogon/denylist filtering and
+sliding-window revisit scheduling.
+"""
+
+import unittest
+from types import SimpleNamespace
+from unittest import mock
+
+from src import crawler as crawler_module
+from src.crawler import Crawler
+
+INTERVAL_MINUTES = 5
+WINDOW_MINUTES = 360
+
+
+def make_crawler(denylist=None, window=WINDOW_MINUTES, store_path=None):
+    """Build a Crawler without touching GeoIP, Supabase or RPC."""
+    config = SimpleNamespace(
+        chain_config=SimpleNamespace(rpc_port=22555, current_version="1.0.0"),
+        chain="dingocoin",
+        denylist=denylist or [],
+        revisit_window_minutes=window,
+        interval_minutes=INTERVAL_MINUTES,
+        max_concurrent=10,
+        geoip_db_path="",
+        supabase_url="http://localhost:4020/rest/v1",
+        supabase_key="",
+        rpc_host=None,
+        rpc_user=None,
+        rpc_pass=None,
+        rpc_port=None,
+        store_path=store_path,
+    )
+    with mock.patch.object(crawler_module, "GeoIPLookup"), mock.patch.object(crawler_module, "Database"):
+        return Crawler(config)
+
+
+class FakeClock:
+    def __init__(self, now=1_000_000.0):
+        self.now = now
+
+    def time(self):
+        return self.now
+
+    def advance(self, minutes):
+        self.now += minutes * 60
+
+
+class IsDeniedTest(unittest.TestCase):
+    def test_bogons_and_denylist(self):
+        crawler = make_crawler(denylist=["45.12.0.0/16", "2a0e:1234::/32"])
+        cases = [
+            ("8.8.8.8", False),
+            ("2a01:4f8::1", False),
+            ("100.64.1.1", True),
+            ("192.0.2.10", True),
+            ("198.51.100.7", True),
+            ("203.0.113.9", True),
+            ("240.0.0.1", True),
+            ("224.0.0.1", True),
+            ("169.254.1.1", True),
+            ("2001:db8::1", True),
+            ("fe80::1", True),
+            ("::ffff:203.0.113.9", True),
+            ("::ffff:8.8.8.8", False),
+            ("45.12.3.4", True),
+            ("::ffff:45.12.3.4", True),
+            ("2a0e:1234::5", True),
+            ("not-an-ip", True),
+        ]
+        for ip, want in cases:
+            with self.subTest(ip=ip):
+                self.assertEqual(crawler._is_denied(ip), want)
+
+    def test_private_ranges_left_to_is_valid_ip(self):
+        # Development mode crawls private networks, so they are not bogons
+        crawler = make_crawler()
+        for ip in ("10.0.0.1", "192.168.1.1", "127.0.0.1"):
+            with self.subTest(ip=ip):
+                self.assertFalse(crawler._is_denied(ip))
+
+
+class RevisitScheduleTest(unittest.TestCase):
+    def setUp(self):
+        self.clock = FakeClock()
+        patcher = mock.patch.object(crawler_module.time, "time", self.clock.time)
+        patcher.start()
+        self.addCleanup(patcher.stop)
+
+    def test_backoff_doubles_per_failure(self):
+        crawler = make_crawler()
+        key = "8.8.8.8:22556"
+        self.assertTrue(crawler._is_due(key))
+
+        # Waits after 1, 2, 3 failures: 1, 2 and 4 crawl intervals
+        for failures, wait in ((1, 1), (2, 2), (3, 4)):
+            crawler._record_visit(key, reachable=False)
+            with self.subTest(failures=failures):
+                self.clock.advance(wait * INTERVAL_MINUTES - 1)
+                self.assertFalse(crawler._is_due(key))
+                self.clock.advance(1)
+                self.assertTrue(crawler._is_due(key))
+
+    def test_backoff_capped_at_window(self):
+        crawler = make_crawler(window=60)
+        key = "8.8.8.8:22556"
+        for _ in range(5):
+            crawler._record_visit(key, reachable=False)
+        # 5 failures would mean 16 intervals (80 min), but the cap is the window
+        self.clock.advance(60 - 1)
+        self.assertFalse(crawler._is_due(key))
+        self.clock.advance(1)
+        self.assertTrue(crawler._is_due(key))
+
+    def test_failures_expire_from_window(self):
+        crawler = make_crawler(window=60)
+        key = "8.8.8.8:22556"
+        crawler._record_visit(key, reachable=False)
+        self.clock.advance(61)
+        self.assertTrue(crawler._is_due(key))
+        self.assertNotIn(key, crawler.failures)
+
+    def test_success_clears_failures(self):
+        crawler = make_crawler()
+        key = "8.8.8.8:22556"
+        crawler._record_visit(key, reachable=False)
+        crawler._record_visit(key, reachable=True)
+        self.assertTrue(crawler._is_due(key))
+        self.assertNotIn(key, crawler.failures)
+
+    def test_zero_window_disables_backoff(self):
+        crawler = make_crawler(window=0)
+        key = "8.8.8.8:22556"
+        crawler._record_visit(key, reachable=False)
+        self.assertTrue(crawler._is_due(key))
+
+
+class EnqueueTest(unittest.TestCase):
+    def setUp(self):
+        self.clock = FakeClock()
+        patcher = mock.patch.object(crawler_module.time, "time", self.clock.time)
+        patcher.start()
+        self.addCleanup(patcher.stop)
+
+    def test_dedupes_queued_and_crawled(self):
+        crawler = make_crawler()
+        self.assertTrue(crawler._enqueue("8.8.8.8", 22556))
+        self.assertFalse(crawler._enqueue("8.8.8.8", 22556))
+        self.assertTrue(crawler._enqueue("8.8.8.8", 22557))
+
+        crawler.crawled.add("1.1.1.1:22556")
+        self.assertFalse(crawler._enqueue("1.1.1.1", 22556))
+        self.assertEqual(crawler.pending, {"8.8.8.8:22556", "8.8.8.8:22557"})
+
+    def test_counts_denied_and_deferred(self):
+        crawler = make_crawler(denylist=["45.12.0.0/16"])
+        self.assertFalse(crawler._enqueue("203.0.113.9", 22556))
+        self.assertFalse(crawler._enqueue("45.12.3.4", 22556))
+
+        crawler._record_visit("9.9.9.9:22556", reachable=False)
+        self.assertFalse(crawler._enqueue("9.9.9.9", 22556))
+
+        self.assertEqual(crawler.stats["denied"], 2)
+        self.assertEqual(crawler.stats["revisits_deferred"], 1)
+        self.assertEqual(crawler.pending, set())
+
+
+if __name__ == "__main__":
+    unittest.main()
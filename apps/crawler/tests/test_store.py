@@ -0,0 +1,156 @@
+"""
+Tests for the SQLite peer store: handshake history, checkpoints of the pass
+in progress and resuming it after a restart.
+"""
+
+import asyncio
+import os
+import sqlite3
+import tempfile
+import unittest
+from collections import deque
+from datetime import datetime, timedelta, timezone
+from unittest import mock
+
+from src.crawler import NodeInfo
+from src.store import PeerStore, main
+from tests.test_scheduling import make_crawler
+
+
+def node(ip, status="up", user_agent="/Dingocoin:1.18.0/", seen=None):
+    seen = seen or datetime.now(timezone.utc)
+    up = status == "up"
+    return NodeInfo(
+        ip=ip,
+        port=33117,
+        version=user_agent if up else None,
+        user_agent=user_agent if up else None,
+        protocol_version=70144 if up else None,
+        start_height=500000 if up else None,
+        latency_ms=42.0 if up else None,
+        status=status,
+        first_seen=seen,
+        last_seen=seen,
+    )
+
+
+class PeerStoreTest(unittest.TestCase):
+    def setUp(self):
+        self.dir = tempfile.TemporaryDirectory()
+        self.path = os.path.join(self.dir.name, "crawler", "peers.db")
+        self.store = PeerStore(self.path)
+
+    def tearDown(self):
+        self.store.close()
+        self.dir.cleanup()
+
+    def test_begin_pass_resumes_unfinished(self):
+        first, resumed = self.store.begin_pass()
+        self.assertFalse(resumed)
+        self.assertEqual(self.store.begin_pass(), (first, True))
+
+        self.store.finish_pass(first, total_nodes=0, reachable_nodes=0)
+        second, resumed = self.store.begin_pass()
+        self.assertNotEqual(second, first)
+        self.assertFalse(resumed)
+
+    def test_checkpoint(self):
+        pass_id, _ = self.store.begin_pass()
+        self.store.record_handshake(pass_id, node("8.8.8.8"))
+        failures = {"9.9.9.9:33117": deque([100.0, 200.0])}
+        self.store.checkpoint(pass_id, {"1.1.1.1:33117", "2a01:4f8::1:33117"}, failures)
+
+        # A new connection sees only what was committed
+        reopened = PeerStore(self.path)
+        self.assertEqual(set(reopened.pending(pass_id)), {"1.1.1.1:33117", "2a01:4f8::1:33117"})
+        self.assertEqual(reopened.load_failures(), failures)
+        visited = reopened.visited(pass_id)
+        self.assertEqual([(r["ip"], r["status"]) for r in visited], [("8.8.8.8", "up")])
+        reopened.close()
+
+        # Finishing a pass drops its queue
+        self.store.finish_pass(pass_id, total_nodes=1, reachable_nodes=1)
+        self.assertEqual(self.store.pending(pass_id), [])
+
+    def test_reachability_keeps_last_user_agent(self):
+        start = datetime(2026, 10, 1, tzinfo=timezone.utc)
+        for i, status in enumerate(["up", "down", "up", "up"]):
+            pass_id, _ = self.store.begin_pass()
+            self.store.record_handshake(pass_id, node("8.8.8.8", status, seen=start + timedelta(hours=i)))
+            self.store.finish_pass(pass_id, total_nodes=1, reachable_nodes=int(status == "up"))
+
+        [peer] = self.store.reachability()
+        self.assertEqual(peer["checks"], 4)
+        self.assertEqual(peer["up_checks"], 3)
+        self.assertEqual(peer["uptime"], 0.75)
+        self.assertEqual(peer["first_seen"], start.isoformat())
+        self.assertEqual(peer["last_status"], "up")
+        # A failed handshake doesn't forget the user agent
+        self.assertEqual(peer["user_agent"], "/Dingocoin:1.18.0/")
+
+        [recent] = self.store.reachability(since=(start + timedelta(hours=2)).isoformat())
+        self.assertEqual((recent["checks"], recent["uptime"]), (2, 1.0))
+        self.assertEqual(len(self.store.passes()), 4)
+
+    def test_snapshot(self):
+        pass_id, _ = self.store.begin_pass()
+        self.store.record_handshake(pass_id, node("8.8.8.8"))
+        self.store.finish_pass(pass_id, total_nodes=1, reachable_nodes=1)
+
+        dest = os.path.join(self.dir.name, "snapshot.db")
+        self.assertEqual(main(["--db", self.path, "snapshot", dest]), 0)
+        copy = sqlite3.connect(dest)
+        self.assertEqual(copy.execute("SELECT COUNT(*) FROM handshakes").fetchone()[0], 1)
+        copy.close()
+
+
+class ResumeTest(unittest.TestCase):
+    def test_restart_resumes_pass(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            path = os.path.join(tmp, "peers.db")
+
+            # The first run visits one node and is killed after the checkpoint
+            crawler = make_crawler(store_path=path)
+            crawler._pass_id, _ = crawler.store.begin_pass()
+            crawler.nodes["8.8.8.8:33117"] = node("8.8.8.8")
+            crawler._store_visit("8.8.8.8:33117")
+            crawler.pending = {"1.1.1.1:33117"}
+            crawler._record_visit("9.9.9.9:33117", reachable=False)
+            crawler._checkpoint()
+            crawler.store.close()
+
+            restarted = make_crawler(store_path=path)
+            self.assertIn("9.9.9.9:33117", restarted.failures)
+            restarted.db.get_all_nodes = mock.AsyncMock(return_value=[])
+            restarted._seed_from_rpc = mock.AsyncMock()
+            restarted._mark_rpc_node_as_up = mock.AsyncMock()
+            restarted._seed_from_dns = mock.AsyncMock()
+            restarted._seed_from_config = mock.AsyncMock()
+            restarted._fetch_current_version = mock.AsyncMock()
+            restarted._save_to_database = mock.AsyncMock()
+            restarted.db.create_network_snapshot = mock.AsyncMock()
+            restarted.db.prune_stale_nodes = mock.AsyncMock()
+            crawled = []
+
+            async def crawl(ip, port):
+                key = restarted._node_key(ip, port)
+                crawled.append(key)
+                restarted.pending.discard(key)
+                restarted.crawled.add(key)
+                restarted.nodes[key] = node(ip, "down")
+                restarted._store_visit(key)
+
+            restarted._crawl_node = crawl
+            asyncio.run(restarted.run_single_pass())
+
+            # The visited node is kept, only the queue is crawled
+            self.assertEqual(crawled, ["1.1.1.1:33117"])
+            self.assertEqual(restarted.nodes["8.8.8.8:33117"].status, "up")
+            self.assertEqual(restarted.nodes["8.8.8.8:33117"].user_agent, "/Dingocoin:1.18.0/")
+            [finished] = restarted.store.passes()
+            self.assertEqual((finished["total_nodes"], finished["reachable_nodes"]), (2, 1))
+            restarted.store.close()
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,95 @@
+/**
+ * Admin Badge Tokens API
+ *
+ * GET  - List issued badge tokens (hashes are never returned)
+ * POST - Issue a sponsorship/community badge token; the raw token is only
+ *        returned once, for the admin to hand to the node operator
+ */
+
+import { NextRequest, NextResponse } from 'next/server';
+import { createClient, createAdminClient } from '@/lib/supabase/server';
+import { isUserAdmin, logAdminAction } from '@/lib/security';
+import { issueBadgeTokenSchema } from '@/lib/validations';
+import { generateBadgeToken, hashBadgeToken } from '@/lib/node-badges';
+
+export const dynamic = 'force-dynamic';
+
+// GET /api/admin/badges - List badge tokens
+export async function GET() {
+  const supabase = await createClient();
+
+  // Check authentication
+  const { data: { user }, error: authError } = await supabase.auth.getUser();
+  if (authError || !user) {
+    return NextResponse.json({ error: 'Unauthorized' }, { status: 401 });
+  }
+
+  // Check admin privileges
+  const isAdmin = await isUserAdmin(user.id);
+  if (!isAdmin) {
+    return NextResponse.json({ error: 'Admin privileges required' }, { status: 403 });
+  }
+
+  const adminClient = createAdminClient();
+  const { data: tokens, error } = await adminClient
+    .from('node_badge_tokens')
+    .select('id, kind, label, expires_at, issued_by, node_id, redeemed_by, redeemed_at, created_at')
+    .order('created_at', { ascending: false });
+
+  if (error) {
+    console.error('Admin badges GET error:', error);
+    return NextResponse.json({ error: 'Failed to fetch badge tokens' }, { status: 500 });
+  }
+
+  return NextResponse.json({ tokens: tokens || [] });
+}
+
+// POST /api/admin/badges - Issue a badge token
+export async function POST(request: NextRequest) {
+  const supabase = await createClient();
+
+  // Check authentication
+  const { data: { user }, error: authError } = await supabase.auth.getUser();
+  if (authError || !user) {
+    return NextResponse.json({ error: 'Unauthorized' }, { status: 401 });
+  }
+
+  // Check admin privileges
+  const isAdmin = await isUserAdmin(user.id);
+  if (!isAdmin) {
+    return NextResponse.json({ error: 'Admin privileges required' }, { status: 403 });
+  }
+
+  const validation = issueBadgeTokenSchema.safeParse(await request.json());
+  if (!validation.success) {
+    const errors = validation.error.errors.map(e => `${e.path.join('.')}: ${e.message}`).join(', ');
+    return NextResponse.json({ error: `Validation failed: ${errors}` }, { status: 400 });
+  }
+
+  const { kind, label, validDays } = validation.data;
+  const expiresAt = new Date(Date.now() + validDays * 24 * 60 * 60 * 1000);
+  const token = generateBadgeToken(kind, expiresAt);
+
+  const adminClient = createAdminClient();
+  const { data: issued, error } = await adminClient
+    .from('node_badge_tokens')
+    .insert({
+      token_hash: await hashBadgeToken(token),
+      kind,
+      label: label ?? null,
+      // Match the expiry encoded in the token (whole seconds)
+      expires_at: new Date(Math.floor(expiresAt.getTime() / 1000) * 1000).toISOString(),
+      issued_by: user.id,
+    })
+    .select('id, kind, label, expires_at')
+    .single();
+
+  if (error || !issued) {
+    console.error('Admin badges POST error:', error);
+    return NextResponse.json({ error: 'Failed to issue badge token' }, { status: 500 });
+  }
+
+  await logAdminAction(user.id, 'issue_badge_token', 'node_badge_tokens', issued.id, { kind, label, validDays }, request);
+
+  return NextResponse.json({ success: true, token, badge: issued });
+}
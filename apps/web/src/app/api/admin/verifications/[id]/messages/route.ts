@@ -0,0 +1,109 @@
+/**
+ * Admin Verification Messages API
+ *
+ * GET  - Follow-up questions and operator answers on a verification
+ * POST - Ask the operator a follow-up question; they see it when polling
+ *        with `verify questions <challenge>`
+ */
+
+import { NextRequest, NextResponse } from 'next/server';
+import { createClient, createAdminClient } from '@/lib/supabase/server';
+import { isUserAdmin, logAdminAction } from '@/lib/security';
+import { verificationQuestionSchema } from '@/lib/validations';
+import { VerificationStatus } from '@/lib/verification';
+import { listVerificationMessages } from '@/lib/verification-messages';
+
+export const dynamic = 'force-dynamic';
+
+interface RouteParams {
+  params: Promise<{
+    id: string
+  }>
+}
+
+// GET /api/admin/verifications/<id>/messages - List messages
+export async function GET(request: NextRequest, { params }: RouteParams) {
+  const { id } = await params;
+  const supabase = await createClient();
+
+  // Check authentication
+  const { data: { user }, error: authError } = await supabase.auth.getUser();
+  if (authError || !user) {
+    return NextResponse.json({ error: 'Unauthorized' }, { status: 401 });
+  }
+
+  // Check admin privileges
+  const isAdmin = await isUserAdmin(user.id);
+  if (!isAdmin) {
+    return NextResponse.json({ error: 'Admin privileges required' }, { status: 403 });
+  }
+
+  try {
+    const messages = await listVerificationMessages(createAdminClient(), id);
+    return NextResponse.json({ messages });
+  } catch (error) {
+    console.error('Admin verification messages GET error:', error);
+    return NextResponse.json({ error: 'Failed to fetch messages' }, { status: 500 });
+  }
+}
+
+// POST /api/admin/verifications/<id>/messages - Ask a question
+export async function POST(request: NextRequest, { params }: RouteParams) {
+  const { id } = await params;
+  const supabase = await createClient();
+
+  // Check authentication
+  const { data: { user }, error: authError } = await supabase.auth.getUser();
+  if (authError || !user) {
+    return NextResponse.json({ error: 'Unauthorized' }, { status: 401 });
+  }
+
+  // Check admin privileges
+  const isAdmin = await isUserAdmin(user.id);
+  if (!isAdmin) {
+    return NextResponse.json({ error: 'Admin privileges required' }, { status: 403 });
+  }
+
+  const validation = verificationQuestionSchema.safeParse(await request.json());
+  if (!validation.success) {
+    const errors = validation.error.errors.map(e => `${e.path.join('.')}: ${e.message}`).join(', ');
+    return NextResponse.json({ error: `Validation failed: ${errors}` }, { status: 400 });
+  }
+
+  const adminClient = createAdminClient();
+  const { data: verification } = await adminClient
+    .from('verifications')
+    .select('id, status')
+    .eq('id', id)
+    .single();
+
+  if (!verification) {
+    return NextResponse.json({ error: 'Verification not found' }, { status: 404 });
+  }
+  if (verification.status !== VerificationStatus.PENDING_APPROVAL) {
+    return NextResponse.json(
+      { error: `Questions can only be asked while a verification awaits approval (status: ${verification.status})` },
+      { status: 409 }
+    );
+  }
+
+  const { data: question, error } = await adminClient
+    .from('verification_messages')
+    .insert({
+      verification_id: id,
+      author: 'admin',
+      body: validation.data.body,
+      created_by: user.id,
+    })
+    .select('id')
+    .single();
+
+  if (error || !question) {
+    console.error('Admin verification messages POST error:', error);
+    return NextResponse.json({ error: 'Failed to store question' }, { status: 500 });
+  }
+
+  await logAdminAction(user.id, 'ask_verification_question', 'verification_messages', question.id, { verificationId: id }, request);
+
+  return NextResponse.json({ success: true, id: question.id });
+}
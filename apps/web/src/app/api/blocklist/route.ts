@@ -0,0 +1,24 @@
+/**
+ * Community Blocklist API (Public)
+ *
+ * GET - Returns the crawler denylist for `verify peers`, signed when
+ * BLOCKLIST_SIGNING_KEY is set (see lib/blocklist.ts)
+ */
+
+import { NextResponse } from 'next/server';
+import { buildBlocklist } from '@/lib/blocklist';
+
+export const dynamic = 'force-dynamic';
+
+// GET /api/blocklist - Get the signed community blocklist
+export async function GET() {
+  try {
+    return NextResponse.json(buildBlocklist());
+  } catch (error) {
+    console.error('Blocklist API error:', error);
+    return NextResponse.json(
+      { error: 'Failed to build blocklist' },
+      { status: 500 }
+    );
+  }
+}
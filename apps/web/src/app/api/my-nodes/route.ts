@@ -45,6 +45,7 @@ function transformNode(dbNode: any): NodeWithProfile {
     lastSeen: dbNode.last_seen,
     firstSeen: dbNode.first_seen || new Date().toISOString(),
     timesSeen: dbNode.times_seen || 0,
+    retiringAt: dbNode.retiring_at || null,
     latencyMs: dbNode.latency_ms,
     latencyAvg: dbNode.latency_avg,
     uptime: dbNode.uptime || 0,
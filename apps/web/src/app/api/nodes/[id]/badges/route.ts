@@ -0,0 +1,93 @@
+import { NextRequest, NextResponse } from 'next/server'
+import { createAdminClient } from '@/lib/supabase/server'
+import { withNodeOwnerAuth } from '@/lib/api-middleware'
+import { attachBadgeSchema } from '@/lib/validations'
+import { hashBadgeToken } from '@/lib/node-badges'
+
+interface RouteParams {
+  params: Promise<{
+    id: string
+  }>
+}
+
+/**
+ * Active (unexpired) badges of a node
+ */
+export async function GET(
+  request: NextRequest,
+  { params }: RouteParams
+) {
+  const { id: nodeId } = await params
+  const adminClient = createAdminClient()
+
+  const { data: badges, error } = await adminClient
+    .from('node_badge_tokens')
+    .select('kind, label, expires_at, redeemed_at')
+    .eq('node_id', nodeId)
+    .gt('expires_at', new Date().toISOString())
+    .order('redeemed_at', { ascending: true })
+
+  if (error) {
+    return NextResponse.json(
+      { error: 'Failed to fetch badges' },
+      { status: 500 }
+    )
+  }
+
+  return NextResponse.json({ badges: badges || [] })
+}
+
+/**
+ * Attach an admin-issued badge token to the node (verify attach-badge)
+ * Requires an API key with write:nodes owned by the node's verifier
+ */
+export async function POST(
+  request: NextRequest,
+  { params }: RouteParams
+) {
+  const { id: nodeId } = await params
+
+  return withNodeOwnerAuth(request, nodeId, async (ctx) => {
+    const validation = attachBadgeSchema.safeParse(await request.json())
+    if (!validation.success) {
+      const errors = validation.error.errors.map(e => `${e.path.join('.')}: ${e.message}`).join(', ')
+      return NextResponse.json(
+        { success: false, error: `Validation failed: ${errors}`, code: 'VALIDATION_ERROR' },
+        { status: 400 }
+      )
+    }
+
+    const adminClient = createAdminClient()
+    const tokenHash = await hashBadgeToken(validation.data.token)
+
+    // Redeem in one statement so a token can't be attached twice
+    const { data: badge, error } = await adminClient
+      .from('node_badge_tokens')
+      .update({
+        node_id: nodeId,
+        redeemed_by: ctx.userId,
+        redeemed_at: new Date().toISOString(),
+      })
+      .eq('token_hash', tokenHash)
+      .is('redeemed_at', null)
+      .gt('expires_at', new Date().toISOString())
+      .select('kind, label, expires_at, redeemed_at')
+      .maybeSingle()
+
+    if (error) {
+      return NextResponse.json(
+        { success: false, error: 'Failed to attach badge' },
+        { status: 500 }
+      )
+    }
+
+    if (!badge) {
+      return NextResponse.json(
+        { success: false, error: 'Badge token is unknown, expired or already used', code: 'BADGE_TOKEN_INVALID' },
+        { status: 404 }
+      )
+    }
+
+    return NextResponse.json({ success: true, badge })
+  })
+}
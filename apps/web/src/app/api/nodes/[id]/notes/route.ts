@@ -0,0 +1,92 @@
+import { NextRequest, NextResponse } from 'next/server'
+import { createClient, createAdminClient } from '@/lib/supabase/server'
+import { withNodeOwnerAuth } from '@/lib/api-middleware'
+import { isUserAdmin } from '@/lib/security'
+import { nodeNoteSchema } from '@/lib/validations'
+
+interface RouteParams {
+  params: Promise<{
+    id: string
+  }>
+}
+
+// Notes returned per request
+const NOTES_LIMIT = 20
+
+/**
+ * Recent operator notes of a node
+ * Public notes for everyone; admins also see private notes
+ */
+export async function GET(
+  request: NextRequest,
+  { params }: RouteParams
+) {
+  const { id: nodeId } = await params
+  const supabase = await createClient()
+
+  const { data: { user } } = await supabase.auth.getUser()
+  const userIsAdmin = user ? await isUserAdmin(user.id) : false
+
+  let query = createAdminClient()
+    .from('node_operator_notes')
+    .select('id, body, is_public, created_at')
+    .eq('node_id', nodeId)
+    .order('created_at', { ascending: false })
+    .limit(NOTES_LIMIT)
+  if (!userIsAdmin) {
+    query = query.eq('is_public', true)
+  }
+
+  const { data: notes, error } = await query
+
+  if (error) {
+    return NextResponse.json(
+      { error: 'Failed to fetch notes' },
+      { status: 500 }
+    )
+  }
+
+  return NextResponse.json({ notes: notes || [] })
+}
+
+/**
+ * Attach an operator note to the node (verify note)
+ * Requires an API key with write:nodes owned by the node's verifier
+ */
+export async function POST(
+  request: NextRequest,
+  { params }: RouteParams
+) {
+  const { id: nodeId } = await params
+
+  return withNodeOwnerAuth(request, nodeId, async (ctx) => {
+    const validation = nodeNoteSchema.safeParse(await request.json())
+    if (!validation.success) {
+      const errors = validation.error.errors.map(e => `${e.path.join('.')}: ${e.message}`).join(', ')
+      return NextResponse.json(
+        { success: false, error: `Validation failed: ${errors}`, code: 'VALIDATION_ERROR' },
+        { status: 400 }
+      )
+    }
+
+    const { data: note, error } = await createAdminClient()
+      .from('node_operator_notes')
+      .insert({
+        node_id: nodeId,
+        user_id: ctx.userId,
+        body: validation.data.note,
+        is_public: validation.data.public,
+      })
+      .select('id, body, is_public, created_at')
+      .single()
+
+    if (error || !note) {
+      return NextResponse.json(
+        { success: false, error: 'Failed to save note' },
+        { status: 500 }
+      )
+    }
+
+    return NextResponse.json({ success: true, note })
+  })
+}
@@ -0,0 +1,92 @@
+import { NextRequest, NextResponse } from 'next/server'
+import { createAdminClient } from '@/lib/supabase/server'
+import { withNodeOwnerAuth } from '@/lib/api-middleware'
+import { retireNodeSchema } from '@/lib/validations'
+
+interface RouteParams {
+  params: Promise<{
+    id: string
+  }>
+}
+
+// Furthest a shutdown can be announced in advance
+const MAX_NOTICE_DAYS = 365
+
+/**
+ * Announce a planned shutdown (verify retire --at YYYY-MM-DD)
+ *
+ * The node shows as retiring until 00:00 UTC on that date. Afterwards the
+ * crawler stops recording snapshots, so its uptime ends at the shutdown.
+ * Requires an API key with write:nodes owned by the node's verifier.
+ */
+export async function POST(
+  request: NextRequest,
+  { params }: RouteParams
+) {
+  const { id: nodeId } = await params
+
+  return withNodeOwnerAuth(request, nodeId, async () => {
+    const validation = retireNodeSchema.safeParse(await request.json())
+    if (!validation.success) {
+      const errors = validation.error.errors.map(e => `${e.path.join('.')}: ${e.message}`).join(', ')
+      return NextResponse.json(
+        { success: false, error: `Validation failed: ${errors}`, code: 'VALIDATION_ERROR' },
+        { status: 400 }
+      )
+    }
+
+    const retiringAt = new Date(`${validation.data.at}T00:00:00Z`)
+    const days = (retiringAt.getTime() - Date.now()) / (24 * 60 * 60 * 1000)
+    if (days <= 0 || days > MAX_NOTICE_DAYS) {
+      return NextResponse.json(
+        { success: false, error: `Shutdown date must be in the next ${MAX_NOTICE_DAYS} days`, code: 'INVALID_RETIRE_DATE' },
+        { status: 400 }
+      )
+    }
+
+    const adminClient = createAdminClient()
+    const { error } = await adminClient
+      .from('nodes')
+      .update({
+        retiring_at: retiringAt.toISOString(),
+        retirement_scheduled_at: new Date().toISOString(),
+      })
+      .eq('id', nodeId)
+
+    if (error) {
+      return NextResponse.json(
+        { success: false, error: 'Failed to schedule shutdown' },
+        { status: 500 }
+      )
+    }
+
+    return NextResponse.json({ success: true, retiringAt: retiringAt.toISOString() })
+  })
+}
+
+/**
+ * Cancel a planned shutdown (verify retire --cancel)
+ */
+export async function DELETE(
+  request: NextRequest,
+  { params }: RouteParams
+) {
+  const { id: nodeId } = await params
+
+  return withNodeOwnerAuth(request, nodeId, async () => {
+    const adminClient = createAdminClient()
+    const { error } = await adminClient
+      .from('nodes')
+      .update({ retiring_at: null, retirement_scheduled_at: null })
+      .eq('id', nodeId)
+
+    if (error) {
+      return NextResponse.json(
+        { success: false, error: 'Failed to cancel shutdown' },
+        { status: 500 }
+      )
+    }
+
+    return NextResponse.json({ success: true })
+  })
+}
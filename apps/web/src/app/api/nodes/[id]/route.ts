@@ -39,6 +39,7 @@ function transformNode(dbNode: any): NodeWithProfile {
     lastSeen: dbNode.last_seen,
     firstSeen: dbNode.first_seen || new Date().toISOString(),
     timesSeen: dbNode.times_seen || 0,
+    retiringAt: dbNode.retiring_at || null,
     latencyMs: dbNode.latency_ms,
     latencyAvg: dbNode.latency_avg,
     uptime: dbNode.uptime_percentage || dbNode.uptime || 0,
@@ -65,7 +66,8 @@ function transformNode(dbNode: any): NodeWithProfile {
  * Single node details API endpoint
  *
  * Returns detailed information about a specific node including
- * profile data and 30-day uptime history.
+ * profile data, 30-day uptime history, active badges and the latest
+ * public operator note.
  *
  * @param {NextRequest} request - The request object
  * @param {Object} params - Route parameters
@@ -113,9 +115,28 @@ export async function GET(
     .gte('snapshot_time', thirtyDaysAgo.toISOString())
     .order('snapshot_time', { ascending: true })
 
+  // Sponsorship/community badges (node_badge_tokens is service-role only)
+  const { data: badges } = await createAdminClient()
+    .from('node_badge_tokens')
+    .select('kind, label, expires_at')
+    .eq('node_id', id)
+    .gt('expires_at', new Date().toISOString())
+
+  // Latest public operator note (verify note --public)
+  const { data: operatorNote } = await supabase
+    .from('node_operator_notes')
+    .select('body, created_at')
+    .eq('node_id', id)
+    .eq('is_public', true)
+    .order('created_at', { ascending: false })
+    .limit(1)
+    .maybeSingle()
+
   return NextResponse.json({
     node,
-    uptimeHistory: uptimeHistory || []
+    uptimeHistory: uptimeHistory || [],
+    badges: badges || [],
+    operatorNote: operatorNote || null
   })
 }
 
@@ -0,0 +1,76 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { createClient } from '@/lib/supabase/server';
+
+const MAX_PERIOD_DAYS = 365;
+
+/**
+ * Uptime of a node over a period, counted in the database. node_snapshots
+ * holds one row per crawl, so fetching the rows (as /snapshots does) hits
+ * the API row limit after a few days.
+ *
+ * GET /api/nodes/<id>/uptime?period=30
+ * → { nodeId, periodDays, total, online, uptime } (uptime is null without data)
+ *
+ * @param {string} params.id - Node UUID
+ */
+export async function GET(
+  request: NextRequest,
+  { params }: { params: Promise<{ id: string }> }
+) {
+  const { id } = await params;
+  const period = Number(request.nextUrl.searchParams.get('period') || '30');
+
+  if (!Number.isInteger(period) || period < 1 || period > MAX_PERIOD_DAYS) {
+    return NextResponse.json(
+      { error: `period must be a whole number of days between 1 and ${MAX_PERIOD_DAYS}` },
+      { status: 400 }
+    );
+  }
+
+  try {
+    const supabase = await createClient();
+    const since = new Date(Date.now() - period * 24 * 60 * 60 * 1000).toISOString();
+
+    const countSnapshots = () =>
+      supabase
+        .from('node_snapshots')
+        .select('id', { count: 'exact', head: true })
+        .eq('node_id', id)
+        .gte('snapshot_time', since);
+
+    const [all, online] = await Promise.all([
+      countSnapshots(),
+      countSnapshots().eq('is_online', true),
+    ]);
+
+    const error = all.error || online.error;
+    if (error) {
+      console.error('Supabase error:', error);
+      return NextResponse.json({ error: error.message }, { status: 500 });
+    }
+
+    const total = all.count ?? 0;
+    const onlineCount = online.count ?? 0;
+
+    return NextResponse.json(
+      {
+        nodeId: id,
+        periodDays: period,
+        total,
+        online: onlineCount,
+        uptime: total > 0 ? (onlineCount * 100) / total : null,
+      },
+      {
+        headers: {
+          'Cache-Control': 'public, s-maxage=300, stale-while-revalidate=600',
+        },
+      }
+    );
+  } catch (error) {
+    console.error('Error counting snapshots:', error);
+    return NextResponse.json(
+      { error: 'Failed to compute uptime' },
+      { status: 500 }
+    );
+  }
+}
@@ -0,0 +1,61 @@
+/**
+ * Verify Capabilities API (Public)
+ *
+ * GET - Tells the verify binary what this backend supports: its API version,
+ * the oldest binary it accepts, the endpoints the binary uses and which
+ * optional features are enabled. `verify handshake` prints it, so fork
+ * maintainers can check a new backend against their binaries.
+ */
+
+import { NextResponse } from 'next/server';
+import { getChainConfig } from '@/config';
+import { getVerificationConfig } from '@/lib/feature-flags.server';
+
+export const dynamic = 'force-dynamic';
+
+// Bumped when a request or response shape changes incompatibly
+const VERIFY_API_VERSION = 1;
+
+// Oldest binary that speaks VERIFY_API_VERSION; forks can raise it
+const MIN_CLIENT_VERSION = process.env.VERIFY_MIN_CLIENT_VERSION || '2.0.0';
+
+const ENDPOINTS = [
+  '/api/verify-node/init',
+  '/api/verify-node/confirm',
+  '/api/verify-node/confirm/[token]',
+  '/api/verify-node/conversation',
+  '/api/verify-node/pair',
+  '/api/verify-node/pair/[token]',
+  '/api/blocklist',
+  '/api/nodes/[id]/badges',
+  '/api/nodes/[id]/notes',
+  '/api/nodes/[id]/retire',
+  '/api/nodes/[id]/uptime',
+];
+
+// GET /api/verify-node/capabilities - Negotiate with the verify binary
+export async function GET() {
+  try {
+    return NextResponse.json({
+      apiVersion: VERIFY_API_VERSION,
+      chain: getChainConfig().name,
+      minClientVersion: MIN_CLIENT_VERSION,
+      endpoints: ENDPOINTS,
+      features: {
+        reachabilityProof: true,
+        confirmPolling: true,
+        pairing: true,
+        alternatePorts: getVerificationConfig().allowAlternatePorts,
+        payloadEncryption: Boolean(process.env.CONFIRM_PAYLOAD_KEY),
+        signedBlocklist: Boolean(process.env.BLOCKLIST_SIGNING_KEY),
+        signedReceipts: Boolean(process.env.RECEIPT_SIGNING_KEY),
+      },
+    });
+  } catch (error) {
+    console.error('Verify capabilities API error:', error);
+    return NextResponse.json(
+      { error: 'Failed to build capabilities' },
+      { status: 500 }
+    );
+  }
+}
@@ -0,0 +1,84 @@
+import { createAdminClient } from '@/lib/supabase/server'
+import { NextRequest, NextResponse } from 'next/server'
+import { rateLimit, RATE_LIMITS } from '@/lib/security'
+
+interface RouteParams {
+  params: Promise<{
+    token: string
+  }>
+}
+
+/**
+ * Poll for a confirm whose server-side checks are still running
+ *
+ * Called by the Go binary with the poll token from a 202 confirm response.
+ * Answers 202 while the checks run, then the stored confirm response with
+ * its original status code.
+ *
+ * @param {NextRequest} request - The request object
+ * @returns {Promise<NextResponse>} Confirm result or processing status
+ */
+export async function GET(
+  request: NextRequest,
+  { params }: RouteParams
+) {
+  try {
+    const rateLimitResult = await rateLimit(request, 'verify-node:confirm-poll', RATE_LIMITS.VERIFY_CONFIRM_POLL);
+    if (!rateLimitResult.allowed) {
+      return NextResponse.json(
+        {
+          success: false,
+          error: 'Too many requests. Please poll less often.',
+          code: 'RATE_LIMIT_EXCEEDED'
+        },
+        { status: 429 }
+      );
+    }
+
+    const { token } = await params;
+    if (!/^[0-9a-f]{32}$/.test(token)) {
+      return NextResponse.json(
+        { success: false, error: 'Invalid poll token', code: 'VALIDATION_ERROR' },
+        { status: 400 }
+      );
+    }
+
+    const supabase = createAdminClient();
+    const { data: verification, error } = await supabase
+      .from('verifications')
+      .select('confirm_result')
+      .eq('poll_token', token)
+      .single();
+
+    if (error || !verification) {
+      return NextResponse.json(
+        { success: false, error: 'Unknown poll token', code: 'POLL_TOKEN_NOT_FOUND' },
+        { status: 404 }
+      );
+    }
+
+    const result = verification.confirm_result as { status: number; body: Record<string, unknown> } | null;
+    if (!result) {
+      return NextResponse.json(
+        {
+          success: true,
+          status: 'processing',
+          message: 'The map is still checking your node.',
+        },
+        { status: 202 }
+      );
+    }
+
+    return NextResponse.json(result.body, { status: result.status });
+  } catch (err) {
+    console.error('[VerifyNode:ConfirmPoll] Unexpected error:', err);
+    return NextResponse.json(
+      {
+        success: false,
+        error: 'An unexpected error occurred. Please try again later.',
+        code: 'INTERNAL_ERROR'
+      },
+      { status: 500 }
+    );
+  }
+}
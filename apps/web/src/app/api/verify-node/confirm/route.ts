@@ -1,8 +1,15 @@
+import { randomBytes } from 'crypto'
 import { createAdminClient } from '@/lib/supabase/server'
 import { NextRequest, NextResponse } from 'next/server'
-import { verifyNodeConfirmSchema } from '@/lib/validations'
+import { verifyNodeConfirmSchema, sealedPayloadSchema } from '@/lib/validations'
+import { openSealedPayload } from '@/lib/payload-encryption'
 import { rateLimit, RATE_LIMITS } from '@/lib/security'
-import { VerificationStatus } from '@/lib/verification'
+import { VerificationStatus, userAgentToken, verifyMessageSignature } from '@/lib/verification'
+import { probeUserAgent, probeAddrRelay, type AddrRelayResult } from '@/lib/p2p-probe'
+import { type ReachabilityProbeOutcome } from '@/lib/reachability-probe'
+import { buildReceipt } from '@/lib/receipt'
+import { getChainConfig } from '@/config'
+import { getVerificationConfig } from '@/lib/feature-flags.server'
 
 /**
  * Confirm node verification (Step 2 of 2)
@@ -13,10 +20,68 @@ import { VerificationStatus } from '@/lib/verification'
  * 2. Port check passed (port listening)
  * 3. Request IP matches init IP (prevents IP spoofing between steps)
  * 4. Request IP matches node IP in crawler DB (proves node ownership)
+ * 5. Optional: node advertises the challenge token in its P2P user agent
+ * 6. With verify --strict: at least one cryptographic proof holds up (RPC,
+ *    a signmessage signature or the user agent handshake). Such
+ *    verifications are marked strictVerified for a higher trust tier.
+ *
+ * Also records whether the node relays addresses (getaddr). This is
+ * informational for admins and never fails the verification.
+ *
+ * Clients that send acceptsPolling get 202 with a poll token when the
+ * map's own probes outlast CONFIRM_SYNC_BUDGET_MS; the checks finish in the
+ * background and the response is served by confirm/[token].
  *
  * @param {NextRequest} request - The request object
  * @returns {Promise<NextResponse>} Verification result
  */
+/**
+ * How long confirm waits for the probe outcome. The binary only confirms
+ * after its own listener was probed or timed out, so the outcome is
+ * normally written already. Together with the user agent probe this stays
+ * well under the binary's 30s HTTP timeout.
+ */
+const REACHABILITY_WAIT_SECONDS = 8;
+
+/**
+ * How long a polling client's confirm waits for the map's own probes
+ * before answering 202 with a poll token
+ */
+const CONFIRM_SYNC_BUDGET_MS = 15000;
+
+/** Final confirm response, sent directly or stored for a polling client */
+interface ConfirmOutcome {
+  status: number;
+  body: Record<string, unknown>;
+}
+
+/**
+ * Wait for the background reachability probe started by init to record its
+ * outcome. The probe itself ends REACHABILITY_TIMEOUT_SECONDS after init.
+ */
+async function awaitReachabilityOutcome(
+  supabase: ReturnType<typeof createAdminClient>,
+  verificationId: string
+): Promise<ReachabilityProbeOutcome | undefined> {
+  const deadline = Date.now() + REACHABILITY_WAIT_SECONDS * 1000;
+
+  while (Date.now() < deadline) {
+    const { data } = await supabase
+      .from('verifications')
+      .select('metadata')
+      .eq('id', verificationId)
+      .single();
+
+    const outcome = (data?.metadata as { reachabilityProbe?: ReachabilityProbeOutcome } | null)?.reachabilityProbe;
+    if (outcome && 'probed' in outcome) {
+      return outcome;
+    }
+    await new Promise((resolve) => setTimeout(resolve, 1000));
+  }
+
+  return undefined;
+}
+
 export async function POST(request: NextRequest) {
   try {
     // Rate limit confirm requests (10/hour per IP)
@@ -32,7 +97,29 @@ export async function POST(request: NextRequest) {
       );
     }
 
-    const body = await request.json();
+    let body = await request.json();
+
+    // verify --encrypt-payload seals the body to CONFIRM_PAYLOAD_KEY
+    const payloadEncrypted = typeof body === 'object' && body !== null && 'sealed' in body;
+    if (payloadEncrypted) {
+      const sealed = sealedPayloadSchema.safeParse(body.sealed);
+      try {
+        if (!sealed.success) {
+          throw new Error(sealed.error.message);
+        }
+        body = JSON.parse(openSealedPayload(sealed.data));
+      } catch (err) {
+        console.warn('[VerifyNode:Confirm] Failed to open sealed payload:', err);
+        return NextResponse.json(
+          {
+            success: false,
+            error: 'Could not decrypt the verification payload. Download the latest verify tool and try again.',
+            code: 'DECRYPTION_FAILED'
+          },
+          { status: 400 }
+        );
+      }
+    }
 
     // Validate request body
     const validation = verifyNodeConfirmSchema.safeParse(body);
@@ -49,7 +136,13 @@ export async function POST(request: NextRequest) {
       );
     }
 
-    const { challenge, processCheck, portCheck, systemInfo } = validation.data;
+    const { challenge, processCheck, portCheck, ports, systemInfo, escalation, reachabilityProof, userAgentCheck, acceptsPolling, customChecks, strict, timings } = validation.data;
+
+    // Fields from a newer tool that this deployment does not know are
+    // dropped by validation; keep their names so admins can see them
+    const unknownFields = typeof body === 'object' && body !== null
+      ? Object.keys(body).filter(key => !(key in verifyNodeConfirmSchema.shape))
+      : [];
 
     const supabase = createAdminClient();
 
@@ -64,6 +157,7 @@ export async function POST(request: NextRequest) {
         expires_at,
         ip_address,
         method,
+        metadata,
         nodes (
           id,
           ip,
@@ -188,6 +282,26 @@ export async function POST(request: NextRequest) {
       );
     }
 
+    // The binary checked another local port (verify --port), e.g. behind a
+    // router forwarding the public port. Only maps that allow it accept
+    // that; the challenge stays usable for a run without --port.
+    if (portCheck.port !== node.port && !getVerificationConfig().allowAlternatePorts) {
+      console.warn('[VerifyNode:Confirm] Alternate port refused', {
+        verificationId: verification.id,
+        nodePort: node.port,
+        checkedPort: portCheck.port,
+      });
+
+      return NextResponse.json(
+        {
+          success: false,
+          error: `The map knows this node on port ${node.port} and does not accept checks of port ${portCheck.port}. Run the tool without --port.`,
+          code: 'PORT_MISMATCH'
+        },
+        { status: 400 }
+      );
+    }
+
     // VALIDATION #3: Process check must pass
     if (!processCheck.found) {
       console.warn('[VerifyNode:Confirm] Process check failed', {
@@ -204,6 +318,10 @@ export async function POST(request: NextRequest) {
             processCheck,
             portCheck,
             systemInfo,
+            escalation,
+            customChecks,
+            timings,
+            payloadEncrypted: payloadEncrypted || undefined,
             failureReason: 'Daemon process not found',
           }
         })
@@ -236,6 +354,10 @@ export async function POST(request: NextRequest) {
             processCheck,
             portCheck,
             systemInfo,
+            escalation,
+            customChecks,
+            timings,
+            payloadEncrypted: payloadEncrypted || undefined,
             failureReason: 'Port not listening',
           }
         })
@@ -251,71 +373,361 @@ export async function POST(request: NextRequest) {
       );
     }
 
-    // All checks passed - update to pending_approval
-    const { error: updateError } = await supabase
-      .from('verifications')
-      .update({
-        status: VerificationStatus.PENDING_APPROVAL,
-        verified_at: new Date().toISOString(),
-        metadata: {
-          processCheck,
-          portCheck,
-          systemInfo,
-          requestIp,
-        }
-      })
-      .eq('id', verification.id);
+    // The port must be held by the daemon the process check found. Older
+    // tools, and hosts where the owner isn't visible, send no owner.
+    if (portCheck.owner && !portCheck.owner.isDaemon) {
+      console.warn('[VerifyNode:Confirm] Port owned by another process', {
+        verificationId: verification.id,
+        port: portCheck.port,
+        owner: portCheck.owner,
+      });
+
+      await supabase
+        .from('verifications')
+        .update({
+          status: VerificationStatus.FAILED,
+          verified_at: new Date().toISOString(),
+          metadata: {
+            processCheck,
+            portCheck,
+            systemInfo,
+            escalation,
+            customChecks,
+            timings,
+            payloadEncrypted: payloadEncrypted || undefined,
+            failureReason: 'Port owned by another process',
+          }
+        })
+        .eq('id', verification.id);
 
-    if (updateError) {
-      console.error('[VerifyNode:Confirm] Failed to update verification:', updateError);
       return NextResponse.json(
         {
           success: false,
-          error: 'Failed to update verification status',
-          code: 'UPDATE_FAILED'
+          error: `Port ${portCheck.port} is held by ${portCheck.owner.name || 'another process'} (PID ${portCheck.owner.pid}), not by the node daemon.`,
+          code: 'PORT_OWNER_MISMATCH'
         },
-        { status: 500 }
+        { status: 400 }
       );
     }
 
-    // Add to moderation queue for admin review
-    const { error: queueError } = await supabase
-      .from('moderation_queue')
-      .insert({
-        item_type: 'verification',
-        item_id: verification.id,
-        user_id: verification.user_id,
-        status: VerificationStatus.PENDING,
-        content_data: {
-          node_id: verification.node_id,
-          method: verification.method,
-          challenge: challenge,
-          proof: 'Two-step POST verification',
-          verification_passed: true,
-          processCheck,
-          portCheck,
-          systemInfo,
+    // The map's own checks of the node and the final status update
+    const serverChecks = async (): Promise<ConfirmOutcome> => {
+      const chainConfig = getChainConfig();
+
+      // Address relay: runs alongside the remaining checks so the total stays
+      // under the binary's HTTP timeout
+      const addrRelayProbe: Promise<AddrRelayResult | undefined> = chainConfig.magicBytes
+        ? probeAddrRelay(node.ip, node.port, chainConfig.magicBytes, chainConfig.protocolVersion)
+        : Promise.resolve(undefined);
+
+      // Optional inbound reachability: the outcome our own probe recorded is
+      // authoritative, the binary's report is kept alongside for admins
+      let reachability: { client?: typeof reachabilityProof; server?: ReachabilityProbeOutcome } | undefined;
+      const probeMetadata = (verification.metadata as { reachabilityProbe?: { port: number } } | null)?.reachabilityProbe;
+      if (probeMetadata) {
+        reachability = {
+          client: reachabilityProof,
+          server: await awaitReachabilityOutcome(supabase, verification.id),
+        };
+      }
+
+      // VALIDATION #5: Optional reverse challenge via the node's user agent
+      let userAgentResult: { token: string; passed: boolean; userAgent?: string; error?: string } | undefined;
+      if (userAgentCheck) {
+        const token = userAgentToken(challenge);
+
+        if (userAgentCheck.token !== token) {
+          userAgentResult = { token, passed: false, error: 'Token does not match challenge' };
+        } else if (!chainConfig.magicBytes) {
+          userAgentResult = { token, passed: false, error: 'Chain magic bytes not configured' };
+        } else {
+          const probe = await probeUserAgent(node.ip, node.port, chainConfig.magicBytes, chainConfig.protocolVersion);
+          userAgentResult = {
+            token,
+            passed: probe.success && !!probe.userAgent?.includes(token),
+            userAgent: probe.userAgent,
+            error: probe.error,
+          };
+        }
+
+        if (!userAgentResult.passed) {
+          console.warn('[VerifyNode:Confirm] User agent check failed', {
+            verificationId: verification.id,
+            userAgentResult,
+          });
+
+          await supabase
+            .from('verifications')
+            .update({
+              status: VerificationStatus.FAILED,
+              verified_at: new Date().toISOString(),
+              metadata: {
+                processCheck,
+                portCheck,
+                systemInfo,
+                escalation,
+                customChecks,
+                timings,
+                payloadEncrypted: payloadEncrypted || undefined,
+                reachability,
+                userAgentCheck: userAgentResult,
+                failureReason: 'User agent token not found',
+              }
+            })
+            .eq('id', verification.id);
+
+          return {
+            status: 400,
+            body: {
+              success: false,
+              error: userAgentResult.userAgent
+                ? `Token ${token} not found in node user agent ${userAgentResult.userAgent}. Restart the daemon with -uacomment=${token}.`
+                : `Could not read the node's user agent: ${userAgentResult.error}`,
+              code: 'USER_AGENT_CHECK_FAILED'
+            },
+          };
+        }
+      }
+
+      // VALIDATION #6: Strict mode passes only with cryptographic evidence.
+      // The signature and the handshake are checked here; RPC access to the
+      // daemon is attested by the tool.
+      let strictResult: { methods: string[]; signature?: { address: string; valid: boolean; error?: string } } | undefined;
+      if (strict) {
+        const methods: string[] = [];
+        if (strict.rpc) {
+          methods.push('rpc');
         }
+        let signature: { address: string; valid: boolean; error?: string } | undefined;
+        if (strict.signature) {
+          const check = await verifyMessageSignature(challenge, strict.signature.address, strict.signature.signature);
+          signature = { address: strict.signature.address, valid: check.valid, error: check.error };
+          if (check.valid) {
+            methods.push('signmessage');
+          }
+        }
+        if (strict.handshake && userAgentResult?.passed) {
+          methods.push('handshake');
+        }
+        strictResult = { methods, signature };
+
+        if (methods.length === 0) {
+          console.warn('[VerifyNode:Confirm] Strict evidence failed', {
+            verificationId: verification.id,
+            strictResult,
+          });
+
+          await supabase
+            .from('verifications')
+            .update({
+              status: VerificationStatus.FAILED,
+              verified_at: new Date().toISOString(),
+              metadata: {
+                processCheck,
+                portCheck,
+                systemInfo,
+                escalation,
+                customChecks,
+                timings,
+                payloadEncrypted: payloadEncrypted || undefined,
+                reachability,
+                userAgentCheck: userAgentResult,
+                strict: strictResult,
+                failureReason: 'No cryptographic evidence in strict mode',
+              }
+            })
+            .eq('id', verification.id);
+
+          return {
+            status: 400,
+            body: {
+              success: false,
+              error: signature?.error
+                ? `Strict mode: the signature did not verify (${signature.error})`
+                : 'Strict mode: no cryptographic evidence held up',
+              code: 'STRICT_EVIDENCE_FAILED'
+            },
+          };
+        }
+      }
+
+      const addrRelay = await addrRelayProbe;
+      if (addrRelay && !addrRelay.relays) {
+        console.info('[VerifyNode:Confirm] Node does not relay addresses', {
+          verificationId: verification.id,
+          addrRelay,
+        });
+      }
+
+      // Newer tools also check the RPC port: undefined when they didn't,
+      // false for a node whose P2P side runs but whose RPC server is down
+      const rpcListening = ports?.find((p) => p.role === 'rpc')?.listening;
+
+      // All checks passed - update to pending_approval
+      const { error: updateError } = await supabase
+        .from('verifications')
+        .update({
+          status: VerificationStatus.PENDING_APPROVAL,
+          verified_at: new Date().toISOString(),
+          metadata: {
+            processCheck,
+            portCheck,
+            ports,
+            rpcListening,
+            systemInfo,
+            escalation,
+            customChecks,
+            timings,
+            payloadEncrypted: payloadEncrypted || undefined,
+            reachability,
+            userAgentCheck: userAgentResult,
+            strict: strictResult,
+            strictVerified: strictResult ? true : undefined,
+            addrRelay,
+            requestIp,
+            unknownFields: unknownFields.length > 0 ? unknownFields : undefined,
+          }
+        })
+        .eq('id', verification.id);
+
+      if (updateError) {
+        console.error('[VerifyNode:Confirm] Failed to update verification:', updateError);
+        return {
+          status: 500,
+          body: {
+            success: false,
+            error: 'Failed to update verification status',
+            code: 'UPDATE_FAILED'
+          },
+        };
+      }
+
+      // Manual submissions (verify emit-curl) carry self-reported checks only,
+      // so point admins at them instead of treating them like tool results
+      const manualSubmission = processCheck.method === 'manual' || portCheck.method === 'manual';
+
+      // Add to moderation queue for admin review
+      const { error: queueError } = await supabase
+        .from('moderation_queue')
+        .insert({
+          item_type: 'verification',
+          item_id: verification.id,
+          user_id: verification.user_id,
+          status: VerificationStatus.PENDING,
+          flagged_reason: manualSubmission
+            ? 'Manual submission: process and port checks were not run by the verify tool'
+            : null,
+          content_data: {
+            node_id: verification.node_id,
+            method: verification.method,
+            challenge: challenge,
+            proof: 'Two-step POST verification',
+            verification_passed: true,
+            manual_submission: manualSubmission,
+            processCheck,
+            portCheck,
+            ports,
+            rpcListening,
+            systemInfo,
+            escalation,
+            customChecks,
+            timings,
+            payloadEncrypted: payloadEncrypted || undefined,
+            reachability,
+            userAgentCheck: userAgentResult,
+            strict: strictResult,
+            strictVerified: strictResult ? true : undefined,
+            addrRelay,
+          }
+        });
+
+      if (queueError) {
+        console.error('[VerifyNode:Confirm] Failed to add to moderation queue:', queueError);
+        // Don't fail the request - verification is still updated
+      }
+
+      console.info('[VerifyNode:Confirm] Verification submitted successfully', {
+        verificationId: verification.id,
+        nodeId: verification.node_id,
+        requestIp,
+        processCheck,
+        portCheck,
+        manualSubmission,
+        timings,
       });
 
-    if (queueError) {
-      console.error('[VerifyNode:Confirm] Failed to add to moderation queue:', queueError);
-      // Don't fail the request - verification is still updated
+      return {
+        status: 200,
+        body: {
+          success: true,
+          status: VerificationStatus.PENDING_APPROVAL,
+          message: 'Verification submitted successfully! An admin will review it shortly.',
+          addrRelay,
+          strict: strictResult ? { methods: strictResult.methods } : undefined,
+          // The binary links the node's map page (with a QR code)
+          nodeId: verification.node_id,
+          // Saved by the binary as proof of the submission
+          receipt: buildReceipt(verification.id, node, challenge),
+        },
+      };
+    };
+
+    const checks = serverChecks().catch((err): ConfirmOutcome => {
+      console.error('[VerifyNode:Confirm] Unexpected error:', err);
+      return {
+        status: 500,
+        body: {
+          success: false,
+          error: 'An unexpected error occurred. Please try again later.',
+          code: 'INTERNAL_ERROR'
+        },
+      };
+    });
+
+    if (!acceptsPolling) {
+      const outcome = await checks;
+      return NextResponse.json(outcome.body, { status: outcome.status });
     }
 
-    console.info('[VerifyNode:Confirm] Verification submitted successfully', {
-      verificationId: verification.id,
-      nodeId: verification.node_id,
-      requestIp,
-      processCheck,
-      portCheck,
+    const outcome = await Promise.race([
+      checks,
+      new Promise<null>((resolve) => setTimeout(() => resolve(null), CONFIRM_SYNC_BUDGET_MS)),
+    ]);
+    if (outcome) {
+      return NextResponse.json(outcome.body, { status: outcome.status });
+    }
+
+    // Still probing: let the client poll instead of running into its HTTP
+    // timeout. The checks keep running and store their response.
+    const pollToken = randomBytes(16).toString('hex');
+    await supabase
+      .from('verifications')
+      .update({ poll_token: pollToken })
+      .eq('id', verification.id);
+
+    void checks.then(async (result) => {
+      const { error } = await supabase
+        .from('verifications')
+        .update({ confirm_result: result })
+        .eq('id', verification.id);
+      if (error) {
+        console.error('[VerifyNode:Confirm] Failed to store confirm result:', error);
+      }
     });
 
-    return NextResponse.json({
-      success: true,
-      status: VerificationStatus.PENDING_APPROVAL,
-      message: 'Verification submitted successfully! An admin will review it shortly.',
+    console.info('[VerifyNode:Confirm] Server checks still running, client will poll', {
+      verificationId: verification.id,
     });
+
+    return NextResponse.json(
+      {
+        success: true,
+        status: 'processing',
+        message: 'The map is still checking your node.',
+        pollToken,
+      },
+      { status: 202 }
+    );
   } catch (err) {
     console.error('[VerifyNode:Confirm] Unexpected error:', err);
     return NextResponse.json(
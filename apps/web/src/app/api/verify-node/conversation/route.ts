@@ -0,0 +1,136 @@
+import { createAdminClient } from '@/lib/supabase/server'
+import { NextRequest, NextResponse } from 'next/server'
+import { verifyNodeConversationSchema } from '@/lib/validations'
+import { rateLimit, RATE_LIMITS } from '@/lib/security'
+import { VerificationStatus } from '@/lib/verification'
+import { listVerificationMessages } from '@/lib/verification-messages'
+
+/**
+ * Verification conversation (verify questions)
+ *
+ * Called by the Go binary with the challenge, which authenticates the
+ * operator. Stores any answers to admin follow-up questions and returns the
+ * verification's status and messages. Answers are only accepted while the
+ * verification awaits approval; the binary stops polling once the status
+ * changes.
+ *
+ * @param {NextRequest} request - The request object
+ * @returns {Promise<NextResponse>} Status and messages
+ */
+export async function POST(request: NextRequest) {
+  try {
+    const rateLimitResult = await rateLimit(request, 'verify-node:conversation', RATE_LIMITS.VERIFY_CONVERSATION);
+    if (!rateLimitResult.allowed) {
+      return NextResponse.json(
+        {
+          success: false,
+          error: 'Too many requests. Please poll less often.',
+          code: 'RATE_LIMIT_EXCEEDED'
+        },
+        { status: 429 }
+      );
+    }
+
+    const validation = verifyNodeConversationSchema.safeParse(await request.json());
+    if (!validation.success) {
+      const errors = validation.error.errors.map(e => `${e.path.join('.')}: ${e.message}`).join(', ');
+      return NextResponse.json(
+        {
+          success: false,
+          error: `Validation failed: ${errors}`,
+          code: 'VALIDATION_ERROR'
+        },
+        { status: 400 }
+      );
+    }
+
+    const { challenge, answers } = validation.data;
+    const supabase = createAdminClient();
+
+    const { data: verification, error: verificationError } = await supabase
+      .from('verifications')
+      .select('id, status')
+      .eq('challenge', challenge)
+      .single();
+
+    if (verificationError || !verification) {
+      return NextResponse.json(
+        {
+          success: false,
+          error: 'Verification not found. Please ensure you copied the challenge correctly.',
+          code: 'VERIFICATION_NOT_FOUND'
+        },
+        { status: 404 }
+      );
+    }
+
+    let messages = await listVerificationMessages(supabase, verification.id);
+
+    if (answers?.length) {
+      if (verification.status !== VerificationStatus.PENDING_APPROVAL) {
+        return NextResponse.json(
+          {
+            success: false,
+            error: `Verification is no longer awaiting review (status: ${verification.status})`,
+            code: 'INVALID_STATUS'
+          },
+          { status: 409 }
+        );
+      }
+
+      // Only open admin questions of this verification can be answered
+      const answered = new Set(messages.map(m => m.replyTo).filter(Boolean));
+      const open = new Set(messages.filter(m => m.author === 'admin' && !answered.has(m.id)).map(m => m.id));
+      const unknown = answers.find(a => !open.has(a.questionId));
+      if (unknown) {
+        return NextResponse.json(
+          {
+            success: false,
+            error: `Question ${unknown.questionId} is unknown or already answered`,
+            code: 'QUESTION_NOT_OPEN'
+          },
+          { status: 409 }
+        );
+      }
+
+      const { error: insertError } = await supabase
+        .from('verification_messages')
+        .insert(answers.map(a => ({
+          verification_id: verification.id,
+          author: 'operator',
+          reply_to: a.questionId,
+          body: a.body,
+        })));
+
+      if (insertError) {
+        console.error('[Verify Conversation] Failed to store answers:', insertError);
+        return NextResponse.json(
+          {
+            success: false,
+            error: 'Failed to store answers',
+            code: 'UPDATE_FAILED'
+          },
+          { status: 500 }
+        );
+      }
+
+      messages = await listVerificationMessages(supabase, verification.id);
+    }
+
+    return NextResponse.json({
+      success: true,
+      status: verification.status,
+      messages,
+    });
+  } catch (error) {
+    console.error('[Verify Conversation] Error:', error);
+    return NextResponse.json(
+      {
+        success: false,
+        error: 'Internal server error',
+        code: 'INTERNAL_ERROR'
+      },
+      { status: 500 }
+    );
+  }
+}
@@ -1,8 +1,37 @@
 import { createAdminClient } from '@/lib/supabase/server'
-import { NextRequest, NextResponse } from 'next/server'
+import { NextRequest, NextResponse, after } from 'next/server'
 import { verifyNodeInitSchema } from '@/lib/validations'
+import { payloadEncryptionOffer } from '@/lib/payload-encryption'
 import { rateLimit, RATE_LIMITS } from '@/lib/security'
 import { VerificationStatus } from '@/lib/verification'
+import { createReachabilityProbe, runReachabilityProbe, type ReachabilityProbeRequest } from '@/lib/reachability-probe'
+
+/**
+ * Merge patch into a verification's metadata rather than replacing the
+ * column. With onlyStatus, nothing is written once the verification has
+ * left that status (e.g. confirm already ran).
+ */
+async function mergeVerificationMetadata(
+  supabase: ReturnType<typeof createAdminClient>,
+  verificationId: string,
+  patch: Record<string, unknown>,
+  onlyStatus?: string
+) {
+  const { data } = await supabase
+    .from('verifications')
+    .select('metadata')
+    .eq('id', verificationId)
+    .single();
+
+  let update = supabase
+    .from('verifications')
+    .update({ metadata: { ...((data?.metadata as Record<string, unknown> | null) ?? {}), ...patch } })
+    .eq('id', verificationId);
+  if (onlyStatus) {
+    update = update.eq('status', onlyStatus);
+  }
+  return update;
+}
 
 /**
  * Initialize node verification (Step 1 of 2)
@@ -10,6 +39,8 @@ import { VerificationStatus } from '@/lib/verification'
  * Called by the Go binary with the challenge string.
  * Returns the node's IP and port from the crawler database.
  * Stores the request IP for validation in step 2.
+ * With reachabilityProof, also hands out an inbound probe and runs it after
+ * the response (next/server after()) while the binary performs its checks.
  *
  * @param {NextRequest} request - The request object
  * @returns {Promise<NextResponse>} Node IP/port details
@@ -46,7 +77,7 @@ export async function POST(request: NextRequest) {
       );
     }
 
-    const { challenge } = validation.data;
+    const { challenge, reachabilityProof } = validation.data;
 
     const supabase = createAdminClient();
 
@@ -78,23 +109,44 @@ export async function POST(request: NextRequest) {
       );
     }
 
-    // Check if verification is still pending
+    // Check if verification is still pending. Expired and already used
+    // challenges get their own status codes so the binary can tell them apart.
     if (verification.status !== VerificationStatus.PENDING) {
-      const statusMessages: Record<string, string> = {
-        'pending_approval': 'Verification already submitted and awaiting admin approval. Check the web UI for status.',
-        'verified': 'This node is already verified.',
-        'failed': 'Verification failed. Please start a new verification from the web UI.',
-        'expired': 'Verification expired. Please start a new verification from the web UI.',
+      const statusErrors: Record<string, { error: string; code: string; status: number }> = {
+        'expired': {
+          error: 'Verification expired. Please start a new verification from the web UI.',
+          code: 'VERIFICATION_EXPIRED',
+          status: 410,
+        },
+        'pending_approval': {
+          error: 'Verification already submitted and awaiting admin approval. Check the web UI for status.',
+          code: 'VERIFICATION_ALREADY_SUBMITTED',
+          status: 409,
+        },
+        'verified': {
+          error: 'This node is already verified.',
+          code: 'NODE_ALREADY_VERIFIED',
+          status: 409,
+        },
+        'failed': {
+          error: 'Verification failed. Please start a new verification from the web UI.',
+          code: 'INVALID_STATUS',
+          status: 400,
+        },
+      };
+      const { error, code, status } = statusErrors[verification.status] || {
+        error: `Verification cannot proceed (status: ${verification.status})`,
+        code: 'INVALID_STATUS',
+        status: 400,
       };
-      const message = statusMessages[verification.status] || `Verification cannot proceed (status: ${verification.status})`;
 
       return NextResponse.json(
         {
           success: false,
-          error: message,
-          code: 'INVALID_STATUS'
+          error,
+          code
         },
-        { status: 400 }
+        { status }
       );
     }
 
@@ -164,6 +216,33 @@ export async function POST(request: NextRequest) {
 
     const node = nodes as { id: string; ip: string; port: number };
 
+    // Optional: probe the binary's auxiliary port while it runs its checks.
+    // The outcome lands in metadata for the confirm step to pick up.
+    let reachabilityProbe: ReachabilityProbeRequest | undefined;
+    if (reachabilityProof) {
+      const probe = createReachabilityProbe(node.port);
+      reachabilityProbe = probe;
+
+      await mergeVerificationMetadata(supabase, verification.id, {
+        reachabilityProbe: { port: probe.port },
+      });
+
+      // after() keeps the probe alive past the response on serverless hosts
+      after(async () => {
+        try {
+          const outcome = await runReachabilityProbe(node.ip, challenge, probe);
+          await mergeVerificationMetadata(
+            supabase,
+            verification.id,
+            { reachabilityProbe: outcome },
+            VerificationStatus.PENDING
+          );
+        } catch (err) {
+          console.error('[VerifyNode:Init] Reachability probe failed:', err);
+        }
+      });
+    }
+
     console.info('[VerifyNode:Init] Verification init successful', {
       verificationId: verification.id,
       nodeIp: node.ip,
@@ -177,6 +256,8 @@ export async function POST(request: NextRequest) {
         ip: node.ip,
         port: node.port,
       },
+      reachabilityProbe,
+      payloadEncryption: payloadEncryptionOffer(),
       message: 'Node details retrieved. Please complete the verification checks.',
     });
   } catch (err) {
@@ -0,0 +1,110 @@
+import { createAdminClient } from '@/lib/supabase/server'
+import { NextRequest, NextResponse } from 'next/server'
+import { rateLimit, RATE_LIMITS } from '@/lib/security'
+
+interface RouteParams {
+  params: Promise<{
+    token: string
+  }>
+}
+
+/**
+ * Poll a terminal pairing
+ *
+ * Called by the Go binary with the poll token from POST /api/verify-node/pair.
+ * Answers 202 until the code is entered on the website, then the bound
+ * verification's challenge. The pairing is deleted once handed over, so the
+ * challenge is returned only once.
+ *
+ * @param {NextRequest} request - The request object
+ * @returns {Promise<NextResponse>} Challenge or waiting status
+ */
+export async function GET(
+  request: NextRequest,
+  { params }: RouteParams
+) {
+  try {
+    const rateLimitResult = await rateLimit(request, 'verify-node:pair-poll', RATE_LIMITS.VERIFY_PAIR_POLL);
+    if (!rateLimitResult.allowed) {
+      return NextResponse.json(
+        {
+          success: false,
+          error: 'Too many requests. Please poll less often.',
+          code: 'RATE_LIMIT_EXCEEDED'
+        },
+        { status: 429 }
+      );
+    }
+
+    const { token } = await params;
+    if (!/^[0-9a-f]{32}$/.test(token)) {
+      return NextResponse.json(
+        { success: false, error: 'Invalid poll token', code: 'VALIDATION_ERROR' },
+        { status: 400 }
+      );
+    }
+
+    const supabase = createAdminClient();
+    const { data: pairing, error } = await supabase
+      .from('verification_pairings')
+      .select('id, verification_id, expires_at')
+      .eq('poll_token', token)
+      .single();
+
+    if (error || !pairing) {
+      return NextResponse.json(
+        { success: false, error: 'Unknown pairing', code: 'PAIRING_NOT_FOUND' },
+        { status: 404 }
+      );
+    }
+
+    if (!pairing.verification_id) {
+      if (new Date(pairing.expires_at) < new Date()) {
+        await supabase.from('verification_pairings').delete().eq('id', pairing.id);
+        return NextResponse.json(
+          { success: false, error: 'The pairing code has expired.', code: 'PAIRING_EXPIRED' },
+          { status: 410 }
+        );
+      }
+      return NextResponse.json(
+        {
+          success: true,
+          status: 'waiting',
+          message: 'Waiting for the code to be entered on the website.',
+        },
+        { status: 202 }
+      );
+    }
+
+    const { data: verification, error: verificationError } = await supabase
+      .from('verifications')
+      .select('challenge')
+      .eq('id', pairing.verification_id)
+      .single();
+
+    await supabase.from('verification_pairings').delete().eq('id', pairing.id);
+
+    if (verificationError || !verification) {
+      return NextResponse.json(
+        { success: false, error: 'The paired verification no longer exists.', code: 'VERIFICATION_NOT_FOUND' },
+        { status: 404 }
+      );
+    }
+
+    return NextResponse.json({
+      success: true,
+      status: 'paired',
+      challenge: verification.challenge,
+    });
+  } catch (err) {
+    console.error('[VerifyNode:PairPoll] Unexpected error:', err);
+    return NextResponse.json(
+      {
+        success: false,
+        error: 'An unexpected error occurred. Please try again later.',
+        code: 'INTERNAL_ERROR'
+      },
+      { status: 500 }
+    );
+  }
+}
@@ -0,0 +1,111 @@
+import { createClient, createAdminClient } from '@/lib/supabase/server'
+import { NextRequest, NextResponse } from 'next/server'
+import { verifyNodePairClaimSchema } from '@/lib/validations'
+import { rateLimit, RATE_LIMITS } from '@/lib/security'
+import { VerificationStatus, VerificationMethod, VerificationErrorCode } from '@/lib/verification'
+
+/**
+ * Enter a terminal pairing code
+ *
+ * Called by the verification dialog with the code the binary shows. Binds
+ * the pairing to the signed-in user's pending binary verification, so the
+ * binary's next poll receives its challenge.
+ *
+ * @param {NextRequest} request - The request object
+ * @returns {Promise<NextResponse>} Pairing result
+ */
+export async function POST(request: NextRequest) {
+  try {
+    const rateLimitResult = await rateLimit(request, 'verify-node:pair-claim', RATE_LIMITS.VERIFY);
+    if (!rateLimitResult.allowed) {
+      return NextResponse.json(
+        {
+          error: 'Too many pairing attempts. Please try again later.',
+          code: VerificationErrorCode.RATE_LIMIT_EXCEEDED
+        },
+        { status: 429 }
+      );
+    }
+
+    const supabase = await createClient();
+    const { data: { user }, error: authError } = await supabase.auth.getUser();
+    if (authError || !user) {
+      return NextResponse.json(
+        {
+          error: 'Authentication required',
+          code: VerificationErrorCode.AUTHENTICATION_REQUIRED
+        },
+        { status: 401 }
+      );
+    }
+
+    const validation = verifyNodePairClaimSchema.safeParse(await request.json());
+    if (!validation.success) {
+      const errors = validation.error.errors.map(e => `${e.path.join('.')}: ${e.message}`).join(', ');
+      return NextResponse.json(
+        {
+          error: `Validation failed: ${errors}`,
+          code: 'VALIDATION_ERROR'
+        },
+        { status: 400 }
+      );
+    }
+
+    const { code, verificationId } = validation.data;
+    const adminClient = createAdminClient();
+
+    const { data: verification } = await adminClient
+      .from('verifications')
+      .select('id, user_id, method, status, expires_at')
+      .eq('id', verificationId)
+      .single();
+
+    if (!verification || verification.user_id !== user.id) {
+      return NextResponse.json(
+        { error: 'Verification not found', code: 'VERIFICATION_NOT_FOUND' },
+        { status: 404 }
+      );
+    }
+    if (verification.method !== VerificationMethod.HTTP_FILE ||
+        verification.status !== VerificationStatus.PENDING ||
+        new Date(verification.expires_at) < new Date()) {
+      return NextResponse.json(
+        { error: 'Only a pending binary verification can be paired', code: 'INVALID_VERIFICATION_STATE' },
+        { status: 409 }
+      );
+    }
+
+    const { data: claimed, error: claimError } = await adminClient
+      .from('verification_pairings')
+      .update({ verification_id: verification.id, claimed_at: new Date().toISOString() })
+      .eq('code', code)
+      .is('claimed_at', null)
+      .gt('expires_at', new Date().toISOString())
+      .select('id');
+
+    if (claimError) {
+      console.error('[VerifyNode:PairClaim] Failed to claim pairing:', claimError);
+      return NextResponse.json(
+        { error: 'Failed to pair', code: 'UPDATE_FAILED' },
+        { status: 500 }
+      );
+    }
+    if (!claimed || claimed.length === 0) {
+      return NextResponse.json(
+        { error: 'Unknown or expired pairing code. Run verify again for a new one.', code: 'PAIRING_NOT_FOUND' },
+        { status: 404 }
+      );
+    }
+
+    return NextResponse.json({ success: true });
+  } catch (err) {
+    console.error('[VerifyNode:PairClaim] Unexpected error:', err);
+    return NextResponse.json(
+      {
+        error: 'An unexpected error occurred. Please try again later.',
+        code: 'INTERNAL_ERROR'
+      },
+      { status: 500 }
+    );
+  }
+}
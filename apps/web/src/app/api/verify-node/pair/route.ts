@@ -0,0 +1,87 @@
+import { createAdminClient } from '@/lib/supabase/server'
+import { NextRequest, NextResponse } from 'next/server'
+import { randomBytes } from 'crypto'
+import { rateLimit, RATE_LIMITS, getClientIP } from '@/lib/security'
+import { generatePairingCode, formatPairingCode, PAIRING_TTL_MS } from '@/lib/verification-pairing'
+
+// A fresh code collides with an open one about once in 10^12; retry a few
+// times rather than fail the operator
+const PAIRING_CODE_ATTEMPTS = 3;
+
+/**
+ * Start a terminal pairing (verify without a challenge)
+ *
+ * Called by the Go binary when the operator has no challenge at hand.
+ * Returns a short code to enter in the verification dialog and a secret
+ * poll token; GET /api/verify-node/pair/[token] returns the challenge once
+ * the code has been entered.
+ *
+ * @param {NextRequest} request - The request object
+ * @returns {Promise<NextResponse>} Pairing code, poll token and expiry
+ */
+export async function POST(request: NextRequest) {
+  try {
+    const rateLimitResult = await rateLimit(request, 'verify-node:pair', RATE_LIMITS.VERIFY_PAIR);
+    if (!rateLimitResult.allowed) {
+      return NextResponse.json(
+        {
+          success: false,
+          error: 'Too many pairing attempts. Please try again later.',
+          code: 'RATE_LIMIT_EXCEEDED'
+        },
+        { status: 429 }
+      );
+    }
+
+    const supabase = createAdminClient();
+    const clientIp = getClientIP(request);
+    const expiresAt = new Date(Date.now() + PAIRING_TTL_MS).toISOString();
+    const pollToken = randomBytes(16).toString('hex');
+
+    for (let attempt = 0; attempt < PAIRING_CODE_ATTEMPTS; attempt++) {
+      const code = generatePairingCode();
+      const { error } = await supabase
+        .from('verification_pairings')
+        .insert({
+          code,
+          poll_token: pollToken,
+          ip_address: clientIp === 'unknown' ? null : clientIp,
+          expires_at: expiresAt,
+        });
+
+      if (!error) {
+        return NextResponse.json({
+          success: true,
+          code: formatPairingCode(code),
+          pollToken,
+          url: `${new URL(request.url).origin}/my-nodes`,
+          expiresAt,
+        });
+      }
+      // 23505: the code is held by another open pairing
+      if (error.code !== '23505') {
+        console.error('[VerifyNode:Pair] Failed to create pairing:', error);
+        break;
+      }
+    }
+
+    return NextResponse.json(
+      {
+        success: false,
+        error: 'Failed to create a pairing code',
+        code: 'INTERNAL_ERROR'
+      },
+      { status: 500 }
+    );
+  } catch (err) {
+    console.error('[VerifyNode:Pair] Unexpected error:', err);
+    return NextResponse.json(
+      {
+        success: false,
+        error: 'An unexpected error occurred. Please try again later.',
+        code: 'INTERNAL_ERROR'
+      },
+      { status: 500 }
+    );
+  }
+}
@@ -79,6 +79,7 @@ export default function NodePage({ params }: PageProps) {
   const theme = getThemeConfig();
   const chainConfig = getChainConfig();
   const [node, setNode] = useState<NodeWithProfile | null>(null);
+  const [operatorNote, setOperatorNote] = useState<{ body: string; created_at: string } | null>(null);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
   const [snapshots, setSnapshots] = useState<any[]>([]);
@@ -94,8 +95,9 @@ export default function NodePage({ params }: PageProps) {
         throw new Error('Node not found');
       }
       const data = await response.json();
-      // API returns { node, uptimeHistory }
+      // API returns { node, uptimeHistory, badges, operatorNote }
       setNode(data.node);
+      setOperatorNote(data.operatorNote);
     } catch (err) {
       setError(err instanceof Error ? err.message : 'Failed to load node');
     } finally {
@@ -329,6 +331,18 @@ export default function NodePage({ params }: PageProps) {
               <p className="text-muted-foreground">{node.description}</p>
             </div>
           )}
+
+          {/* Operator note (verify note --public) */}
+          {operatorNote && (
+            <div className="bg-muted/50 rounded-lg p-4 mt-4">
+              <p className="text-sm text-muted-foreground">
+                <span className="font-medium text-foreground">Operator note</span>
+                {' · '}
+                {new Date(operatorNote.created_at).toLocaleString()}
+              </p>
+              <p className="text-muted-foreground mt-1">{operatorNote.body}</p>
+            </div>
+          )}
         </div>
 
         {/* Stats Grid - auto-fit ensures items expand when fewer than 4 */}
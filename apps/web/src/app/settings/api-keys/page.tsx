@@ -49,6 +49,7 @@ const AVAILABLE_SCOPES = [
   { id: 'read:stats', label: 'Read Stats', description: 'Access network statistics' },
   { id: 'read:leaderboard', label: 'Read Leaderboard', description: 'Access node rankings' },
   { id: 'read:profiles', label: 'Read Profiles', description: 'Access node profiles' },
+  { id: 'write:nodes', label: 'Manage Nodes', description: 'Manage your verified nodes from the verify CLI' },
 ];
 
 export default function ApiKeysPage() {
@@ -223,6 +223,13 @@ export function NodeDetailModal({
                   <span className="text-[10px] sm:text-xs text-muted-foreground uppercase tracking-wide">
                     {node.status === 'reachable' ? 'TCP only' : `Since ${connectionDuration}`}
                   </span>
+                  {node.retiringAt && (
+                    <span className="text-[10px] sm:text-xs font-bold uppercase tracking-wide text-warning">
+                      {new Date(node.retiringAt) > new Date()
+                        ? `Retiring ${new Date(node.retiringAt).toLocaleDateString()}`
+                        : 'Retired'}
+                    </span>
+                  )}
                 </div>
               </div>
             </div>
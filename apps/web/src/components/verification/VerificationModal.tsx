@@ -68,6 +68,12 @@ export function VerificationModal({
   const [turnstileToken, setTurnstileToken] = useState<string | null>(null);
   const [copySuccess, setCopySuccess] = useState(false);
 
+  // Pairing with a binary started without a challenge
+  const [pairingCode, setPairingCode] = useState('');
+  const [pairingLoading, setPairingLoading] = useState(false);
+  const [pairingError, setPairingError] = useState<string | null>(null);
+  const [paired, setPaired] = useState(false);
+
   // DNS verification state
   const [dnsDomain, setDnsDomain] = useState('');
   const [dnsPolling, setDnsPolling] = useState(false);
@@ -395,6 +401,29 @@ export function VerificationModal({
     }
   };
 
+  const handlePairTerminal = async () => {
+    if (!verification.verificationId || !pairingCode.trim()) return;
+    setPairingLoading(true);
+    setPairingError(null);
+
+    try {
+      const response = await fetch('/api/verify-node/pair/claim', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ code: pairingCode, verificationId: verification.verificationId }),
+      });
+      const data = await response.json();
+      if (!response.ok) {
+        throw new Error(data.error || 'Failed to pair');
+      }
+      setPaired(true);
+    } catch (err) {
+      setPairingError(err instanceof Error ? err.message : 'Failed to pair');
+    } finally {
+      setPairingLoading(false);
+    }
+  };
+
   const handleMethodSelect = async (method: VerificationMethod) => {
     setLoading(true);
     setError(null);
@@ -534,6 +563,9 @@ export function VerificationModal({
     setProof('');
     setError(null);
     setTurnstileToken(null);
+    setPairingCode('');
+    setPairingError(null);
+    setPaired(false);
     onClose();
   };
 
@@ -1137,6 +1169,44 @@ export function VerificationModal({
                         <AlertCircle className="h-4 w-4 mt-0.5 flex-shrink-0" />
                         <span><strong>Important:</strong> Run this command on your node server (the machine running {chainConfig.name.toLowerCase()}d), not your local computer. The binary will automatically submit the verification and you&apos;ll see the result in the terminal.</span>
                       </p>
+
+                      {/* Pairing: the binary run without a challenge shows a short code */}
+                      <div className="p-4 rounded-xl border-2 border-border space-y-3">
+                        <p className="text-sm font-medium">Ran the binary without a challenge?</p>
+                        <p className="text-xs text-muted-foreground">
+                          Run <code className="font-mono">./verify</code> on its own and enter the code it shows. It picks up this challenge automatically, so nothing has to be copied over SSH.
+                        </p>
+                        {paired ? (
+                          <p className="text-sm text-green-600 dark:text-green-400 flex items-center gap-2">
+                            <CheckCircle className="h-4 w-4" />
+                            Paired. The binary continues on its own; watch the terminal for the result.
+                          </p>
+                        ) : (
+                          <div className="flex gap-2">
+                            <input
+                              type="text"
+                              value={pairingCode}
+                              onChange={(e) => setPairingCode(e.target.value.toUpperCase())}
+                              placeholder="ABCD-EFGH"
+                              maxLength={9}
+                              autoComplete="off"
+                              spellCheck={false}
+                              className="flex-1 px-3 py-2 rounded-lg border border-border bg-background font-mono tracking-widest text-sm"
+                            />
+                            <button
+                              onClick={handlePairTerminal}
+                              disabled={pairingLoading || !pairingCode.trim()}
+                              className="px-4 py-2 rounded-lg text-white text-sm font-medium disabled:opacity-50"
+                              style={{ backgroundColor: theme.primaryColor }}
+                            >
+                              {pairingLoading ? <Loader2 className="h-4 w-4 animate-spin" /> : 'Pair'}
+                            </button>
+                          </div>
+                        )}
+                        {pairingError && (
+                          <p className="text-xs text-red-600 dark:text-red-400">{pairingError}</p>
+                        )}
+                      </div>
                     </div>
                   </div>
                 </div>
@@ -39,6 +39,7 @@ const transformNode = (node: any): NodeWithProfile => ({
   lastSeen: node.last_seen,
   firstSeen: node.first_seen || new Date().toISOString(),
   timesSeen: node.times_seen || 0,
+  retiringAt: node.retiring_at || null,
   latencyMs: node.latency_ms,
   latencyAvg: node.latency_avg,
   uptime: node.uptime_percentage || node.uptime || 0,
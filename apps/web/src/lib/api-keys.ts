@@ -26,6 +26,7 @@ export const API_SCOPES = {
   'read:stats': 'Read network statistics',
   'read:leaderboard': 'Read leaderboard data',
   'read:profiles': 'Read node profiles',
+  'write:nodes': 'Manage your verified nodes from the verify CLI',
 } as const;
 
 export type ApiScope = keyof typeof API_SCOPES;
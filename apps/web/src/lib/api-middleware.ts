@@ -251,3 +251,34 @@ export async function withOptionalApiKeyAuth(
   // No key provided - anonymous access
   return handler(null);
 }
+
+/**
+ * Wrapper for endpoints that change a node on behalf of its owner
+ *
+ * Requires an API key with the write:nodes scope whose user owns the
+ * node's verification (verified_nodes.user_id).
+ */
+export async function withNodeOwnerAuth(
+  request: NextRequest,
+  nodeId: string,
+  handler: (ctx: ApiContext) => Promise<NextResponse>
+): Promise<NextResponse> {
+  return withApiKeyAuth(request, 'write:nodes', async (ctx) => {
+    const adminClient = createAdminClient();
+    const { data: ownership } = await adminClient
+      .from('verified_nodes')
+      .select('id')
+      .eq('node_id', nodeId)
+      .eq('user_id', ctx.userId)
+      .maybeSingle();
+
+    if (!ownership) {
+      return NextResponse.json(
+        { success: false, error: 'You can only manage nodes you have verified', code: 'NOT_NODE_OWNER' },
+        { status: 403 }
+      );
+    }
+
+    return handler(ctx);
+  });
+}
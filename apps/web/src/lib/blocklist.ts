@@ -0,0 +1,62 @@
+/**
+ * Community Blocklist
+ *
+ * The ranges the crawler never reports (crawlerConfig.denylist plus
+ * CRAWLER_DENYLIST), served to `verify peers` so operators can see how many
+ * of their node's peers are flagged. When BLOCKLIST_SIGNING_KEY is set the
+ * list is signed with Ed25519, and binaries built with the matching
+ * crawlerConfig.blocklistPublicKey refuse a list that was altered.
+ */
+
+import { createPrivateKey, sign } from 'crypto';
+import { getProjectConfig } from '@atlasp2p/config';
+
+// PKCS#8 DER header for a raw 32-byte Ed25519 private key seed
+export const ED25519_PKCS8_PREFIX = Buffer.from('302e020100300506032b657004220420', 'hex');
+
+export interface Blocklist {
+  entries: string[];
+  issuedAt: string;
+  signature?: string;
+}
+
+/**
+ * Configured denylist entries, without duplicates
+ */
+export function blocklistEntries(): string[] {
+  const configured = getProjectConfig().crawlerConfig.denylist ?? [];
+  const fromEnv = (process.env.CRAWLER_DENYLIST ?? '')
+    .split(',')
+    .map(entry => entry.trim())
+    .filter(Boolean);
+  return Array.from(new Set([...configured, ...fromEnv]));
+}
+
+/**
+ * The text that is signed: the issue time and one entry per line.
+ * Must match blocklistMessage in tools/verify/peers.go.
+ */
+export function blocklistMessage(issuedAt: string, entries: string[]): string {
+  return [issuedAt, ...entries].join('\n');
+}
+
+/**
+ * Build the current blocklist, signed when a signing key is configured
+ */
+export function buildBlocklist(now: Date = new Date()): Blocklist {
+  const entries = blocklistEntries();
+  const issuedAt = now.toISOString();
+
+  const seed = process.env.BLOCKLIST_SIGNING_KEY;
+  if (!seed) {
+    return { entries, issuedAt };
+  }
+
+  const key = createPrivateKey({
+    key: Buffer.concat([ED25519_PKCS8_PREFIX, Buffer.from(seed, 'base64')]),
+    format: 'der',
+    type: 'pkcs8',
+  });
+  const signature = sign(null, Buffer.from(blocklistMessage(issuedAt, entries)), key).toString('base64');
+  return { entries, issuedAt, signature };
+}
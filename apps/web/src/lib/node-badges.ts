@@ -0,0 +1,41 @@
+/**
+ * Node Badge Tokens
+ *
+ * Admins issue sponsorship and community badge tokens; node owners redeem
+ * them with `verify attach-badge` to show a supporter badge on the map.
+ *
+ * Token format: nmb_<kind>_<expiry unix seconds>_<24 alphanumeric>
+ * The expiry is part of the token so the binary can reject expired tokens
+ * before submitting; the stored expires_at stays authoritative. Only the
+ * SHA-256 hash of a token is stored.
+ */
+
+import { generateSecureToken } from '@/lib/security';
+
+export const BADGE_KINDS = ['sponsor', 'community'] as const;
+
+export type BadgeKind = typeof BADGE_KINDS[number];
+
+export const BADGE_TOKEN_PATTERN = /^nmb_(sponsor|community)_(\d{10})_[A-Za-z0-9]{24}$/;
+
+// Longest validity an admin can give a token
+export const MAX_BADGE_VALIDITY_DAYS = 730;
+
+/**
+ * Generate a badge token of kind that expires at expiresAt
+ */
+export function generateBadgeToken(kind: BadgeKind, expiresAt: Date): string {
+  const expiry = Math.floor(expiresAt.getTime() / 1000);
+  return `nmb_${kind}_${expiry}_${generateSecureToken(24)}`;
+}
+
+/**
+ * Hash a badge token using SHA-256
+ */
+export async function hashBadgeToken(token: string): Promise<string> {
+  const data = new TextEncoder().encode(token);
+  const hashBuffer = await crypto.subtle.digest('SHA-256', data);
+  return Array.from(new Uint8Array(hashBuffer))
+    .map(b => b.toString(16).padStart(2, '0'))
+    .join('');
+}
@@ -0,0 +1,297 @@
+import net from 'net'
+import { createHash, randomBytes } from 'crypto'
+
+// ===========================================
+// P2P VERSION HANDSHAKE PROBE
+// ===========================================
+// Minimal Bitcoin-style handshake: send our version message and read the
+// peer's version reply to learn its advertised user agent. The addr relay
+// probe completes the handshake and asks for peers with getaddr.
+
+const HEADER_SIZE = 24
+const PROBE_USER_AGENT = '/nodes-map-verify:1.0/'
+const ADDR_ENTRY_SIZE = 30
+
+// A getaddr answer with fewer addresses is not a reasonable peer set (the
+// node's own announcement alone is one address)
+export const MIN_RELAYED_ADDRESSES = 8
+
+export interface P2PProbeResult {
+  success: boolean
+  userAgent?: string
+  protocolVersion?: number
+  error?: string
+}
+
+export interface AddrRelayResult {
+  success: boolean
+  addressCount: number
+  relays: boolean
+  error?: string
+}
+
+function sha256d(data: Buffer): Buffer {
+  return createHash('sha256').update(createHash('sha256').update(data).digest()).digest()
+}
+
+// Read a CompactSize integer; null if the buffer is too short
+function readVarInt(buf: Buffer, offset: number): { value: number; size: number } | null {
+  if (buf.length < offset + 1) return null
+  const first = buf[offset]
+  if (first < 0xfd) return { value: first, size: 1 }
+  if (first === 0xfd && buf.length >= offset + 3) return { value: buf.readUInt16LE(offset + 1), size: 3 }
+  if (first === 0xfe && buf.length >= offset + 5) return { value: buf.readUInt32LE(offset + 1), size: 5 }
+  return null
+}
+
+function encodeVarInt(n: number): Buffer {
+  if (n < 0xfd) return Buffer.from([n])
+  const buf = Buffer.alloc(3)
+  buf[0] = 0xfd
+  buf.writeUInt16LE(n, 1)
+  return buf
+}
+
+// Network address without timestamp (as used in the version message)
+function encodeNetAddr(): Buffer {
+  const buf = Buffer.alloc(26)
+  buf.writeBigUInt64LE(BigInt(0), 0)
+  // IPv4-mapped ::ffff:0.0.0.0, port 0
+  buf[18] = 0xff
+  buf[19] = 0xff
+  return buf
+}
+
+function buildMessage(magic: Buffer, command: string, payload: Buffer): Buffer {
+  const header = Buffer.alloc(HEADER_SIZE)
+  magic.copy(header, 0)
+  header.write(command, 4, 'ascii')
+  header.writeUInt32LE(payload.length, 16)
+  sha256d(payload).copy(header, 20, 0, 4)
+  return Buffer.concat([header, payload])
+}
+
+function buildVersionPayload(protocolVersion: number): Buffer {
+  const head = Buffer.alloc(20)
+  head.writeInt32LE(protocolVersion, 0)
+  head.writeBigUInt64LE(BigInt(0), 4)
+  head.writeBigInt64LE(BigInt(Math.floor(Date.now() / 1000)), 12)
+
+  const agent = Buffer.from(PROBE_USER_AGENT, 'ascii')
+  const tail = Buffer.alloc(5)
+  tail.writeInt32LE(0, 0) // start_height
+  tail[4] = 0             // relay
+
+  return Buffer.concat([
+    head,
+    encodeNetAddr(),
+    encodeNetAddr(),
+    randomBytes(8),
+    encodeVarInt(agent.length),
+    agent,
+    tail,
+  ])
+}
+
+// Parse protocol version and user agent out of a version payload
+function parseVersionPayload(payload: Buffer): { protocolVersion: number; userAgent: string } | null {
+  // version(4) services(8) timestamp(8) addr_recv(26) addr_from(26) nonce(8)
+  const offset = 80
+  if (payload.length < offset + 1) return null
+
+  const protocolVersion = payload.readInt32LE(0)
+  let len = payload[offset]
+  let start = offset + 1
+  if (len === 0xfd) {
+    if (payload.length < offset + 3) return null
+    len = payload.readUInt16LE(offset + 1)
+    start = offset + 3
+  } else if (len > 0xfd) {
+    return null
+  }
+  if (payload.length < start + len) return null
+
+  return { protocolVersion, userAgent: payload.subarray(start, start + len).toString('ascii') }
+}
+
+// Addresses (ip:port keys) in an addr payload: count, then entries of
+// time(4) services(8) ip(16) port(2, big endian)
+function parseAddrPayload(payload: Buffer): string[] | null {
+  const count = readVarInt(payload, 0)
+  if (!count || payload.length < count.size + count.value * ADDR_ENTRY_SIZE) return null
+
+  const addresses: string[] = []
+  for (let i = 0; i < count.value; i++) {
+    const entry = count.size + i * ADDR_ENTRY_SIZE
+    const ip = payload.toString('hex', entry + 12, entry + 28)
+    addresses.push(`${ip}:${payload.readUInt16BE(entry + 28)}`)
+  }
+  return addresses
+}
+
+type SendMessage = (command: string, payload?: Buffer) => void
+
+/**
+ * Run a P2P session: connect, send our version message and pass every
+ * message to onMessage until it returns a result. onTimeout (if given)
+ * builds the result when the peer goes quiet.
+ */
+function runP2PSession<T>(
+  ip: string,
+  port: number,
+  magicBytes: string,
+  protocolVersion: number,
+  timeoutMs: number,
+  fail: (error: string) => T,
+  onMessage: (command: string, payload: Buffer, send: SendMessage) => T | undefined,
+  onTimeout?: () => T
+): Promise<T> {
+  const magic = Buffer.from(magicBytes, 'hex')
+
+  return new Promise((resolve) => {
+    const socket = new net.Socket()
+    let buffered = Buffer.alloc(0)
+    let resolved = false
+
+    const finish = (result: T) => {
+      if (!resolved) {
+        resolved = true
+        socket.destroy()
+        resolve(result)
+      }
+    }
+    const send: SendMessage = (command, payload = Buffer.alloc(0)) => {
+      socket.write(buildMessage(magic, command, payload))
+    }
+
+    socket.setTimeout(timeoutMs)
+
+    socket.on('connect', () => {
+      send('version', buildVersionPayload(protocolVersion))
+    })
+
+    socket.on('data', (chunk: Buffer) => {
+      buffered = Buffer.concat([buffered, chunk])
+
+      while (buffered.length >= HEADER_SIZE) {
+        if (!buffered.subarray(0, 4).equals(magic)) {
+          finish(fail('Unexpected network magic'))
+          return
+        }
+        const command = buffered.toString('ascii', 4, 16).replace(/\0+$/, '')
+        const length = buffered.readUInt32LE(16)
+        if (buffered.length < HEADER_SIZE + length) return
+
+        const payload = buffered.subarray(HEADER_SIZE, HEADER_SIZE + length)
+        buffered = buffered.subarray(HEADER_SIZE + length)
+
+        const result = onMessage(command, payload, send)
+        if (result !== undefined) {
+          finish(result)
+          return
+        }
+      }
+    })
+
+    socket.on('timeout', () => finish(onTimeout ? onTimeout() : fail('Handshake timeout')))
+    socket.on('error', (err) => finish(fail(err.message)))
+    socket.on('close', () => finish(onTimeout ? onTimeout() : fail('Connection closed before version message')))
+
+    try {
+      socket.connect(port, ip)
+    } catch {
+      finish(fail('Failed to initiate connection'))
+    }
+  })
+}
+
+/**
+ * Connect to a node's P2P port and read the user agent from its version message
+ *
+ * @param {string} ip - Node IP address
+ * @param {number} port - Node P2P port
+ * @param {string} magicBytes - Network magic as 8 hex characters
+ * @param {number} protocolVersion - Protocol version to announce
+ * @param {number} timeoutMs - Overall timeout for the handshake
+ */
+export function probeUserAgent(
+  ip: string,
+  port: number,
+  magicBytes: string,
+  protocolVersion: number,
+  timeoutMs: number = 10000
+): Promise<P2PProbeResult> {
+  return runP2PSession<P2PProbeResult>(
+    ip, port, magicBytes, protocolVersion, timeoutMs,
+    (error) => ({ success: false, error }),
+    (command, payload) => {
+      if (command !== 'version') return undefined
+      const parsed = parseVersionPayload(payload)
+      return parsed
+        ? { success: true, ...parsed }
+        : { success: false, error: 'Malformed version message' }
+    }
+  )
+}
+
+/**
+ * Check that a node relays addresses: complete the handshake, send getaddr
+ * and count the addresses it answers with. Nodes that accept connections
+ * but return no (or only their own) address don't propagate addresses.
+ *
+ * @param {string} ip - Node IP address
+ * @param {number} port - Node P2P port
+ * @param {string} magicBytes - Network magic as 8 hex characters
+ * @param {number} protocolVersion - Protocol version to announce
+ * @param {number} timeoutMs - How long to wait for the handshake and addr reply
+ */
+export function probeAddrRelay(
+  ip: string,
+  port: number,
+  magicBytes: string,
+  protocolVersion: number,
+  timeoutMs: number = 10000
+): Promise<AddrRelayResult> {
+  const addresses = new Set<string>()
+  let handshakeDone = false
+
+  const result = (): AddrRelayResult => ({
+    success: true,
+    addressCount: addresses.size,
+    relays: addresses.size >= MIN_RELAYED_ADDRESSES,
+  })
+
+  return runP2PSession<AddrRelayResult>(
+    ip, port, magicBytes, protocolVersion, timeoutMs,
+    (error) => ({ success: false, addressCount: 0, relays: false, error }),
+    (command, payload, send) => {
+      switch (command) {
+        case 'version':
+          send('verack')
+          return undefined
+        case 'verack':
+          // getaddr is only answered once the handshake is complete
+          handshakeDone = true
+          send('getaddr')
+          return undefined
+        case 'ping':
+          send('pong', payload)
+          return undefined
+        case 'addr': {
+          const batch = parseAddrPayload(payload)
+          if (!batch) {
+            return { success: false, addressCount: addresses.size, relays: false, error: 'Malformed addr message' }
+          }
+          batch.forEach(a => addresses.add(a))
+          // A single address is usually the node announcing itself; the
+          // getaddr reply comes as one larger batch
+          return batch.length > 1 ? result() : undefined
+        }
+      }
+      return undefined
+    },
+    () => handshakeDone
+      ? result()
+      : { success: false, addressCount: 0, relays: false, error: 'Handshake did not complete' }
+  )
+}
@@ -0,0 +1,100 @@
+/**
+ * Payload Encryption
+ *
+ * Some hosting providers terminate TLS at a mandatory proxy that can read
+ * request bodies. With CONFIRM_PAYLOAD_KEY set, verify init offers an X25519
+ * public key and `verify --encrypt-payload` seals the confirm body to it:
+ * a fresh ephemeral key per payload, AES-256-GCM with a key derived by
+ * SHA-256 from a label, the shared secret and both public keys. Binaries
+ * built with the matching features.verification.payloadPublicKey refuse an
+ * offer carrying any other key. Must match tools/verify/payload.go.
+ */
+
+import { createDecipheriv, createHash, createPrivateKey, createPublicKey, diffieHellman, type KeyObject } from 'crypto';
+
+export const PAYLOAD_ALG = 'x25519-aes256gcm-v1';
+
+const PAYLOAD_KDF_LABEL = 'atlasp2p-payload-v1';
+
+// DER headers for raw 32-byte X25519 keys
+const X25519_PKCS8_PREFIX = Buffer.from('302e020100300506032b656e04220420', 'hex');
+const X25519_SPKI_PREFIX = Buffer.from('302a300506032b656e032100', 'hex');
+
+const GCM_TAG_LENGTH = 16;
+
+export interface PayloadEncryptionOffer {
+  alg: string;
+  publicKey: string;
+}
+
+export interface SealedPayload {
+  alg: string;
+  epk: string;
+  nonce: string;
+  ciphertext: string;
+}
+
+function payloadPrivateKey(): KeyObject | null {
+  const seed = process.env.CONFIRM_PAYLOAD_KEY;
+  if (!seed) {
+    return null;
+  }
+  return createPrivateKey({
+    key: Buffer.concat([X25519_PKCS8_PREFIX, Buffer.from(seed, 'base64')]),
+    format: 'der',
+    type: 'pkcs8',
+  });
+}
+
+function rawPublicKey(key: KeyObject): Buffer {
+  const der = createPublicKey(key).export({ format: 'der', type: 'spki' });
+  return der.subarray(der.length - 32);
+}
+
+/**
+ * The offer for the init response, or undefined when no key is configured
+ */
+export function payloadEncryptionOffer(): PayloadEncryptionOffer | undefined {
+  const key = payloadPrivateKey();
+  if (!key) {
+    return undefined;
+  }
+  return { alg: PAYLOAD_ALG, publicKey: rawPublicKey(key).toString('base64') };
+}
+
+/**
+ * Decrypt a sealed payload. Throws when no key is configured or the payload
+ * was not sealed to it.
+ */
+export function openSealedPayload(sealed: SealedPayload): string {
+  const privateKey = payloadPrivateKey();
+  if (!privateKey) {
+    throw new Error('Payload encryption is not configured');
+  }
+  if (sealed.alg !== PAYLOAD_ALG) {
+    throw new Error(`Unsupported payload algorithm ${sealed.alg}`);
+  }
+
+  const epk = Buffer.from(sealed.epk, 'base64');
+  const publicKey = createPublicKey({
+    key: Buffer.concat([X25519_SPKI_PREFIX, epk]),
+    format: 'der',
+    type: 'spki',
+  });
+  const shared = diffieHellman({ privateKey, publicKey });
+  const key = createHash('sha256')
+    .update(PAYLOAD_KDF_LABEL)
+    .update(shared)
+    .update(epk)
+    .update(rawPublicKey(privateKey))
+    .digest();
+
+  // Go's GCM Seal appends the tag to the ciphertext
+  const data = Buffer.from(sealed.ciphertext, 'base64');
+  const decipher = createDecipheriv('aes-256-gcm', key, Buffer.from(sealed.nonce, 'base64'));
+  decipher.setAuthTag(data.subarray(data.length - GCM_TAG_LENGTH));
+  return Buffer.concat([
+    decipher.update(data.subarray(0, data.length - GCM_TAG_LENGTH)),
+    decipher.final(),
+  ]).toString('utf8');
+}
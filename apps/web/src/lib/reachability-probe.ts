@@ -0,0 +1,117 @@
+import net from 'net'
+import { createHash, randomBytes } from 'crypto'
+
+// ===========================================
+// INBOUND REACHABILITY PROBE
+// ===========================================
+// The verify tool (--reachability-proof) listens on an auxiliary port while
+// its local checks run. We connect, send a nonce, and expect a reply bound
+// to the challenge, proving the host accepts inbound connections.
+
+// Seconds the tool keeps listening; must cover the tool's local checks
+export const REACHABILITY_TIMEOUT_SECONDS = 30
+
+const RETRY_INTERVAL_MS = 2000
+const CONNECT_TIMEOUT_MS = 5000
+
+export interface ReachabilityProbeRequest {
+  port: number
+  nonce: string
+  timeout: number
+}
+
+export interface ReachabilityProbeOutcome {
+  port: number
+  probed: boolean
+  error?: string
+  checkedAt: string
+}
+
+/**
+ * Build the probe the init route hands to the verify tool. The auxiliary port
+ * defaults to the P2P port + 1 and can be overridden with
+ * VERIFY_REACHABILITY_PORT.
+ *
+ * @param {number} nodePort - Node P2P port
+ */
+export function createReachabilityProbe(nodePort: number): ReachabilityProbeRequest {
+  const configured = parseInt(process.env.VERIFY_REACHABILITY_PORT || '', 10)
+  const port = configured > 0 && configured < 65536 ? configured : nodePort + 1
+
+  return {
+    port: port < 65536 ? port : nodePort - 1,
+    nonce: randomBytes(16).toString('hex'),
+    timeout: REACHABILITY_TIMEOUT_SECONDS,
+  }
+}
+
+/**
+ * Expected reply to a probe; must match reachabilityReply() in tools/verify
+ */
+export function reachabilityReply(challenge: string, nonce: string): string {
+  return createHash('sha256').update(`${challenge}:${nonce}`).digest('hex')
+}
+
+function attemptProbe(ip: string, port: number, nonce: string, expected: string): Promise<string | null> {
+  return new Promise((resolve) => {
+    const socket = new net.Socket()
+    let data = ''
+    let resolved = false
+
+    const finish = (error: string | null) => {
+      if (!resolved) {
+        resolved = true
+        socket.destroy()
+        resolve(error)
+      }
+    }
+
+    socket.setTimeout(CONNECT_TIMEOUT_MS)
+    socket.on('connect', () => socket.write(`${nonce}\n`))
+    socket.on('data', (chunk) => {
+      data += chunk.toString('ascii')
+      if (data.includes('\n')) {
+        finish(data.trim() === expected ? null : 'Unexpected probe reply')
+      }
+    })
+    socket.on('timeout', () => finish('Connection timeout'))
+    socket.on('error', (err) => finish(err.message))
+    socket.on('close', () => finish('Connection closed without reply'))
+
+    try {
+      socket.connect(port, ip)
+    } catch {
+      finish('Failed to initiate connection')
+    }
+  })
+}
+
+/**
+ * Connect to the tool's auxiliary port until it answers or the probe window
+ * closes. The tool starts listening right after init returns, so early
+ * attempts are expected to be refused.
+ *
+ * @param {string} ip - Node IP address
+ * @param {string} challenge - Verification challenge the reply is bound to
+ * @param {ReachabilityProbeRequest} probe - Probe handed out by init
+ */
+export async function runReachabilityProbe(
+  ip: string,
+  challenge: string,
+  probe: ReachabilityProbeRequest
+): Promise<ReachabilityProbeOutcome> {
+  const expected = reachabilityReply(challenge, probe.nonce)
+  const deadline = Date.now() + probe.timeout * 1000
+  let lastError = 'No attempt made'
+
+  while (Date.now() < deadline) {
+    const error = await attemptProbe(ip, probe.port, probe.nonce, expected)
+    if (!error) {
+      return { port: probe.port, probed: true, checkedAt: new Date().toISOString() }
+    }
+    lastError = error
+    await new Promise((resolve) => setTimeout(resolve, RETRY_INTERVAL_MS))
+  }
+
+  return { port: probe.port, probed: false, error: lastError, checkedAt: new Date().toISOString() }
+}
@@ -0,0 +1,67 @@
+/**
+ * Verification Receipts
+ *
+ * A successful confirm returns a receipt the verify binary saves to disk,
+ * so operators keep durable proof of the submission and support staff have
+ * something concrete to look up when an approval stalls. When
+ * RECEIPT_SIGNING_KEY is set the receipt is signed with Ed25519 like the
+ * blocklist; check a receipt with the matching public key.
+ */
+
+import { createHash, createPrivateKey, sign } from 'crypto';
+import { ED25519_PKCS8_PREFIX } from '@/lib/blocklist';
+
+export interface VerificationReceipt {
+  verificationId: string;
+  nodeIp: string;
+  nodePort: number;
+  /** SHA-256 of the challenge, hex; the challenge itself stays secret */
+  challengeHash: string;
+  submittedAt: string;
+  signature?: string;
+}
+
+/**
+ * The text that is signed: one field per line in declaration order
+ */
+export function receiptMessage(receipt: Omit<VerificationReceipt, 'signature'>): string {
+  return [
+    receipt.verificationId,
+    receipt.nodeIp,
+    String(receipt.nodePort),
+    receipt.challengeHash,
+    receipt.submittedAt,
+  ].join('\n');
+}
+
+/**
+ * Build the receipt of a submitted verification, signed when a signing key
+ * is configured
+ */
+export function buildReceipt(
+  verificationId: string,
+  node: { ip: string; port: number },
+  challenge: string,
+  now: Date = new Date()
+): VerificationReceipt {
+  const receipt: VerificationReceipt = {
+    verificationId,
+    nodeIp: node.ip,
+    nodePort: node.port,
+    challengeHash: createHash('sha256').update(challenge).digest('hex'),
+    submittedAt: now.toISOString(),
+  };
+
+  const seed = process.env.RECEIPT_SIGNING_KEY;
+  if (!seed) {
+    return receipt;
+  }
+
+  const key = createPrivateKey({
+    key: Buffer.concat([ED25519_PKCS8_PREFIX, Buffer.from(seed, 'base64')]),
+    format: 'der',
+    type: 'pkcs8',
+  });
+  receipt.signature = sign(null, Buffer.from(receiptMessage(receipt)), key).toString('base64');
+  return receipt;
+}
@@ -426,6 +426,30 @@ export const RATE_LIMITS = {
     windowMs: 60 * 60 * 1000 // 1 hour
   },
 
+  // Polling for admin follow-up questions on a verification
+  VERIFY_CONVERSATION: {
+    maxRequests: 120,
+    windowMs: 60 * 60 * 1000 // 1 hour - enough to poll every 30 seconds
+  },
+
+  // Polling for a confirm whose server-side checks are still running
+  VERIFY_CONFIRM_POLL: {
+    maxRequests: 120,
+    windowMs: 10 * 60 * 1000 // 10 minutes - one poll every 5 seconds
+  },
+
+  // Terminal pairing codes requested by verify run without a challenge
+  VERIFY_PAIR: {
+    maxRequests: 10,
+    windowMs: 60 * 60 * 1000 // 1 hour
+  },
+
+  // Polling for a pairing code to be entered on the website
+  VERIFY_PAIR_POLL: {
+    maxRequests: 120,
+    windowMs: 10 * 60 * 1000 // 10 minutes - one poll every 5 seconds
+  },
+
   // Moderate limits for profile updates
   PROFILE: {
     maxRequests: 20,
@@ -48,32 +48,198 @@ export type VerifyComplete = z.infer<typeof verifyCompleteSchema>;
 export const verifyNodeInitSchema = z.object({
   challenge: z.string().min(20).max(128).regex(/^[a-zA-Z0-9]+$/, 'Challenge must contain only alphanumeric characters'),
   hostname: z.string().optional(),
+  reachabilityProof: z.boolean().optional(),
 });
 
 export type VerifyNodeInit = z.infer<typeof verifyNodeInitSchema>;
 
+// Check method reported by the verify tool. Newer tools may report methods
+// this deployment does not list yet; any short lowercase label is accepted
+// so mixed versions keep working during a rollout.
+const checkMethod = <T extends [string, ...string[]]>(known: T) =>
+  z.enum(known).or(z.string().max(32).regex(/^[a-z0-9:/_-]*$/, 'Invalid check method'));
+
+// A local port check; the P2P port is portCheck, every port is in ports
+const portCheckSchema = z.object({
+  listening: z.boolean(),
+  port: z.number().int().positive(),
+  method: checkMethod(['sockstat', 'netstat', 'ss', 'lsof', 'netlink', 'proc', 'netns:/proc', 'netns:nsenter', 'iphlpapi', 'dial', 'manual', 'none']),
+  // Process holding the listening socket, when the tool could see it
+  owner: z.object({
+    pid: z.number().int().positive(),
+    name: z.string().max(256).optional(),
+    isDaemon: z.boolean(),
+  }).optional(),
+  bindAddresses: z.array(z.string().max(64)).max(16).optional(),
+  families: z.array(z.enum(['ipv4', 'ipv6'])).max(2).optional(),
+});
+
 // Verify Node Confirm API (two-step POST-based verification)
 export const verifyNodeConfirmSchema = z.object({
   challenge: z.string().min(20).max(128).regex(/^[a-zA-Z0-9]+$/, 'Challenge must contain only alphanumeric characters'),
   processCheck: z.object({
     found: z.boolean(),
-    method: z.enum(['ps', 'pidof', 'pgrep']),
+    // Released tools send '' instead of 'none' for a check that found nothing
+    method: checkMethod(['systemd', 'proc', 'ps', 'pidof', 'pgrep', 'toolhelp', 'pod-rpc', 'manual', 'none']),
     daemonName: z.string().optional(),
+    evidence: z.object({
+      pid: z.number().int().positive(),
+      exe: z.string().max(4096).optional(),
+      parentPid: z.number().int().nonnegative().optional(),
+      parentName: z.string().max(256).optional(),
+      startTime: z.string().max(64).optional(),
+      unit: z.string().max(256).optional(),
+      package: z.object({
+        format: z.enum(['snap', 'flatpak']),
+        name: z.string().max(256),
+        app: z.string().max(64).optional(),
+      }).optional(),
+    }).optional(),
+    container: z.object({
+      id: z.string().regex(/^[0-9a-f]{64}$/, 'Invalid container ID'),
+      runtime: z.string().max(32).regex(/^[a-z0-9-]+$/, 'Runtime must be a short lowercase label'),
+      name: z.string().max(256).optional(),
+      image: z.string().max(512).optional(),
+      ports: z.array(z.object({
+        containerPort: z.number().int().min(1).max(65535),
+        hostIp: z.string().max(64).optional(),
+        hostPort: z.number().int().min(1).max(65535),
+      })).max(64).optional(),
+    }).optional(),
   }),
-  portCheck: z.object({
-    listening: z.boolean(),
-    port: z.number().int().positive(),
-    method: z.enum(['netstat', 'ss', 'lsof']),
-  }),
+  portCheck: portCheckSchema,
+  // Every port checked, the P2P port first (newer tools)
+  ports: z.array(portCheckSchema.extend({ role: z.enum(['p2p', 'rpc']) })).max(4).optional(),
   systemInfo: z.object({
     hostname: z.string().optional(),
     platform: z.string().optional(),
     arch: z.string().optional(),
+    provider: z.string().max(32).regex(/^[a-z0-9-]+$/, 'Provider must be a short lowercase label').optional(),
+    environment: z.enum(['container', 'vm', 'bare-metal', 'unknown']).optional(),
+    diskFreeGB: z.number().nonnegative().max(1000000).optional(),
+    // Kubernetes pod of a sidecar tool, from the Downward API
+    pod: z.object({
+      name: z.string().max(253).regex(/^[a-z0-9.-]+$/, 'Invalid pod name'),
+      namespace: z.string().max(253).regex(/^[a-z0-9.-]+$/, 'Invalid namespace'),
+      node: z.string().max(253).regex(/^[a-z0-9.-]+$/, 'Invalid node name').optional(),
+    }).optional(),
+  }).optional(),
+  escalation: z.array(z.object({
+    method: z.string().max(32),
+    passed: z.boolean(),
+    detail: z.string().max(512).optional(),
+  })).max(10).optional(),
+  reachabilityProof: z.object({
+    port: z.number().int().positive(),
+    probed: z.boolean(),
+    remoteAddr: z.string().max(64).optional(),
+    error: z.string().max(512).optional(),
+  }).optional(),
+  userAgentCheck: z.object({
+    token: z.string().regex(/^nm-[0-9a-f]{12}$/, 'Invalid user agent token'),
+    method: z.enum(['uacomment']),
+  }).optional(),
+  // The tool can poll confirm/[token] when the server-side checks run long
+  acceptsPolling: z.boolean().optional(),
+  // JSON output of the operator's own check scripts, keyed by script name
+  customChecks: z.record(z.string().regex(/^[A-Za-z0-9_-]{1,64}$/, 'Invalid check name'), z.record(z.unknown()))
+    .refine((checks) => Object.keys(checks).length <= 16, 'Too many custom checks')
+    .refine((checks) => JSON.stringify(checks).length <= 64 * 1024, 'Custom checks too large')
+    .optional(),
+  // How long the binary's init request and local checks took
+  timings: z.object({
+    initMs: z.number().int().min(0).max(3_600_000),
+    processCheckMs: z.number().int().min(0).max(3_600_000),
+    portCheckMs: z.number().int().min(0).max(3_600_000),
+    checksMs: z.number().int().min(0).max(3_600_000),
+  }).optional(),
+  // verify --strict: proofs that don't rest on process names or port tables
+  strict: z.object({
+    rpc: z.object({
+      subversion: z.string().max(256),
+      chain: z.string().max(32),
+      blocks: z.number().int().min(0),
+      bestBlockHash: z.string().regex(/^[0-9a-f]{64}$/, 'Invalid block hash'),
+    }).optional(),
+    signature: z.object({
+      address: z.string().min(20).max(128),
+      signature: z.string().min(1).max(256),
+    }).optional(),
+    handshake: z.boolean().optional(),
   }).optional(),
 });
 
 export type VerifyNodeConfirm = z.infer<typeof verifyNodeConfirmSchema>;
 
+// Confirm body sealed with `verify --encrypt-payload` (see lib/payload-encryption.ts)
+export const sealedPayloadSchema = z.object({
+  alg: z.string().max(32),
+  epk: z.string().max(64),
+  nonce: z.string().max(32),
+  ciphertext: z.string().min(1).max(256 * 1024),
+});
+
+// Verify Node Conversation API: poll admin follow-up questions, send answers
+export const verifyNodeConversationSchema = z.object({
+  challenge: z.string().min(20).max(128).regex(/^[a-zA-Z0-9]+$/, 'Challenge must contain only alphanumeric characters'),
+  answers: z.array(z.object({
+    questionId: z.string().uuid('Invalid question ID format'),
+    body: z.string().trim().min(1).max(2000),
+  })).max(10).optional(),
+});
+
+export type VerifyNodeConversation = z.infer<typeof verifyNodeConversationSchema>;
+
+// Verify Node Pairing: bind a code shown by the binary to a pending verification
+export const verifyNodePairClaimSchema = z.object({
+  code: z.string().trim().toUpperCase()
+    .transform(code => code.replace(/[\s-]/g, ''))
+    .pipe(z.string().regex(/^[A-HJ-NP-Z2-9]{8}$/, 'Pairing code must be the 8 characters shown by the binary')),
+  verificationId: z.string().uuid('Invalid verification ID format'),
+});
+
+export type VerifyNodePairClaim = z.infer<typeof verifyNodePairClaimSchema>;
+
+// Admin: ask a follow-up question on a verification awaiting approval
+export const verificationQuestionSchema = z.object({
+  body: z.string().trim().min(1).max(2000),
+});
+
+export type VerificationQuestion = z.infer<typeof verificationQuestionSchema>;
+
+// Admin: issue a sponsorship/community badge token
+export const issueBadgeTokenSchema = z.object({
+  kind: z.enum(['sponsor', 'community']),
+  label: z.string().trim().min(1).max(64).optional(),
+  validDays: z.number().int().min(1).max(730),
+});
+
+export type IssueBadgeToken = z.infer<typeof issueBadgeTokenSchema>;
+
+// Node owner: attach a badge token to a verified node (verify attach-badge)
+export const attachBadgeSchema = z.object({
+  token: z.string().regex(/^nmb_(sponsor|community)_(\d{10})_[A-Za-z0-9]{24}$/, 'Invalid badge token format'),
+});
+
+export type AttachBadge = z.infer<typeof attachBadgeSchema>;
+
+// Node owner: announce a planned shutdown (verify retire --at YYYY-MM-DD)
+export const retireNodeSchema = z.object({
+  at: z.string().regex(/^\d{4}-\d{2}-\d{2}$/, 'Date must be YYYY-MM-DD')
+    .refine(at => !isNaN(Date.parse(`${at}T00:00:00Z`)) && new Date(`${at}T00:00:00Z`).toISOString().startsWith(at), 'Invalid date'),
+});
+
+export type RetireNode = z.infer<typeof retireNodeSchema>;
+
+// Node owner: attach an operator note (verify note)
+export const nodeNoteSchema = z.object({
+  note: z.string().trim().min(1).max(280)
+    .regex(/^[^\p{Cc}<>]+$/u, 'Note must be a single line without control characters or angle brackets'),
+  public: z.boolean().default(false),
+});
+
+export type NodeNote = z.infer<typeof nodeNoteSchema>;
+
 /**
  * Helper function to validate and parse query parameters
  */
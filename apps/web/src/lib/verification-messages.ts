@@ -0,0 +1,42 @@
+/**
+ * Verification Messages
+ *
+ * Admin follow-up questions on a verification awaiting approval, and the
+ * operator's answers sent with `verify questions`.
+ */
+
+import { createAdminClient } from '@/lib/supabase/server';
+
+export interface VerificationMessage {
+  id: string;
+  author: 'admin' | 'operator';
+  body: string;
+  replyTo: string | null;
+  createdAt: string;
+}
+
+/**
+ * List a verification's messages, oldest first
+ */
+export async function listVerificationMessages(
+  supabase: ReturnType<typeof createAdminClient>,
+  verificationId: string
+): Promise<VerificationMessage[]> {
+  const { data, error } = await supabase
+    .from('verification_messages')
+    .select('id, author, body, reply_to, created_at')
+    .eq('verification_id', verificationId)
+    .order('created_at', { ascending: true });
+
+  if (error) {
+    throw error;
+  }
+
+  return (data || []).map(m => ({
+    id: m.id,
+    author: m.author,
+    body: m.body,
+    replyTo: m.reply_to,
+    createdAt: m.created_at,
+  }));
+}
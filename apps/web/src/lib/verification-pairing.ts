@@ -0,0 +1,34 @@
+/**
+ * Verification Pairing
+ *
+ * `verify` run without a challenge asks for a short pairing code and polls
+ * with a secret token. The operator enters the code in the verification
+ * dialog, which binds it to their pending binary verification, and the next
+ * poll hands the challenge to the binary. No long token is copied over SSH.
+ */
+
+import { randomInt } from 'crypto';
+
+// No 0/O or 1/I, so a code read off a terminal is typed back correctly
+const PAIRING_CODE_ALPHABET = 'ABCDEFGHJKLMNPQRSTUVWXYZ23456789';
+const PAIRING_CODE_LENGTH = 8;
+
+export const PAIRING_TTL_MS = 10 * 60 * 1000; // 10 minutes
+
+/**
+ * Generate a pairing code, stored without the separator
+ */
+export function generatePairingCode(): string {
+  let code = '';
+  for (let i = 0; i < PAIRING_CODE_LENGTH; i++) {
+    code += PAIRING_CODE_ALPHABET[randomInt(PAIRING_CODE_ALPHABET.length)];
+  }
+  return code;
+}
+
+/**
+ * Format a stored code for display, e.g. ABCD-EFGH
+ */
+export function formatPairingCode(code: string): string {
+  return `${code.slice(0, 4)}-${code.slice(4)}`;
+}
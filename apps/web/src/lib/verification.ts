@@ -726,3 +726,14 @@ export function isValidChallenge(method: string, challenge: string): boolean {
       return false;
   }
 }
+
+/**
+ * Derive the user agent token the verify tool asks the operator to advertise
+ * via -uacomment. Must match userAgentToken() in tools/verify.
+ *
+ * @param challenge - The two-step verification challenge
+ * @returns Token of the form "nm-" + 12 hex characters
+ */
+export function userAgentToken(challenge: string): string {
+  return 'nm-' + createHash('sha256').update(challenge).digest('hex').slice(0, 12);
+}
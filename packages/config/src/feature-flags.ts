@@ -61,6 +61,7 @@ export function getFeatureFlags(): ApplicationFeatureFlags {
       paymentCurrency: yaml.verification.paymentCurrency,
       challengeExpiryHours: yaml.verification.challengeExpiryHours,
       autoApprove: yaml.verification.autoApprove,
+      allowAlternatePorts: yaml.verification.allowAlternatePorts ?? false,
     },
 
     // Turnstile (site key and mode from YAML, secret key from ENV)
@@ -259,6 +259,10 @@ export const CrawlerConfigSchema = z.object({
   retryBackoffMultiplier: z.number().min(1).max(10, 'Retry backoff multiplier must be between 1 and 10'),
   fallbackProtocolVersions: z.array(z.number().int().positive('Protocol version must be positive')),
   requireVersionForSave: z.boolean(),
+  revisitWindowMinutes: z.number().int().min(0).optional(),
+  denylist: z.array(z.string().min(1, 'Denylist entry cannot be empty')).optional(),
+  blocklistPublicKey: z.string().regex(/^([A-Za-z0-9+/]{43}=)?$/, 'Blocklist public key must be a base64 Ed25519 key').optional(),
+  payloadPublicKey: z.string().regex(/^([A-Za-z0-9+/]{43}=)?$/, 'Payload public key must be a base64 X25519 key').optional(),
 });
 
 // ===========================================
@@ -323,6 +327,7 @@ export const VerificationFeaturesSchema = z.object({
   paymentCurrency: z.string().min(1, 'Payment currency is required'),
   challengeExpiryHours: PositiveNumberSchema,
   autoApprove: z.boolean(),
+  allowAlternatePorts: z.boolean().default(false),
 });
 
 export const MapFeaturesSchema = z.object({
@@ -55,6 +55,8 @@ export interface VerificationFeatureFlags {
   challengeExpiryHours: number;
   /** Auto-approve verified nodes (skip manual moderation) */
   autoApprove: boolean;
+  /** Accept a verify binary checking another local port (verify --port) */
+  allowAlternatePorts: boolean;
 }
 
 /**
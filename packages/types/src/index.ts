@@ -55,6 +55,7 @@ export interface Node {
   lastSeen: string | null;
   firstSeen: string;
   timesSeen: number;
+  retiringAt: string | null;  // Planned shutdown announced with `verify retire`
 
   // Performance
   latencyMs: number | null;
@@ -314,6 +315,8 @@ export interface ChainConfig {
   addressPrefix?: string;    // e.g., "D" for Dingocoin, "1" or "3" for Bitcoin
   messagePrefix?: string;    // e.g., "Dingocoin Signed Message:\n"
   pubKeyHash?: string;       // Version byte for P2PKH addresses (hex)
+  // P2P network
+  magicBytes?: string;       // Network magic as 8 hex characters, e.g. "c1c1c1c1"
 }
 
 export interface TierColorConfig {
@@ -487,6 +490,10 @@ export interface CrawlerConfig {
   retryBackoffMultiplier: number;
   fallbackProtocolVersions: number[];
   requireVersionForSave: boolean;
+  revisitWindowMinutes?: number;  // Sliding window for backing off unreachable nodes
+  denylist?: string[];            // Extra CIDR ranges never crawled or reported
+  blocklistPublicKey?: string;    // Base64 Ed25519 key verify checks /api/blocklist with
+  payloadPublicKey?: string;      // Base64 X25519 key verify --encrypt-payload seals to
 }
 
 export interface ProjectConfig {
@@ -580,6 +587,7 @@ export interface FeatureFlags {
     paymentCurrency: string;
     challengeExpiryHours: number;
     autoApprove: boolean;
+    allowAlternatePorts?: boolean;
   };
   tipping: {
     enabled: boolean;
@@ -0,0 +1,23 @@
+-- Sponsorship and community badge tokens
+-- Admins issue tokens (only the SHA-256 hash is stored); node owners redeem
+-- them for a verified node with `verify attach-badge`. A redeemed token is a
+-- badge: node_id is set and it shows on the map until expires_at.
+
+CREATE TABLE IF NOT EXISTS node_badge_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    token_hash TEXT NOT NULL UNIQUE,
+    kind TEXT NOT NULL CHECK (kind IN ('sponsor', 'community')),
+    label TEXT CHECK (char_length(label) <= 64),
+    expires_at TIMESTAMPTZ NOT NULL,
+    issued_by UUID,
+    node_id UUID REFERENCES nodes(id) ON DELETE SET NULL,
+    redeemed_by UUID,
+    redeemed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_node_badge_tokens_node ON node_badge_tokens(node_id) WHERE node_id IS NOT NULL;
+
+-- Tokens are only handled by the API (service role)
+ALTER TABLE node_badge_tokens ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS "Service role can manage badge tokens" ON node_badge_tokens;
+CREATE POLICY "Service role can manage badge tokens" ON node_badge_tokens FOR ALL USING (auth.role() = 'service_role');
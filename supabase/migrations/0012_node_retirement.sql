@@ -0,0 +1,79 @@
+-- Planned node shutdowns (verify retire)
+-- Owners announce a shutdown date so the map can show the node as retiring.
+-- Once the date has passed the crawler stops recording snapshots for the
+-- node and its metrics are no longer recalculated, so the uptime record
+-- ends at the shutdown instead of decaying through missed crawls.
+
+ALTER TABLE nodes ADD COLUMN IF NOT EXISTS retiring_at TIMESTAMPTZ;
+ALTER TABLE nodes ADD COLUMN IF NOT EXISTS retirement_scheduled_at TIMESTAMPTZ;
+
+-- Expose retiring_at on the map (appended: CREATE OR REPLACE VIEW can only add columns at the end)
+CREATE OR REPLACE VIEW nodes_public AS
+SELECT
+  n.id,
+  host(n.ip) as ip,
+  n.port,
+  n.address,
+  n.chain,
+  n.status,
+  (n.status = 'up') as is_online,
+  n.country_code,
+  n.country_name,
+  n.city,
+  n.latitude,
+  n.longitude,
+  n.region,
+  n.timezone,
+  n.isp,
+  n.org,
+  n.asn,
+  n.asn_org,
+  n.connection_type,
+  n.version,
+  n.client_version,
+  n.client_name,
+  n.protocol_version,
+  n.is_current_version,
+  n.version_major,
+  n.version_minor,
+  n.version_patch,
+  n.services,
+  n.start_height,
+  n.times_seen,
+  n.uptime as uptime_percentage,
+  n.latency_avg,
+  n.reliability,
+  n.tier,
+  n.pix_score,
+  n.rank,
+  n.is_verified,
+  n.tips_enabled,
+  n.first_seen,
+  n.last_seen,
+  p.display_name,
+  p.description,
+  p.avatar_url,
+  p.website,
+  p.twitter,
+  p.discord,
+  p.telegram,
+  p.github,
+  p.tags,
+  COALESCE(p.is_public, true) as is_public,
+  n.retiring_at
+FROM nodes n
+LEFT JOIN node_profiles p ON n.id = p.node_id AND p.is_public = true;
+
+GRANT SELECT ON nodes_public TO anon, authenticated;
+
+-- Skip retired nodes when recalculating metrics
+CREATE OR REPLACE FUNCTION calculate_all_node_metrics()
+RETURNS TABLE(updated_count INTEGER) AS $$
+DECLARE node_record RECORD; count INTEGER := 0;
+BEGIN
+    FOR node_record IN SELECT id FROM nodes WHERE retiring_at IS NULL OR retiring_at > NOW() LOOP PERFORM calculate_node_metrics(node_record.id); count := count + 1; END LOOP;
+    WITH ranked AS (SELECT id, ROW_NUMBER() OVER (ORDER BY pix_score DESC NULLS LAST, uptime DESC NULLS LAST, latency_avg ASC NULLS LAST) as new_rank FROM nodes WHERE status = 'up')
+    UPDATE nodes n SET rank = ranked.new_rank FROM ranked WHERE n.id = ranked.id;
+    RETURN QUERY SELECT count;
+END;
+$$ LANGUAGE plpgsql;
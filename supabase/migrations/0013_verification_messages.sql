@@ -0,0 +1,23 @@
+-- Admin follow-up questions on verifications awaiting approval
+-- Admins ask questions on a pending_approval verification; the operator
+-- answers from the node with `verify questions <challenge>` (authenticated
+-- by the challenge). Answers reference the question they reply to.
+
+CREATE TABLE IF NOT EXISTS verification_messages (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    verification_id UUID NOT NULL REFERENCES verifications(id) ON DELETE CASCADE,
+    author TEXT NOT NULL CHECK (author IN ('admin', 'operator')),
+    reply_to UUID REFERENCES verification_messages(id) ON DELETE CASCADE,
+    body TEXT NOT NULL CHECK (char_length(body) BETWEEN 1 AND 2000),
+    created_by UUID,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_verification_messages_verification ON verification_messages(verification_id, created_at);
+-- One answer per question
+CREATE UNIQUE INDEX IF NOT EXISTS idx_verification_messages_reply ON verification_messages(reply_to) WHERE reply_to IS NOT NULL;
+
+ALTER TABLE verification_messages ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS "Users can view messages on own verifications" ON verification_messages;
+CREATE POLICY "Users can view messages on own verifications" ON verification_messages FOR SELECT USING (auth.uid() = (SELECT user_id FROM verifications WHERE id = verification_messages.verification_id));
+DROP POLICY IF EXISTS "Service role can manage verification messages" ON verification_messages;
+CREATE POLICY "Service role can manage verification messages" ON verification_messages FOR ALL USING (auth.role() = 'service_role');
@@ -0,0 +1,21 @@
+-- Operator notes and incident annotations (verify note)
+-- Owners attach short notes to a node ("migrating disks tonight"). Admins
+-- see all notes; public notes also show on the node's detail page.
+
+CREATE TABLE IF NOT EXISTS node_operator_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    node_id UUID NOT NULL REFERENCES nodes(id) ON DELETE CASCADE,
+    user_id UUID,
+    body TEXT NOT NULL CHECK (char_length(body) BETWEEN 1 AND 280),
+    is_public BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_node_operator_notes_node ON node_operator_notes(node_id, created_at DESC);
+
+ALTER TABLE node_operator_notes ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS "Public notes are viewable by everyone" ON node_operator_notes;
+CREATE POLICY "Public notes are viewable by everyone" ON node_operator_notes FOR SELECT USING (is_public = true);
+DROP POLICY IF EXISTS "Users can view own notes" ON node_operator_notes;
+CREATE POLICY "Users can view own notes" ON node_operator_notes FOR SELECT USING (auth.uid() = user_id);
+DROP POLICY IF EXISTS "Service role can manage operator notes" ON node_operator_notes;
+CREATE POLICY "Service role can manage operator notes" ON node_operator_notes FOR ALL USING (auth.role() = 'service_role');
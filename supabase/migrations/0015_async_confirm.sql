@@ -0,0 +1,7 @@
+-- Asynchronous confirm (verify polls for slow server-side checks)
+-- When the map's own probes outlast the confirm request, the tool gets a
+-- poll token and fetches the stored confirm response once they finish.
+
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS poll_token TEXT;
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS confirm_result JSONB;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_verifications_poll_token ON verifications(poll_token) WHERE poll_token IS NOT NULL;
@@ -0,0 +1,22 @@
+-- Terminal pairing (verify without a challenge)
+-- The tool asks for a short code and polls with a secret token; the
+-- operator enters the code in the verification dialog, which binds it to
+-- their pending verification, and the next poll hands over the challenge.
+
+CREATE TABLE IF NOT EXISTS verification_pairings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code TEXT NOT NULL,
+    poll_token TEXT NOT NULL UNIQUE,
+    verification_id UUID REFERENCES verifications(id) ON DELETE CASCADE,
+    ip_address INET,
+    claimed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+-- Codes are short, so only one unclaimed pairing may hold a code at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_verification_pairings_open_code ON verification_pairings(code) WHERE claimed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_verification_pairings_expires ON verification_pairings(expires_at);
+
+ALTER TABLE verification_pairings ENABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS "Service role can manage verification pairings" ON verification_pairings;
+CREATE POLICY "Service role can manage verification pairings" ON verification_pairings FOR ALL USING (auth.role() = 'service_role');
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/atlasp2p/verify/internal/flags"
+)
+
+// Uptime is the public /api/nodes/<id>/uptime aggregate. Percent is nil
+// when the node has no snapshots in the period.
+type Uptime struct {
+	Total   int      `json:"total"`
+	Online  int      `json:"online"`
+	Percent *float64 `json:"uptime"`
+}
+
+// runBadge renders an SVG uptime badge for a node from the public API
+func runBadge(args []string) {
+	fs := flag.NewFlagSet("badge", flag.ExitOnError)
+	window := flags.Duration(30 * 24 * time.Hour)
+	fs.Var(&window, "window", "Availability window in whole days (e.g. 30d or 90d)")
+	output := fs.String("o", "", "Write the SVG to this file instead of stdout")
+	fs.Usage = commandUsage("badge")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 || !nodeIDPattern.MatchString(fs.Arg(0)) {
+		fs.Usage()
+		os.Exit(1)
+	}
+	day := 24 * time.Hour
+	days := int(time.Duration(window) / day)
+	if time.Duration(window)%day != 0 || days < 1 || days > 365 {
+		log.Fatalf("❌ Invalid --window %s: must be whole days between 1d and 365d", window.String())
+	}
+
+	uptime, err := fetchUptime(fs.Arg(0), days)
+	if err != nil {
+		log.Fatalf("❌ Failed to fetch uptime: %v", err)
+	}
+
+	svg := renderUptimeBadge(fmt.Sprintf("uptime %dd", days), uptime)
+
+	if *output == "" {
+		fmt.Print(svg)
+		return
+	}
+	if err := os.WriteFile(*output, []byte(svg), 0644); err != nil {
+		log.Fatalf("❌ Failed to write badge: %v", err)
+	}
+	fmt.Printf("✅ Badge written to %s\n", *output)
+}
+
+// fetchUptime asks the API for the node's uptime over the last days. The
+// counting happens server-side; raw snapshots are capped at the API's row
+// limit and would cover only a few days.
+func fetchUptime(nodeID string, days int) (*Uptime, error) {
+	url := fmt.Sprintf("%s/api/nodes/%s/uptime?period=%d", ApiUrl, nodeID, days)
+
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to API: %w", explainTLSError(err))
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned %s", resp.Status)
+	}
+
+	var uptime Uptime
+	if _, err := decodeResponse(body, &uptime); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &uptime, nil
+}
+
+// renderUptimeBadge draws a flat shields-style badge with the node's
+// uptime, colored by availability. Text is XML-escaped.
+func renderUptimeBadge(label string, uptime *Uptime) string {
+	value := "no data"
+	color := "#9f9f9f"
+
+	if uptime != nil && uptime.Percent != nil {
+		pct := *uptime.Percent
+		value = fmt.Sprintf("%.1f%%", pct)
+
+		switch {
+		case pct >= 99:
+			color = "#4c1"
+		case pct >= 95:
+			color = "#97ca00"
+		case pct >= 90:
+			color = "#dfb317"
+		default:
+			color = "#e05d44"
+		}
+	}
+
+	// Approximate Verdana 11px glyph width
+	labelW := utf8.RuneCountInString(label)*7 + 10
+	valueW := utf8.RuneCountInString(value)*7 + 10
+	label, value = html.EscapeString(label), html.EscapeString(value)
+	title := html.EscapeString(ChainName) + " " + label
+	total := labelW + valueW
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`, total, label, value)
+	fmt.Fprintf(&b, `<title>%s: %s</title>`, title, value)
+	fmt.Fprintf(&b, `<rect width="%d" height="20" rx="3" fill="#555"/>`, total)
+	fmt.Fprintf(&b, `<rect x="%d" width="%d" height="20" rx="3" fill="%s"/>`, labelW, valueW, color)
+	fmt.Fprintf(&b, `<rect x="%d" width="4" height="20" fill="%s"/>`, labelW, color)
+	b.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, labelW/2, label)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, labelW+valueW/2, value)
+	b.WriteString("</g></svg>\n")
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderUptimeBadge(t *testing.T) {
+	pct := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name   string
+		label  string
+		uptime *Uptime
+		value  string
+		color  string
+	}{
+		{"no response", "uptime 30d", nil, "no data", "#9f9f9f"},
+		{"no snapshots", "uptime 30d", &Uptime{}, "no data", "#9f9f9f"},
+		{"excellent", "uptime 30d", &Uptime{Total: 8640, Online: 8640, Percent: pct(100)}, "100.0%", "#4c1"},
+		{"good", "uptime 7d", &Uptime{Percent: pct(96.5)}, "96.5%", "#97ca00"},
+		{"fair", "uptime 7d", &Uptime{Percent: pct(91)}, "91.0%", "#dfb317"},
+		{"poor", "uptime 90d", &Uptime{Percent: pct(42.25)}, "42.2%", "#e05d44"},
+	}
+
+	for _, tt := range tests {
+		svg := renderUptimeBadge(tt.label, tt.uptime)
+		if !strings.Contains(svg, ">"+tt.value+"</text>") {
+			t.Errorf("%s: badge lacks value %q:\n%s", tt.name, tt.value, svg)
+		}
+		if !strings.Contains(svg, `fill="`+tt.color+`"`) {
+			t.Errorf("%s: badge lacks color %s", tt.name, tt.color)
+		}
+	}
+}
+
+func TestRenderUptimeBadgeEscapes(t *testing.T) {
+	saved := ChainName
+	ChainName = `Coin<&>"`
+	defer func() { ChainName = saved }()
+
+	svg := renderUptimeBadge(`up <"time"> & more`, nil)
+	for _, raw := range []string{`<"time">`, `Coin<&>`, "& more"} {
+		if strings.Contains(svg, raw) {
+			t.Errorf("badge contains unescaped %q:\n%s", raw, svg)
+		}
+	}
+	if !strings.Contains(svg, "up &lt;&#34;time&#34;&gt; &amp; more") {
+		t.Errorf("badge lacks the escaped label:\n%s", svg)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// A challenges file holds a few dozen tokens at most
+const maxChallengesInput = 64 << 10
+
+// readChallenges reads a file of challenges, one per line. Blank lines and
+// lines starting with # are skipped, and a challenge listed twice is only
+// verified once.
+func readChallenges(r io.Reader) ([]string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxChallengesInput+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxChallengesInput {
+		return nil, fmt.Errorf("more than %d bytes, not a list of challenges", maxChallengesInput)
+	}
+	var challenges []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !isValidChallenge(line) {
+			return nil, fmt.Errorf("line %d is not a challenge", n)
+		}
+		if !seen[line] {
+			seen[line] = true
+			challenges = append(challenges, line)
+		}
+	}
+	if len(challenges) == 0 {
+		return nil, errors.New("no challenges found")
+	}
+	return challenges, nil
+}
+
+// dropFlag removes flag name and its value from args, in any of the forms
+// the flag package accepts
+func dropFlag(args []string, name string) []string {
+	var out []string
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimLeft(args[i], "-")
+		switch {
+		case !strings.HasPrefix(args[i], "-"):
+		case arg == name:
+			i++
+			continue
+		case strings.HasPrefix(arg, name+"="):
+			continue
+		}
+		out = append(out, args[i])
+	}
+	return out
+}
+
+// batchOutcome is one challenge's run
+type batchOutcome struct {
+	Code   int     `json:"exitCode"`
+	Result *Result `json:"result"`
+}
+
+// runBatch verifies the challenges one after another, each by running the
+// tool itself with args, and ends with a summary table. The challenge goes
+// to the child on stdin so it stays out of the process list, and the child
+// reports its result as JSON. jsonOut receives all results as a JSON array
+// when asJSON is set. Returns the exit code of the first failed
+// verification, or 0 when all passed.
+func runBatch(challenges, args []string, asJSON bool, jsonOut io.Writer) int {
+	self, err := os.Executable()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	args = append(append([]string{}, args...), "--json", "--challenge-file", "-")
+
+	outcomes := make([]batchOutcome, len(challenges))
+	for i, challenge := range challenges {
+		fmt.Print(tr("━━ Challenge %d of %d ━━\n", i+1, len(challenges)))
+		var stdout bytes.Buffer
+		cmd := exec.Command(self, args...)
+		cmd.Stdin = strings.NewReader(challenge + "\n")
+		cmd.Stdout, cmd.Stderr = &stdout, os.Stderr
+		code := 0
+		if err := cmd.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				fmt.Printf("❌ %v\n", err)
+				return 1
+			}
+			code = exitErr.ExitCode()
+		}
+		result := newResult()
+		if err := json.Unmarshal(stdout.Bytes(), result); err != nil {
+			// The run ended before it had a result, e.g. on a bad option
+			result.Error = fmt.Sprintf("exited with code %d", code)
+		}
+		outcomes[i] = batchOutcome{code, result}
+		fmt.Println()
+	}
+
+	printBatchSummary(os.Stdout, outcomes)
+	if asJSON {
+		enc := json.NewEncoder(jsonOut)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(outcomes); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Failed to write JSON result: %v\n", err)
+		}
+	}
+	for _, o := range outcomes {
+		if o.Code != 0 {
+			return o.Code
+		}
+	}
+	return 0
+}
+
+// printBatchSummary prints a line per challenge: the node, the outcome and
+// what the map or the error said
+func printBatchSummary(w io.Writer, outcomes []batchOutcome) {
+	passed := 0
+	for _, o := range outcomes {
+		if o.Code == 0 {
+			passed++
+		}
+	}
+	fmt.Fprint(w, tr("Summary: %d of %d verified\n", passed, len(outcomes)))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "  #\t%s\t%s\t%s\n", tr("Node"), tr("Outcome"), tr("Details"))
+	for i, o := range outcomes {
+		node := "-"
+		if o.Result.Node.IP != "" {
+			node = net.JoinHostPort(o.Result.Node.IP, strconv.Itoa(o.Result.Node.Port))
+		}
+		outcome, details := o.Result.Status, o.Result.Message
+		if o.Result.DryRun {
+			outcome = "dry run"
+		}
+		if o.Code != 0 {
+			outcome, details = fmt.Sprintf("failed (exit %d)", o.Code), o.Result.Error
+		}
+		// Errors that span lines (one per address family) keep their first
+		details, _, _ = strings.Cut(details, "\n")
+		fmt.Fprintf(tw, "  %d\t%s\t%s\t%s\n", i+1, node, outcome, details)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadChallenges(t *testing.T) {
+	input := `# nodes behind jump host
+abc123xyz456def789ghi0
+
+  zzz999yyy888xxx777www6
+abc123xyz456def789ghi0
+`
+	got, err := readChallenges(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"abc123xyz456def789ghi0", "zzz999yyy888xxx777www6"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readChallenges = %v, want %v", got, want)
+	}
+
+	if _, err := readChallenges(strings.NewReader("abc123xyz456def789ghi0\nnot a challenge\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("invalid line: err = %v", err)
+	}
+	if _, err := readChallenges(strings.NewReader("# nothing here\n")); err == nil {
+		t.Error("empty list accepted")
+	}
+}
+
+func TestDropFlag(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"--challenges-file", "nodes.txt", "--dry-run"}, []string{"--dry-run"}},
+		{[]string{"-challenges-file=nodes.txt", "--json"}, []string{"--json"}},
+		{[]string{"--datadir", "/srv/challenges-file"}, []string{"--datadir", "/srv/challenges-file"}},
+		{[]string{"--challenge-file", "-"}, []string{"--challenge-file", "-"}},
+	}
+	for _, tt := range tests {
+		if got := dropFlag(tt.args, "challenges-file"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("dropFlag(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestPrintBatchSummary(t *testing.T) {
+	outcomes := []batchOutcome{
+		{0, &Result{Node: NodeAddress{IP: "203.0.113.7", Port: 33117}, Status: "verified", Message: "Node verified"}},
+		{exitPortNotListening, &Result{Node: NodeAddress{IP: "2001:db8::1", Port: 33117}, Error: "port not listening"}},
+		{exitChallengeExpired, &Result{Error: "challenge expired\nsecond line"}},
+	}
+	var out strings.Builder
+	printBatchSummary(&out, outcomes)
+	got := out.String()
+
+	for _, want := range []string{
+		"Summary: 1 of 3 verified",
+		"203.0.113.7:33117    verified",
+		"[2001:db8::1]:33117",
+		"failed (exit 3)",
+		"-",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary lacks %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "second line") {
+		t.Errorf("summary has a multi-line error:\n%s", got)
+	}
+}
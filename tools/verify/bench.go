@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/atlasp2p/verify/internal/flags"
+)
+
+// runBenchChecks times every detection backend on this host (hidden
+// command). The numbers feed decisions about the default strategy order.
+func runBenchChecks(args []string) {
+	fs := flag.NewFlagSet("bench-checks", flag.ExitOnError)
+	rounds := fs.Int("n", 5, "Number of runs per backend")
+	port := defaultPort
+	fs.Var(&port, "port", "Port to probe")
+	dialTimeout := flags.Duration(3 * time.Second)
+	fs.Var(&dialTimeout, "dial-timeout", "Timeout for the port/dial backend")
+	fs.Usage = commandUsage("bench-checks")
+	fs.Parse(args)
+
+	if *rounds < 1 {
+		*rounds = 1
+	}
+	daemon := primaryDaemon()
+	p := int(port)
+	ctx := context.Background()
+
+	backends := []struct {
+		name string
+		run  func() bool
+	}{
+		{"process/native", func() bool { ok, _ := checkProcessNative(daemon); return ok }},
+		{"process/ps", func() bool { ok, _ := checkProcessPS(ctx, daemon); return ok }},
+		{"process/pidof", func() bool { ok, _ := checkProcessPidof(ctx, daemon); return ok }},
+		{"process/pgrep", func() bool { ok, _ := checkProcessPgrep(ctx, daemon); return ok }},
+		{"process/validate", func() bool { _, ok := validateDaemonProcess(ctx, daemon, p); return ok }},
+		{"port/netstat", func() bool { ok, _ := checkPortNetstat(ctx, p); return ok }},
+		{"port/ss", func() bool { ok, _ := checkPortSS(ctx, p); return ok }},
+		{"port/lsof", func() bool { ok, _ := checkPortLsof(ctx, p); return ok }},
+		{"port/native", func() bool { ok, _ := checkPortNative(p); return ok }},
+		{"port/owner", func() bool { _, _, ok := listeningSocketOwner(p); return ok }},
+		{"port/dial", func() bool {
+			conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(p)), time.Duration(dialTimeout))
+			if err != nil {
+				return false
+			}
+			conn.Close()
+			return true
+		}},
+	}
+
+	fmt.Printf("Benchmarking detection backends (%d runs each, daemon %s, port %d)\n\n", *rounds, daemon, p)
+	fmt.Printf("%-18s %10s %10s %10s  %s\n", "BACKEND", "AVG", "MIN", "MAX", "RESULT")
+	for _, b := range backends {
+		var total, min, max time.Duration
+		found := false
+		for i := 0; i < *rounds; i++ {
+			start := time.Now()
+			found = b.run()
+			d := time.Since(start)
+			total += d
+			if i == 0 || d < min {
+				min = d
+			}
+			if d > max {
+				max = d
+			}
+		}
+		avg := total / time.Duration(*rounds)
+		fmt.Printf("%-18s %10s %10s %10s  %v\n", b.name, round(avg), round(min), round(max), found)
+	}
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+func BenchmarkNetlinkListeners(b *testing.B) {
+	benchListener(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := netlinkListeners(); err != nil {
+			b.Skipf("inet_diag unavailable: %v", err)
+		}
+	}
+}
+
+func BenchmarkProcListenInodes(b *testing.B) {
+	port := benchListener(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		procListenInodes(port)
+	}
+}
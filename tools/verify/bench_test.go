@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// Benchmarks for the detection backends, the go test counterpart of the
+// bench-checks command. Process checks look for the test binary itself and
+// port checks for a listener opened by the benchmark.
+
+func benchDaemon() string {
+	return filepath.Base(os.Args[0])
+}
+
+// benchListener opens a local listener and returns its port
+func benchListener(b *testing.B) int {
+	b.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { ln.Close() })
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func requireTool(b *testing.B, name string) {
+	b.Helper()
+	if _, err := exec.LookPath(name); err != nil {
+		b.Skipf("%s not installed", name)
+	}
+}
+
+func BenchmarkCheckProcessPS(b *testing.B) {
+	requireTool(b, "ps")
+	daemon := benchDaemon()
+	for i := 0; i < b.N; i++ {
+		checkProcessPS(context.Background(), daemon)
+	}
+}
+
+func BenchmarkCheckProcessPidof(b *testing.B) {
+	requireTool(b, "pidof")
+	daemon := benchDaemon()
+	for i := 0; i < b.N; i++ {
+		checkProcessPidof(context.Background(), daemon)
+	}
+}
+
+func BenchmarkCheckProcessPgrep(b *testing.B) {
+	requireTool(b, "pgrep")
+	daemon := benchDaemon()
+	for i := 0; i < b.N; i++ {
+		checkProcessPgrep(context.Background(), daemon)
+	}
+}
+
+func BenchmarkValidateDaemonProcess(b *testing.B) {
+	requireTool(b, "ps")
+	daemon := benchDaemon()
+	for i := 0; i < b.N; i++ {
+		validateDaemonProcess(context.Background(), daemon, 0)
+	}
+}
+
+func BenchmarkCheckPortNative(b *testing.B) {
+	port := benchListener(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		checkPortNative(port)
+	}
+}
+
+func BenchmarkCheckPortNetstat(b *testing.B) {
+	requireTool(b, "netstat")
+	port := benchListener(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		checkPortNetstat(context.Background(), port)
+	}
+}
+
+func BenchmarkCheckPortSS(b *testing.B) {
+	requireTool(b, "ss")
+	port := benchListener(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		checkPortSS(context.Background(), port)
+	}
+}
+
+func BenchmarkCheckPortLsof(b *testing.B) {
+	requireTool(b, "lsof")
+	port := benchListener(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		checkPortLsof(context.Background(), port)
+	}
+}
+
+func BenchmarkListeningSocketOwner(b *testing.B) {
+	port := benchListener(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		listeningSocketOwner(port)
+	}
+}
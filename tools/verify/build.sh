@@ -11,6 +11,10 @@ BINARY_NAME="verify"
 # Configuration from environment variables (REQUIRED - no defaults)
 # CI/CD extracts these from config/project.config.yaml
 # For local builds, set these env vars or use: source .env
+# RPC_PORT is optional and enables RPC-based checks (e.g. chain sanity check)
+# BLOCKLIST_PUBLIC_KEY is optional and lets `verify peers` check the
+# blocklist signature
+# PAYLOAD_PUBLIC_KEY is optional and pins the key --encrypt-payload seals to
 if [ -z "$API_URL" ] || [ -z "$DAEMON_NAMES" ] || [ -z "$DEFAULT_PORT" ] || [ -z "$CHAIN_NAME" ]; then
     echo "ERROR: Required environment variables not set"
     echo "  API_URL, DAEMON_NAMES, DEFAULT_PORT, CHAIN_NAME"
@@ -29,6 +33,9 @@ echo "  API URL:      $API_URL"
 echo "  Daemon Names: $DAEMON_NAMES"
 echo "  Default Port: $DEFAULT_PORT"
 echo "  Chain Name:   $CHAIN_NAME"
+echo "  RPC Port:     ${RPC_PORT:-(not set)}"
+echo "  Blocklist:    ${BLOCKLIST_PUBLIC_KEY:-(not set)}"
+echo "  Payload Key:  ${PAYLOAD_PUBLIC_KEY:-(not set)}"
 echo ""
 
 # Create output directory
@@ -52,7 +59,10 @@ build_platform() {
             -X main.ApiUrl=$API_URL \
             -X main.DaemonNames=$DAEMON_NAMES \
             -X main.DefaultPort=$DEFAULT_PORT \
-            -X main.ChainName=$CHAIN_NAME" \
+            -X main.ChainName=$CHAIN_NAME \
+            -X main.RpcPort=$RPC_PORT \
+            -X main.BlocklistKey=$BLOCKLIST_PUBLIC_KEY \
+            -X main.PayloadKey=$PAYLOAD_PUBLIC_KEY" \
         -trimpath \
         -o "$OUTPUT_DIR/$FILENAME" \
         .
@@ -74,6 +84,8 @@ build_platform "linux" "arm64" "" "Linux (ARM64)"
 build_platform "darwin" "amd64" "" "macOS (Intel)"
 build_platform "darwin" "arm64" "" "macOS (Apple Silicon)"
 build_platform "windows" "amd64" ".exe" "Windows (x86_64)"
+build_platform "freebsd" "amd64" "" "FreeBSD (x86_64)"
+build_platform "openbsd" "amd64" "" "OpenBSD (x86_64)"
 
 echo ""
 echo "✅ Build complete! Binaries available at:"
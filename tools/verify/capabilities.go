@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Capabilities is the set of evidence sources this run can use. It is
+// probed once, so every check picks its strategy from the same answers
+// instead of trying each source in turn and failing noisily.
+type Capabilities struct {
+	ProcFS       bool            `json:"procfs"`       // /proc/net/tcp readable (Linux)
+	Netlink      bool            `json:"netlink"`      // inet_diag socket dumps allowed (Linux)
+	OtherFDs     bool            `json:"otherFds"`     // other users' /proc/<pid>/fd readable (socket owner lookups)
+	DockerSocket bool            `json:"dockerSocket"` // /var/run/docker.sock accepts connections
+	RPCAuth      string          `json:"rpcAuth"`      // "config", "cookie" or "" when RPC can't authenticate
+	RPCError     string          `json:"rpcError,omitempty"`
+	Tools        map[string]bool `json:"tools"` // external commands found in PATH
+}
+
+// External commands the checks can fall back to
+var probedTools = []string{"systemctl", "ps", "pidof", "pgrep", "sockstat", "netstat", "ss", "lsof", "nsenter"}
+
+const dockerSocket = "/var/run/docker.sock"
+
+var (
+	capsOnce sync.Once
+	caps     Capabilities
+)
+
+// capabilities returns the probed set. The first call probes, so it must
+// come after --datadir and --rpc-addr are parsed.
+func capabilities() Capabilities {
+	capsOnce.Do(func() {
+		caps = probeCapabilities()
+	})
+	return caps
+}
+
+func probeCapabilities() Capabilities {
+	c := Capabilities{Tools: make(map[string]bool)}
+	for _, tool := range probedTools {
+		_, err := exec.LookPath(tool)
+		c.Tools[tool] = err == nil
+	}
+	c.ProcFS, c.Netlink, c.OtherFDs = probeKernelSources()
+
+	if conn, err := net.DialTimeout("unix", dockerSocket, 500*time.Millisecond); err == nil {
+		conn.Close()
+		c.DockerSocket = true
+	}
+
+	conf, _ := readDaemonConf(daemonConfPath())
+	if _, _, source, err := rpcCredentials(conf); err != nil {
+		c.RPCError = err.Error()
+	} else {
+		c.RPCAuth = source
+	}
+	return c
+}
+
+// processMethods lists the process lookups checkProcess tries on goos, in
+// order
+func processMethods(goos string, c Capabilities) []string {
+	var methods []string
+	// The unit's main PID is checked against the process table, so systemd
+	// needs /proc or ps as well
+	if goos == "linux" && c.Tools["systemctl"] && (c.ProcFS || c.Tools["ps"]) {
+		methods = append(methods, "systemd")
+	}
+	switch {
+	case goos == "windows":
+		methods = append(methods, "toolhelp")
+	case goos == "linux" && c.ProcFS:
+		methods = append(methods, "proc")
+	}
+	for _, tool := range []string{"ps", "pidof", "pgrep"} {
+		if c.Tools[tool] {
+			methods = append(methods, tool)
+		}
+	}
+	return methods
+}
+
+// portMethods lists the socket lookups checkPort tries on goos, in order
+func portMethods(goos string, c Capabilities) []string {
+	var methods []string
+	switch {
+	case goos == "windows":
+		methods = append(methods, "iphlpapi")
+	case c.Netlink:
+		methods = append(methods, "netlink")
+	case c.ProcFS:
+		methods = append(methods, "proc")
+	}
+	for _, tool := range []string{"sockstat", "netstat", "ss", "lsof"} {
+		if c.Tools[tool] {
+			methods = append(methods, tool)
+		}
+	}
+	return append(methods, "dial")
+}
+
+func yesNo(ok bool) string {
+	if ok {
+		return "✅"
+	}
+	return "❌"
+}
+
+// runDoctor prints the capability set and the strategies chosen from it,
+// and whether the data directory and the map can be reached
+func runDoctor(args []string) {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Print the capability set and the checks as JSON")
+	fs.StringVar(&dataDir, "datadir", defaultDataDir(), "Daemon data directory (config, .cookie)")
+	fs.Var(&rpcAddr, "rpc-addr", "Daemon RPC host:port (default: 127.0.0.1 and rpcport from the config)")
+	fs.Var(&apiTimeout, "api-timeout", "Time limit for the checks that reach the map")
+	fs.Usage = commandUsage("doctor")
+	fs.Parse(args)
+	httpClient.Timeout = time.Duration(apiTimeout)
+
+	c := capabilities()
+	support := platformSupport()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(apiTimeout))
+	checks := environmentChecks(ctx, ApiUrl, dataDir)
+	cancel()
+	code := doctorExitCode(checks, support)
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(struct {
+			Capabilities
+			Support SupportReport `json:"support"`
+			Checks  []DoctorCheck `json:"checks"`
+		}{c, support, checks})
+		os.Exit(code)
+	}
+
+	fmt.Println("Environment:")
+	for _, check := range checks {
+		fmt.Printf("  %s %s: %s\n", yesNo(check.OK), check.Name, check.Detail)
+	}
+	fmt.Println()
+
+	fmt.Println("Evidence sources:")
+	if runtime.GOOS == "linux" {
+		fmt.Printf("  %s /proc/net/tcp readable\n", yesNo(c.ProcFS))
+		fmt.Printf("  %s netlink socket dumps\n", yesNo(c.Netlink))
+		fmt.Printf("  %s other users' /proc/<pid>/fd (needs root)\n", yesNo(c.OtherFDs))
+	}
+	fmt.Printf("  %s Docker socket (%s)\n", yesNo(c.DockerSocket), dockerSocket)
+	if c.RPCAuth != "" {
+		fmt.Printf("  ✅ RPC credentials (%s)\n", c.RPCAuth)
+	} else {
+		fmt.Printf("  ❌ RPC credentials: %s\n", c.RPCError)
+	}
+	for _, tool := range probedTools {
+		fmt.Printf("  %s %s\n", yesNo(c.Tools[tool]), tool)
+	}
+	fmt.Println()
+
+	fmt.Println("Strategies:")
+	for _, s := range []struct {
+		name    string
+		methods []string
+	}{
+		{"Process check", support.ProcessMethods},
+		{"Port check", support.PortMethods},
+	} {
+		if len(s.methods) > 0 {
+			fmt.Printf("  %s: %s\n", s.name, strings.Join(s.methods, " → "))
+		}
+	}
+	for _, issue := range support.Issues {
+		fmt.Printf("  ❌ No usable %s check: %s\n", issue.Check, issue.Fix)
+	}
+	if !support.Supported {
+		fmt.Printf("  verify refuses to run on %s/%s until this is fixed\n", support.OS, support.Arch)
+	}
+	os.Exit(code)
+}
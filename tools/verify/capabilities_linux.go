@@ -0,0 +1,23 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// probeKernelSources checks the procfs and netlink interfaces the native
+// checks use. Other users' fds are only readable as root (or with
+// CAP_SYS_PTRACE), which is tested on PID 1.
+func probeKernelSources() (procFS, netlink, otherFDs bool) {
+	_, err := os.ReadFile("/proc/net/tcp")
+	procFS = err == nil
+
+	_, err = netlinkListeners()
+	netlink = err == nil
+
+	if os.Getpid() != 1 {
+		_, err = os.ReadDir(filepath.Join("/proc", "1", "fd"))
+		otherFDs = err == nil
+	}
+	return procFS, netlink, otherFDs
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package main
+
+// procfs and netlink are Linux interfaces
+func probeKernelSources() (procFS, netlink, otherFDs bool) {
+	return false, false, false
+}
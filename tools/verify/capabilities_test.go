@@ -0,0 +1,59 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckMethods(t *testing.T) {
+	tests := []struct {
+		name        string
+		caps        Capabilities
+		wantProcess []string
+		wantPort    []string
+	}{
+		{
+			name:        "full host",
+			caps:        Capabilities{ProcFS: true, Netlink: true, Tools: map[string]bool{"ps": true, "pidof": true, "pgrep": true, "netstat": true, "ss": true, "lsof": true}},
+			wantProcess: []string{"proc", "ps", "pidof", "pgrep"},
+			wantPort:    []string{"netlink", "netstat", "ss", "lsof", "dial"},
+		},
+		{
+			name:        "netlink blocked by seccomp",
+			caps:        Capabilities{ProcFS: true, Tools: map[string]bool{"ps": true, "ss": true}},
+			wantProcess: []string{"proc", "ps"},
+			wantPort:    []string{"proc", "ss", "dial"},
+		},
+		{
+			name:        "systemd host",
+			caps:        Capabilities{ProcFS: true, Tools: map[string]bool{"systemctl": true}},
+			wantProcess: []string{"systemd", "proc"},
+			wantPort:    []string{"proc", "dial"},
+		},
+		{
+			name:     "distroless container",
+			caps:     Capabilities{Tools: map[string]bool{}},
+			wantPort: []string{"dial"},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := processMethods("linux", tt.caps); !reflect.DeepEqual(got, tt.wantProcess) {
+			t.Errorf("%s: processMethods = %v, want %v", tt.name, got, tt.wantProcess)
+		}
+		if got := portMethods("linux", tt.caps); !reflect.DeepEqual(got, tt.wantPort) {
+			t.Errorf("%s: portMethods = %v, want %v", tt.name, got, tt.wantPort)
+		}
+	}
+
+	// FreeBSD lists sockets with sockstat before netstat
+	freebsd := Capabilities{Tools: map[string]bool{"ps": true, "pgrep": true, "sockstat": true, "netstat": true}}
+	if got := portMethods("freebsd", freebsd); !reflect.DeepEqual(got, []string{"sockstat", "netstat", "dial"}) {
+		t.Errorf("freebsd: portMethods = %v", got)
+	}
+
+	// Windows always has its native lookups first
+	if got := portMethods("windows", Capabilities{}); !reflect.DeepEqual(got, []string{"iphlpapi", "dial"}) {
+		t.Errorf("windows: portMethods = %v", got)
+	}
+}
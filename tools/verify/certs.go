@@ -0,0 +1,55 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"embed"
+	"errors"
+	"fmt"
+	"path"
+)
+
+// Bundled root CAs covering the issuers the map is typically served with
+// (Let's Encrypt via Caddy, Cloudflare edge certificates). They are added to
+// the system pool so hosts with a missing or outdated trust store (old
+// CentOS, minimal containers) can still reach the API.
+//
+//go:embed certs/*.pem
+var bundledCerts embed.FS
+
+// apiTLSConfig returns the TLS config for API requests: system roots plus
+// the bundled fallback roots
+func apiTLSConfig() *tls.Config {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	entries, _ := bundledCerts.ReadDir("certs")
+	for _, entry := range entries {
+		pem, err := bundledCerts.ReadFile(path.Join("certs", entry.Name()))
+		if err == nil {
+			pool.AppendCertsFromPEM(pem)
+		}
+	}
+
+	return &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
+}
+
+// explainTLSError adds a hint to certificate errors, which otherwise show
+// up as inscrutable x509 messages
+func explainTLSError(err error) error {
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalid x509.CertificateInvalidError
+	switch {
+	case errors.As(err, &unknownAuthority):
+		return fmt.Errorf("%w (the API certificate is not trusted by this system; "+
+			"update the ca-certificates package or check for a TLS-intercepting proxy)", err)
+	case errors.As(err, &invalid):
+		return fmt.Errorf("%w (check that the system clock is correct)", err)
+	}
+	return err
+}
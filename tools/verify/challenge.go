@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// A challenge file or pipe holds a single token; anything longer is not one
+const maxChallengeInput = 4 << 10
+
+// readChallenge reads a challenge from a password manager pipe or secret
+// file: the first non-empty line, surrounding whitespace removed. The format
+// is validated by the caller like a challenge given as an argument.
+func readChallenge(r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxChallengeInput+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxChallengeInput {
+		return "", fmt.Errorf("more than %d bytes, not a challenge", maxChallengeInput)
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			return line, nil
+		}
+	}
+	return "", errors.New("no challenge found")
+}
+
+// resolveChallenge returns the challenge from the argument, "-" for stdin,
+// or --challenge-file (which may also be "-"). fromStdin tells the caller
+// stdin is used up, so nothing can prompt on it later.
+func resolveChallenge(arg, file string, stdin io.Reader) (challenge string, fromStdin bool, err error) {
+	switch {
+	case arg != "" && file != "":
+		return "", false, errors.New("give the challenge as an argument or with --challenge-file, not both")
+	case arg == "-" || file == "-":
+		challenge, err = readChallenge(stdin)
+		if err != nil {
+			return "", true, fmt.Errorf("reading the challenge from stdin: %w", err)
+		}
+		return challenge, true, nil
+	case file != "":
+		f, err := os.Open(file)
+		if err != nil {
+			return "", false, err
+		}
+		defer f.Close()
+		challenge, err = readChallenge(f)
+		if err != nil {
+			return "", false, fmt.Errorf("%s: %w", file, err)
+		}
+		return challenge, false, nil
+	}
+	return arg, false, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadChallenge(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"abc123xyz456def789ghi0\n", "abc123xyz456def789ghi0", false},
+		{"\n  abc123xyz456def789ghi0  \r\nsecond line\n", "abc123xyz456def789ghi0", false},
+		{"abc123xyz456def789ghi0", "abc123xyz456def789ghi0", false},
+		{"", "", true},
+		{" \n\t\n", "", true},
+		{strings.Repeat("a", maxChallengeInput+1), "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := readChallenge(strings.NewReader(tt.in))
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("readChallenge(%.20q) = %q, %v; want %q, wantErr %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestResolveChallenge(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "challenge")
+	if err := os.WriteFile(file, []byte("fromfile0123456789abcd\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	stdin := "fromstdin0123456789abc\n"
+
+	tests := []struct {
+		name          string
+		arg, file     string
+		want          string
+		wantFromStdin bool
+		wantErr       bool
+	}{
+		{"argument", "fromarg0123456789abcde", "", "fromarg0123456789abcde", false, false},
+		{"dash argument", "-", "", "fromstdin0123456789abc", true, false},
+		{"file", "", file, "fromfile0123456789abcd", false, false},
+		{"dash file", "", "-", "fromstdin0123456789abc", true, false},
+		{"both", "fromarg0123456789abcde", file, "", false, true},
+		{"missing file", "", filepath.Join(t.TempDir(), "missing"), "", false, true},
+	}
+
+	for _, tt := range tests {
+		got, fromStdin, err := resolveChallenge(tt.arg, tt.file, strings.NewReader(stdin))
+		if (err != nil) != tt.wantErr || got != tt.want || (err == nil && fromStdin != tt.wantFromStdin) {
+			t.Errorf("%s: got %q, %v, %v; want %q, %v, wantErr %v", tt.name, got, fromStdin, err, tt.want, tt.wantFromStdin, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Limits for operator check scripts. The timeout is set per run with
+// --check-timeout; on Unix it also becomes the CPU time limit.
+const (
+	maxCheckScripts      = 16
+	checkScriptMaxOutput = 64 << 10
+	checkScriptMemory    = 256 << 20 // address space, Unix only
+)
+
+// Script names become keys of customChecks, which the API limits to these
+var checkScriptName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// CheckScript is an allow-listed operator script and the key its output is
+// reported under
+type CheckScript struct {
+	Name string
+	Path string
+}
+
+// checkScriptsDir is the allow-list: only scripts inside this directory
+// are run, e.g. ~/.config/dingo-verify/checks.d
+func checkScriptsDir() string {
+	dir := configDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "checks.d")
+}
+
+// resolveCheckScripts checks every --check-script argument against the
+// allow-listed directory. A bare name refers to a script inside it.
+func resolveCheckScripts(args []string, dir string) ([]CheckScript, error) {
+	if len(args) > maxCheckScripts {
+		return nil, fmt.Errorf("at most %d check scripts can run, got %d", maxCheckScripts, len(args))
+	}
+	seen := make(map[string]bool)
+	var scripts []CheckScript
+	for _, arg := range args {
+		script, err := resolveCheckScript(arg, dir)
+		if err != nil {
+			return nil, fmt.Errorf("check script %q: %w", arg, err)
+		}
+		if seen[script.Name] {
+			return nil, fmt.Errorf("check script %q: another script is already named %q", arg, script.Name)
+		}
+		seen[script.Name] = true
+		scripts = append(scripts, script)
+	}
+	return scripts, nil
+}
+
+func resolveCheckScript(arg, dir string) (CheckScript, error) {
+	if dir == "" {
+		return CheckScript{}, errors.New("no config directory to allow scripts from")
+	}
+	path := arg
+	if !strings.ContainsAny(arg, `/\`) {
+		path = filepath.Join(dir, arg)
+	}
+
+	// Compare resolved paths so a symlink can't lead out of the directory
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return CheckScript{}, fmt.Errorf("allow-listed directory %s: %w", dir, err)
+	}
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return CheckScript{}, err
+	}
+	realPath, err = filepath.Abs(realPath)
+	if err != nil {
+		return CheckScript{}, err
+	}
+	rel, err := filepath.Rel(realDir, realPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return CheckScript{}, fmt.Errorf("not inside the allow-listed directory %s", dir)
+	}
+
+	info, err := os.Stat(realPath)
+	if err != nil {
+		return CheckScript{}, err
+	}
+	if !info.Mode().IsRegular() {
+		return CheckScript{}, errors.New("not a regular file")
+	}
+	if err := checkScriptPermissions(info); err != nil {
+		return CheckScript{}, err
+	}
+
+	name := strings.TrimSuffix(filepath.Base(realPath), filepath.Ext(realPath))
+	if !checkScriptName.MatchString(name) {
+		return CheckScript{}, fmt.Errorf("name %q must be 1-64 letters, digits, '-' or '_'", name)
+	}
+	return CheckScript{Name: name, Path: realPath}, nil
+}
+
+// limitedBuffer keeps the first max bytes written to it and discards the
+// rest, so a chatty script can't exhaust memory. The buffer is not embedded:
+// its ReadFrom would let io.Copy bypass the limit.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.buf.Len(); len(p) > room {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// checkScriptEnv is the environment scripts run with: nothing from the
+// operator's shell beyond what is needed to find programs
+func checkScriptEnv() []string {
+	env := []string{"LC_ALL=C", "VERIFY_CHAIN=" + ChainName, "VERIFY_DATADIR=" + dataDir}
+	for _, name := range []string{"PATH", "HOME", "SystemRoot"} {
+		if v, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+v)
+		}
+	}
+	return env
+}
+
+// runCheckScript runs one script and returns its output, which must be a
+// single JSON object
+func runCheckScript(script CheckScript, timeout time.Duration) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := limitedCommand(ctx, script.Path, timeout)
+	cmd.Dir = filepath.Dir(script.Path)
+	cmd.Env = checkScriptEnv()
+	stdout := &limitedBuffer{max: checkScriptMaxOutput}
+	stderr := &limitedBuffer{max: 4096}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Don't wait for descendants that keep the output pipes open
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		if msg := lastLine(stderr.buf.String()); msg != "" {
+			return nil, fmt.Errorf("%v: %s", err, msg)
+		}
+		return nil, err
+	}
+	if stdout.truncated {
+		return nil, fmt.Errorf("output exceeds %d KiB", checkScriptMaxOutput>>10)
+	}
+	return parseCheckOutput(stdout.buf.Bytes())
+}
+
+// parseCheckOutput accepts a single JSON object and returns it compacted
+func parseCheckOutput(out []byte) (json.RawMessage, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(out, &obj); err != nil || obj == nil {
+		return nil, errors.New("output is not a JSON object")
+	}
+	var b bytes.Buffer
+	if err := json.Compact(&b, out); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// runCheckScripts runs the scripts one after another and collects their
+// output by name. A failed script is reported with its error so the admins
+// see that the check was attempted.
+func runCheckScripts(scripts []CheckScript, timeout time.Duration) map[string]json.RawMessage {
+	results := make(map[string]json.RawMessage, len(scripts))
+	for _, script := range scripts {
+		out, err := runCheckScript(script, timeout)
+		if err != nil {
+			fmt.Printf("  ❌ Custom check %s failed: %v\n", script.Name, err)
+			out, _ = json.Marshal(map[string]string{"error": err.Error()})
+		} else {
+			fmt.Printf("  ✅ Custom check %s\n", script.Name)
+		}
+		results[script.Name] = out
+	}
+	return results
+}
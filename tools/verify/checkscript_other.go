@@ -0,0 +1,43 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// limitedCommand runs the script through sh, which applies CPU time and
+// memory limits before exec'ing it. Limits the shell can't set are skipped.
+// The script gets its own process group so a timeout kills its children too.
+func limitedCommand(ctx context.Context, path string, timeout time.Duration) *exec.Cmd {
+	cpu := int(timeout.Seconds())
+	if cpu < 1 {
+		cpu = 1
+	}
+	limits := fmt.Sprintf(`ulimit -t %d 2>/dev/null; ulimit -v %d 2>/dev/null; exec "$0"`, cpu, checkScriptMemory>>10)
+
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", limits, path)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	return cmd
+}
+
+// checkScriptPermissions refuses scripts that other users could change
+// into something else between being allow-listed and being run
+func checkScriptPermissions(info os.FileInfo) error {
+	if info.Mode().Perm()&0o022 != 0 {
+		return errors.New("writable by other users")
+	}
+	if info.Mode().Perm()&0o111 == 0 {
+		return errors.New("not executable")
+	}
+	return nil
+}
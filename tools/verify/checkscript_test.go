@@ -0,0 +1,156 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeCheckScript(t *testing.T, dir, name, body string, mode os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), mode); err != nil {
+		t.Fatal(err)
+	}
+	// WriteFile is subject to the umask
+	if err := os.Chmod(path, mode); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolveCheckScripts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission and symlink checks are Unix-only")
+	}
+	dir := t.TempDir()
+	outside := t.TempDir()
+	writeCheckScript(t, dir, "image.sh", "echo {}", 0o700)
+	writeCheckScript(t, dir, "shared.sh", "echo {}", 0o777)
+	writeCheckScript(t, dir, "data.sh", "echo {}", 0o600)
+	writeCheckScript(t, dir, "bad name.sh", "echo {}", 0o700)
+	escape := writeCheckScript(t, outside, "escape.sh", "echo {}", 0o700)
+	if err := os.Symlink(escape, filepath.Join(dir, "link.sh")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "image"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	writeCheckScript(t, filepath.Join(dir, "image"), "image.py", "echo {}", 0o700)
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantName []string
+		wantErr  string
+	}{
+		{"bare name", []string{"image.sh"}, []string{"image"}, ""},
+		{"full path", []string{filepath.Join(dir, "image.sh")}, []string{"image"}, ""},
+		{"outside the directory", []string{escape}, nil, "not inside"},
+		{"symlink out of the directory", []string{"link.sh"}, nil, "not inside"},
+		{"dot-dot path", []string{filepath.Join(dir, "..", filepath.Base(outside), "escape.sh")}, nil, "not inside"},
+		{"world-writable", []string{"shared.sh"}, nil, "writable"},
+		{"not executable", []string{"data.sh"}, nil, "not executable"},
+		{"missing", []string{"gone.sh"}, nil, "no such file"},
+		{"directory", []string{filepath.Join(dir, "image")}, nil, "not a regular file"},
+		{"invalid name", []string{"bad name.sh"}, nil, "must be"},
+		{"duplicate name", []string{"image.sh", filepath.Join(dir, "image", "image.py")}, nil, "already named"},
+	}
+
+	for _, tt := range tests {
+		scripts, err := resolveCheckScripts(tt.args, dir)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: err = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if len(scripts) != len(tt.wantName) || scripts[0].Name != tt.wantName[0] {
+			t.Errorf("%s: scripts = %+v, want names %v", tt.name, scripts, tt.wantName)
+		}
+	}
+
+	if _, err := resolveCheckScripts(make([]string, maxCheckScripts+1), dir); err == nil {
+		t.Errorf("%d scripts accepted, want error", maxCheckScripts+1)
+	}
+}
+
+func TestParseCheckOutput(t *testing.T) {
+	tests := []struct {
+		out     string
+		want    string
+		wantErr bool
+	}{
+		{"{\n  \"image\": \"golden-2026.09\",\n  \"ok\": true\n}\n", `{"image":"golden-2026.09","ok":true}`, false},
+		{`{}`, `{}`, false},
+		{`["a"]`, "", true},
+		{`null`, "", true},
+		{`"ok"`, "", true},
+		{`{"ok":true} {"ok":false}`, "", true},
+		{"approved image", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseCheckOutput([]byte(tt.out))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseCheckOutput(%q) err = %v, wantErr %v", tt.out, err, tt.wantErr)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("parseCheckOutput(%q) = %s, want %s", tt.out, got, tt.want)
+		}
+	}
+}
+
+func TestRunCheckScript(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh scripts")
+	}
+	dir := t.TempDir()
+	t.Setenv("VERIFY_TEST_SECRET", "leaked")
+
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr string
+	}{
+		{"json object", `echo '{"image": "golden"}'`, `{"image":"golden"}`, ""},
+		{"environment is stripped", `printf '{"secret":"%s"}' "$VERIFY_TEST_SECRET"`, `{"secret":""}`, ""},
+		{"plain text", `echo approved`, "", "not a JSON object"},
+		{"exit status", `echo 'image mismatch' >&2; exit 3`, "", "image mismatch"},
+		{"timeout", `sleep 5; echo '{}'`, "", "timed out"},
+		{"timeout with child holding the pipe", `sleep 5 & wait`, "", "timed out"},
+		{"output cap", `head -c 70000 /dev/zero | tr '\0' 'x'`, "", "exceeds"},
+	}
+
+	for i, tt := range tests {
+		path := writeCheckScript(t, dir, "check"+string(rune('a'+i))+".sh", tt.body, 0o700)
+		start := time.Now()
+		got, err := runCheckScript(CheckScript{Name: tt.name, Path: path}, 500*time.Millisecond)
+		if elapsed := time.Since(start); elapsed > 3*time.Second {
+			t.Errorf("%s: took %s", tt.name, elapsed)
+		}
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: err = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s: output %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// limitedCommand runs the script directly, or PowerShell scripts through
+// powershell. Windows has no ulimit; only the timeout and the output cap
+// apply.
+func limitedCommand(ctx context.Context, path string, timeout time.Duration) *exec.Cmd {
+	if strings.EqualFold(filepath.Ext(path), ".ps1") {
+		return exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-ExecutionPolicy", "Bypass", "-File", path)
+	}
+	return exec.CommandContext(ctx, path)
+}
+
+// Access to the allow-listed directory is governed by its ACL on Windows
+func checkScriptPermissions(info os.FileInfo) error {
+	return nil
+}
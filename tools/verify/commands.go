@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// statusDescriptions explain the verification statuses the API reports
+var statusDescriptions = map[string]string{
+	"pending":          "Waiting for this tool to submit the checks. Run it on the node with the challenge.",
+	"pending_approval": "Checks submitted; the map admins are reviewing them.",
+	"verified":         "The node is verified.",
+	"failed":           "The checks failed. Create a new challenge on the website to try again.",
+	"expired":          "The challenge expired. Create a new one on the website.",
+}
+
+func describeStatus(status string) string {
+	if d, ok := statusDescriptions[status]; ok {
+		return d
+	}
+	return "Unknown status; a newer map may use statuses this tool doesn't know yet."
+}
+
+// recheckExitCode is the exit code of a recheck, matching the exit codes
+// of a verification that stopped at the same check
+func recheckExitCode(processFound, portListening bool) int {
+	switch {
+	case !processFound:
+		return exitDaemonNotFound
+	case !portListening:
+		return exitPortNotListening
+	}
+	return 0
+}
+
+// runVersion prints the build and the configuration it was built with
+func runVersion(args []string) {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Print as JSON")
+	fs.Usage = commandUsage("version")
+	fs.Parse(args)
+
+	info := struct {
+		Version string `json:"version"`
+		Chain   string `json:"chain"`
+		APIURL  string `json:"apiUrl"`
+		Go      string `json:"go"`
+		OS      string `json:"os"`
+		Arch    string `json:"arch"`
+	}{Version, ChainName, ApiUrl, runtime.Version(), runtime.GOOS, runtime.GOARCH}
+
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(info)
+		return
+	}
+	fmt.Printf("%s verify %s (%s, %s/%s)\n", info.Chain, info.Version, info.Go, info.OS, info.Arch)
+	fmt.Printf("API: %s\n", info.APIURL)
+}
+
+// runStatus shows where a verification stands, using the conversation
+// endpoint the challenge already authenticates
+func runStatus(args []string) {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Print the status as JSON")
+	fs.Usage = commandUsage("status")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 || !isValidChallenge(fs.Arg(0)) {
+		fs.Usage()
+		os.Exit(1)
+	}
+	challenge := fs.Arg(0)
+	addRedaction(challenge)
+
+	resp, err := postConversation(ConversationRequest{Challenge: challenge})
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			if code := apiErr.challengeExitCode(); code != 0 {
+				fatal(code, "❌ %s", apiErr.Message)
+			}
+			fatal(1, "❌ Failed to fetch the status: %v", err)
+		}
+		fatal(exitAPIUnreachable, "❌ Failed to fetch the status: %v", err)
+	}
+	questions := openQuestions(resp.Messages)
+
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(struct {
+			Status        string `json:"status"`
+			OpenQuestions int    `json:"openQuestions"`
+		}{resp.Status, len(questions)})
+		return
+	}
+	fmt.Printf("Status: %s\n", resp.Status)
+	fmt.Printf("  %s\n", describeStatus(resp.Status))
+	if len(questions) > 0 {
+		fmt.Printf("  The admins asked %d question(s); answer with: %s questions %s\n", len(questions), os.Args[0], challenge)
+	}
+}
+
+// runRecheck runs the local process and port checks without contacting the
+// map, e.g. after fixing what made a verification fail
+func runRecheck(args []string) {
+	fs := flag.NewFlagSet("recheck", flag.ExitOnError)
+	port := defaultPort
+	fs.Var(&port, "port", "Node P2P port to check")
+	fs.StringVar(&dataDir, "datadir", defaultDataDir(), "Daemon data directory")
+	fs.Var(&checkTimeout, "check-timeout", "Time limit for the process check and for the port check")
+	fs.Usage = commandUsage("recheck")
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	p := int(port)
+
+	fmt.Println("Checking local node process and port...")
+	ctx, cancel := checkContext()
+	processFound, processMethod, daemonName, _ := checkProcess(ctx, p)
+	warnCheckTimeout(ctx, "process")
+	cancel()
+	if processFound {
+		fmt.Printf("  ✅ Found daemon: %s (method: %s)\n", daemonName, processMethod)
+	} else {
+		fmt.Printf("  ❌ No node daemon found. Expected: %s\n", DaemonNames)
+	}
+	ctx, cancel = checkContext()
+	portListening, portMethod := checkNodePort(ctx, daemonName, p)
+	warnCheckTimeout(ctx, "port")
+	cancel()
+	if portListening {
+		fmt.Printf("  ✅ Port %d is listening (method: %s)\n", p, portMethod)
+	} else {
+		fmt.Printf("  ❌ Port %d is not listening\n", p)
+	}
+
+	if code := recheckExitCode(processFound, portListening); code != 0 {
+		os.Exit(code)
+	}
+	fmt.Println()
+	fmt.Println("✅ Both checks pass. Submit them with a challenge from the website.")
+}
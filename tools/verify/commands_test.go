@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestRecheckExitCode(t *testing.T) {
+	tests := []struct {
+		processFound, portListening bool
+		want                        int
+	}{
+		{true, true, 0},
+		{false, true, exitDaemonNotFound},
+		{false, false, exitDaemonNotFound},
+		{true, false, exitPortNotListening},
+	}
+
+	for _, tt := range tests {
+		if got := recheckExitCode(tt.processFound, tt.portListening); got != tt.want {
+			t.Errorf("recheckExitCode(%v, %v) = %d, want %d", tt.processFound, tt.portListening, got, tt.want)
+		}
+	}
+}
+
+func TestDescribeStatus(t *testing.T) {
+	for status := range statusDescriptions {
+		if describeStatus(status) == describeStatus("some_new_status") {
+			t.Errorf("status %q described as unknown", status)
+		}
+	}
+}
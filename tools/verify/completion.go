@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Flags that apply to every command, handled before subcommands
+var globalFlags = []flagHelp{flagConfig, flagLang, flagNoColor, flagASCII, flagVerbose, flagDebug}
+
+// Fixed arguments completed besides the flags
+var completionArgs = map[string][]string{
+	"completion": {"bash", "zsh", "fish"},
+}
+
+// completionCommands are the commands offered for completion: every
+// visible command plus help
+func completionCommands() []commandHelp {
+	list := []commandHelp{}
+	for _, c := range commands {
+		if !c.Hidden {
+			list = append(list, c)
+		}
+	}
+	return append(list, commandHelp{Name: "help", Summary: "Show the help of a command"})
+}
+
+// completionFlags are a command's flags plus the global ones
+func completionFlags(c commandHelp) []flagHelp {
+	flags := append([]flagHelp(nil), c.Flags...)
+	for _, g := range globalFlags {
+		if !hasFlag(flags, g.Name) {
+			flags = append(flags, g)
+		}
+	}
+	return flags
+}
+
+func hasFlag(flags []flagHelp, name string) bool {
+	for _, f := range flags {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// flagSpelling is how a flag is typed: one dash for single letters
+func flagSpelling(f flagHelp) string {
+	if len(f.Name) == 1 {
+		return "-" + f.Name
+	}
+	return "--" + f.Name
+}
+
+// takesFile reports whether a flag's value is a path, for file completion
+func takesFile(f flagHelp) bool {
+	switch f.Arg {
+	case "path", "file", "f":
+		return true
+	}
+	return false
+}
+
+var nonIdentifier = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+func writeBashCompletion(w io.Writer, bin string) {
+	fn := "_" + nonIdentifier.ReplaceAllString(bin, "_") + "_complete"
+	cmds := completionCommands()
+	verify, _ := lookupCommand("verify")
+
+	var names, fileFlags []string
+	for _, c := range cmds {
+		names = append(names, c.Name)
+		for _, f := range completionFlags(c) {
+			if takesFile(f) && !slices.Contains(fileFlags, flagSpelling(f)) {
+				fileFlags = append(fileFlags, flagSpelling(f))
+			}
+		}
+	}
+	spell := func(c commandHelp) string {
+		words := append([]string(nil), completionArgs[c.Name]...)
+		for _, f := range completionFlags(c) {
+			words = append(words, flagSpelling(f))
+		}
+		return strings.Join(words, " ")
+	}
+
+	fmt.Fprintf(w, "# bash completion for %s\n", bin)
+	fmt.Fprintf(w, "# Load with: source <(%s completion bash)\n", bin)
+	fmt.Fprintf(w, "%s() {\n", fn)
+	fmt.Fprintln(w, `    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}" cmd="" i`)
+	fmt.Fprintln(w, `    for ((i = 1; i < COMP_CWORD; i++)); do`)
+	fmt.Fprintf(w, "        case \"${COMP_WORDS[i]}\" in\n            %s) cmd=\"${COMP_WORDS[i]}\"; break ;;\n        esac\n", strings.Join(names, "|"))
+	fmt.Fprintln(w, `    done`)
+	fmt.Fprintln(w, `    case "$prev" in`)
+	fmt.Fprintf(w, "        %s)\n            COMPREPLY=($(compgen -f -- \"$cur\")); return ;;\n", strings.Join(fileFlags, "|"))
+	fmt.Fprintln(w, `    esac`)
+	fmt.Fprintln(w, `    local words`)
+	fmt.Fprintln(w, `    case "$cmd" in`)
+	fmt.Fprintf(w, "        \"\")\n            if [[ $cur == -* ]]; then words=%q; else words=%q; fi ;;\n", spell(verify), strings.Join(names, " "))
+	fmt.Fprintf(w, "        help) words=%q ;;\n", strings.Join(names[:len(names)-1], " "))
+	for _, c := range cmds {
+		if c.Name == "help" {
+			continue
+		}
+		fmt.Fprintf(w, "        %s) words=%q ;;\n", c.Name, spell(c))
+	}
+	fmt.Fprintln(w, `    esac`)
+	fmt.Fprintln(w, `    COMPREPLY=($(compgen -W "$words" -- "$cur"))`)
+	fmt.Fprintln(w, `}`)
+	fmt.Fprintf(w, "complete -F %s %s\n", fn, bin)
+}
+
+// zshQuote escapes s for a single-quoted zsh word, and for _arguments and
+// _describe, which give [ ] and : a meaning
+func zshQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`, `:`, `\:`).Replace(s)
+	return strings.ReplaceAll(s, `'`, `'\''`)
+}
+
+func zshFlagSpecs(c commandHelp) []string {
+	var specs []string
+	for _, f := range completionFlags(c) {
+		spec := fmt.Sprintf("'%s[%s]", flagSpelling(f), zshQuote(f.Usage))
+		switch {
+		case takesFile(f):
+			spec += ":" + f.Arg + ":_files"
+		case f.Arg != "":
+			spec += ":" + zshQuote(f.Arg) + ": "
+		}
+		specs = append(specs, spec+"'")
+	}
+	return specs
+}
+
+func writeZshCompletion(w io.Writer, bin string) {
+	fn := "_" + nonIdentifier.ReplaceAllString(bin, "_")
+	cmds := completionCommands()
+	verify, _ := lookupCommand("verify")
+
+	fmt.Fprintf(w, "#compdef %s\n", bin)
+	fmt.Fprintf(w, "# Load with: source <(%s completion zsh)\n", bin)
+	fmt.Fprintf(w, "%s() {\n", fn)
+	fmt.Fprintln(w, `    local curcontext="$curcontext" state line`)
+	fmt.Fprintln(w, `    local -a commands`)
+	fmt.Fprintln(w, `    commands=(`)
+	for _, c := range cmds {
+		fmt.Fprintf(w, "        '%s:%s'\n", c.Name, zshQuote(c.Summary))
+	}
+	fmt.Fprintln(w, `    )`)
+	fmt.Fprintln(w, `    _arguments -C \`)
+	for _, spec := range zshFlagSpecs(verify) {
+		fmt.Fprintf(w, "        %s \\\n", spec)
+	}
+	fmt.Fprintln(w, `        '1: :->command' \`)
+	fmt.Fprintln(w, `        '*:: :->args'`)
+	fmt.Fprintln(w, `    case $state in`)
+	fmt.Fprintln(w, `        command) _describe -t commands command commands ;;`)
+	fmt.Fprintln(w, `        args)`)
+	fmt.Fprintln(w, `            case $line[1] in`)
+	fmt.Fprintln(w, `                help) _describe -t commands command commands ;;`)
+	for _, c := range cmds {
+		if c.Name == "help" {
+			continue
+		}
+		specs := zshFlagSpecs(c)
+		if args := completionArgs[c.Name]; len(args) > 0 {
+			specs = append(specs, fmt.Sprintf("'1: :(%s)'", strings.Join(args, " ")))
+		}
+		fmt.Fprintf(w, "                %s) _arguments %s ;;\n", c.Name, strings.Join(specs, " "))
+	}
+	fmt.Fprintln(w, `            esac ;;`)
+	fmt.Fprintln(w, `    esac`)
+	fmt.Fprintln(w, `}`)
+	fmt.Fprintf(w, "compdef %s %s\n", fn, bin)
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func writeFishCompletion(w io.Writer, bin string) {
+	cmds := completionCommands()
+	var names []string
+	for _, c := range cmds {
+		names = append(names, c.Name)
+	}
+
+	fmt.Fprintf(w, "# fish completion for %s\n", bin)
+	fmt.Fprintf(w, "# Load with: %s completion fish | source\n", bin)
+	fmt.Fprintf(w, "complete -c %s -f\n", bin)
+	for _, c := range cmds {
+		fmt.Fprintf(w, "complete -c %s -n __fish_use_subcommand -a %s -d %s\n", bin, c.Name, fishQuote(c.Summary))
+	}
+	fmt.Fprintf(w, "complete -c %s -n '__fish_seen_subcommand_from help' -a %s\n", bin, fishQuote(strings.Join(names[:len(names)-1], " ")))
+
+	for _, c := range cmds {
+		if c.Name == "help" {
+			continue
+		}
+		condition := fmt.Sprintf("'__fish_seen_subcommand_from %s'", c.Name)
+		if c.Name == "verify" {
+			// The verification also runs without naming it
+			condition = "'__fish_use_subcommand; or __fish_seen_subcommand_from verify'"
+		}
+		if args := completionArgs[c.Name]; len(args) > 0 {
+			fmt.Fprintf(w, "complete -c %s -n %s -a %s\n", bin, condition, fishQuote(strings.Join(args, " ")))
+		}
+		for _, f := range completionFlags(c) {
+			line := fmt.Sprintf("complete -c %s -n %s", bin, condition)
+			if len(f.Name) == 1 {
+				line += " -s " + f.Name
+			} else {
+				line += " -l " + f.Name
+			}
+			switch {
+			case takesFile(f):
+				line += " -r -F"
+			case f.Arg != "":
+				line += " -x"
+			}
+			fmt.Fprintf(w, "%s -d %s\n", line, fishQuote(f.Usage))
+		}
+	}
+}
+
+// runCompletion prints a shell completion script
+func runCompletion(args []string) {
+	if len(args) != 1 {
+		commandUsage("completion")()
+		os.Exit(1)
+	}
+	bin := filepath.Base(os.Args[0])
+	switch args[0] {
+	case "bash":
+		writeBashCompletion(os.Stdout, bin)
+	case "zsh":
+		writeZshCompletion(os.Stdout, bin)
+	case "fish":
+		writeFishCompletion(os.Stdout, bin)
+	default:
+		fmt.Printf("Unknown shell %q: use bash, zsh or fish\n\n", args[0])
+		commandUsage("completion")()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCompletionScripts(t *testing.T) {
+	writers := map[string]func(io.Writer, string){
+		"bash": writeBashCompletion,
+		"zsh":  writeZshCompletion,
+		"fish": writeFishCompletion,
+	}
+	for shell, write := range writers {
+		var buf bytes.Buffer
+		write(&buf, "verify")
+		script := buf.String()
+
+		for _, c := range commands {
+			if got := strings.Contains(script, c.Name); got == c.Hidden {
+				t.Errorf("%s: command %s completed = %v, hidden = %v", shell, c.Name, got, c.Hidden)
+			}
+		}
+		for _, flag := range []string{"challenge-file", "api-key", "verbose"} {
+			if !strings.Contains(script, flag) {
+				t.Errorf("%s: flag %s missing", shell, flag)
+			}
+		}
+
+		// Check the syntax where the shell is installed
+		if path, err := exec.LookPath(shell); err == nil {
+			cmd := exec.Command(path, "-n")
+			cmd.Stdin = strings.NewReader(script)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("%s -n: %v\n%s", shell, err, out)
+			}
+		}
+	}
+}
+
+func TestCompletionQuoting(t *testing.T) {
+	tests := []struct {
+		quote func(string) string
+		in    string
+		want  string
+	}{
+		{zshQuote, "Daemon RPC address (default: 127.0.0.1:<rpcport>)", `Daemon RPC address (default\: 127.0.0.1\:<rpcport>)`},
+		{zshQuote, "the node's [port]", `the node'\''s \[port\]`},
+		{fishQuote, "the node's port", `'the node\'s port'`},
+		{fishQuote, `a\b`, `'a\\b'`},
+	}
+	for _, tt := range tests {
+		if got := tt.quote(tt.in); got != tt.want {
+			t.Errorf("quote(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/atlasp2p/verify/internal/flags"
+)
+
+// The tool's own config directory, e.g. ~/.config/dingo-verify on Linux.
+// It can't depend on ChainName, which the config file may supply.
+const configDirName = "dingo-verify"
+
+func configDir() string {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(base, configDirName)
+}
+
+// configKeys maps config file keys to the build-time values they override
+var configKeys = map[string]*string{
+	"apiUrl":      &ApiUrl,
+	"daemonNames": &DaemonNames,
+	"defaultPort": &DefaultPort,
+	"chainName":   &ChainName,
+}
+
+// configOrder lists the overridable values in the order they are reported
+var configOrder = []string{"apiUrl", "daemonNames", "defaultPort", "chainName"}
+
+// configSources records where each overridden value came from, for the
+// startup notice; values missing here are the build-time ones
+var configSources = map[string]string{}
+
+// envOverrides are the environment variables that take precedence over both
+// the config file and the build, for containers configured per environment
+var envOverrides = []struct {
+	Env string
+	Key string
+}{
+	{"DINGO_VERIFY_API_URL", "apiUrl"},
+	{"DINGO_VERIFY_PORT", "defaultPort"},
+	{"DINGO_VERIFY_DAEMONS", "daemonNames"},
+}
+
+// splitConfigFlag removes --config <path> (or --config=<path>) from args.
+// It is handled before anything else because the values it supplies are
+// needed to decide whether the binary can run at all, and it works in front
+// of subcommands as well as with them.
+func splitConfigFlag(args []string) (path string, rest []string, err error) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "config" {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return "", nil, errors.New("--config needs a file path")
+			}
+			i++
+			value = args[i]
+		}
+		path = value
+	}
+	return path, rest, nil
+}
+
+// loadConfig applies the config file at path over the build-time values.
+// Without --config the default file is optional.
+// splitDaemonNameFlag removes --daemon-name from args. It may be repeated,
+// and takes the same comma-separated names and patterns as daemonNames.
+// It applies to every command and outranks the config file and
+// $DINGO_VERIFY_DAEMONS.
+func splitDaemonNameFlag(args []string) (names []string, rest []string, err error) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "daemon-name" {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return nil, nil, errors.New("--daemon-name needs a process name or pattern")
+			}
+			i++
+			value = args[i]
+		}
+		names = append(names, value)
+	}
+	return names, rest, nil
+}
+
+// applyDaemonNameFlag sets daemonNames from --daemon-name
+func applyDaemonNameFlag(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	value := strings.Join(names, ",")
+	if err := validateConfigValue("daemonNames", value); err != nil {
+		return fmt.Errorf("--daemon-name: %w", err)
+	}
+	setConfigValue("daemonNames", value, "--daemon-name")
+	return nil
+}
+
+func loadConfig(path string) error {
+	explicit := path != ""
+	if !explicit {
+		if dir := configDir(); dir != "" {
+			path = filepath.Join(dir, "config.yaml")
+		}
+	}
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	values, err := parseConfig(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for key, value := range values {
+		if err := validateConfigValue(key, value); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	for key, value := range values {
+		setConfigValue(key, value, path)
+	}
+	return nil
+}
+
+// applyEnvOverrides applies the DINGO_VERIFY_* variables that are set.
+// An empty variable counts as unset.
+func applyEnvOverrides() error {
+	for _, o := range envOverrides {
+		value := strings.TrimSpace(os.Getenv(o.Env))
+		if value == "" {
+			continue
+		}
+		if err := validateConfigValue(o.Key, value); err != nil {
+			return fmt.Errorf("$%s: %w", o.Env, err)
+		}
+		setConfigValue(o.Key, value, "$"+o.Env)
+	}
+	return nil
+}
+
+func setConfigValue(key, value, source string) {
+	if key == "apiUrl" {
+		// API paths are appended with a leading slash
+		value = strings.TrimRight(value, "/")
+	}
+	*configKeys[key] = value
+	configSources[key] = source
+}
+
+// validateConfigValue rejects values build.sh would never have injected
+func validateConfigValue(key, value string) error {
+	switch key {
+	case "apiUrl":
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("apiUrl %q is not an http(s) URL", value)
+		}
+	case "defaultPort":
+		var port flags.Port
+		if err := port.Set(value); err != nil {
+			return fmt.Errorf("defaultPort: %w", err)
+		}
+	case "daemonNames":
+		for _, name := range strings.Split(value, ",") {
+			if !daemonPattern(strings.TrimSpace(name)).validate() {
+				return fmt.Errorf("daemonNames %q must be process names, globs or re:expressions separated by commas", value)
+			}
+		}
+	}
+	return nil
+}
+
+// parseConfig reads the flat subset of YAML the config file uses:
+// "key: value" lines, comments, quoted values, and daemonNames as either a
+// comma-separated string or a list. Unknown keys are errors so a typo
+// doesn't silently leave the build-time value in place.
+func parseConfig(r io.Reader) (map[string]string, error) {
+	values := make(map[string]string)
+	var listKey string
+	var list []string
+	endList := func() {
+		if listKey != "" {
+			values[listKey] = strings.Join(list, ",")
+			listKey, list = "", nil
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := stripYAMLComment(scanner.Text())
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+
+		if item, ok := strings.CutPrefix(trimmed, "- "); ok && listKey != "" {
+			list = append(list, unquoteYAML(item))
+			continue
+		}
+		endList()
+
+		if line[0] == ' ' || line[0] == '\t' {
+			return nil, fmt.Errorf("line %d: nested values are not supported", lineNo)
+		}
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", lineNo)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if _, known := configKeys[key]; !known {
+			return nil, fmt.Errorf("line %d: unknown key %q (known: apiUrl, daemonNames, defaultPort, chainName)", lineNo, key)
+		}
+		if _, dup := values[key]; dup {
+			return nil, fmt.Errorf("line %d: %s is set twice", lineNo, key)
+		}
+
+		switch {
+		case value == "" && key == "daemonNames":
+			listKey = key
+		case value == "":
+			return nil, fmt.Errorf("line %d: %s has no value", lineNo, key)
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			if key != "daemonNames" {
+				return nil, fmt.Errorf("line %d: %s takes a single value", lineNo, key)
+			}
+			var names []string
+			for _, item := range strings.Split(value[1:len(value)-1], ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					names = append(names, unquoteYAML(item))
+				}
+			}
+			values[key] = strings.Join(names, ",")
+		default:
+			values[key] = unquoteYAML(value)
+		}
+	}
+	endList()
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for key, value := range values {
+		if value == "" {
+			return nil, fmt.Errorf("%s is empty", key)
+		}
+	}
+	return values, nil
+}
+
+// stripYAMLComment drops a trailing comment; '#' only starts one at the
+// start of the line or after whitespace, so URLs with fragments survive
+func stripYAMLComment(line string) string {
+	inQuote := byte(0)
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case inQuote != 0:
+			if c == inQuote {
+				inQuote = 0
+			}
+		case c == '"' || c == '\'':
+			inQuote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func unquoteYAML(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// printConfigSources says where each value came from, so a run against a
+// staging API is never mistaken for a real one
+func printConfigSources() {
+	parts := make([]string, len(configOrder))
+	for i, key := range configOrder {
+		source, ok := configSources[key]
+		if !ok {
+			source = "build"
+		}
+		parts[i] = fmt.Sprintf("%s=%s (%s)", key, *configKeys[key], source)
+	}
+	fmt.Printf("ℹ️  Settings: %s\n", strings.Join(parts, ", "))
+	fmt.Println()
+}
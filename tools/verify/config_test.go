@@ -0,0 +1,216 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "all keys",
+			in: "# staging\n---\napiUrl: https://staging.example/ # trailing comment\n" +
+				"daemonNames: dingocoind,dingod\ndefaultPort: \"33117\"\nchainName: 'Dingocoin'\n",
+			want: map[string]string{
+				"apiUrl":      "https://staging.example/",
+				"daemonNames": "dingocoind,dingod",
+				"defaultPort": "33117",
+				"chainName":   "Dingocoin",
+			},
+		},
+		{
+			name: "flow list",
+			in:   "daemonNames: [dingocoind, \"dingod\"]\n",
+			want: map[string]string{"daemonNames": "dingocoind,dingod"},
+		},
+		{
+			name: "block list",
+			in:   "daemonNames:\n  - dingocoind\n  - dingod\nchainName: Dingocoin\n",
+			want: map[string]string{"daemonNames": "dingocoind,dingod", "chainName": "Dingocoin"},
+		},
+		{
+			name: "hash inside a value",
+			in:   "apiUrl: https://map.example/#top\n",
+			want: map[string]string{"apiUrl": "https://map.example/#top"},
+		},
+		{name: "unknown key", in: "apiURL: https://map.example\n", wantErr: "unknown key"},
+		{name: "set twice", in: "chainName: a\nchainName: b\n", wantErr: "set twice"},
+		{name: "no value", in: "apiUrl:\n", wantErr: "no value"},
+		{name: "empty quoted value", in: "chainName: \"\"\n", wantErr: "empty"},
+		{name: "empty list", in: "daemonNames:\nchainName: a\n", wantErr: "empty"},
+		{name: "nested", in: "  apiUrl: https://map.example\n", wantErr: "nested"},
+		{name: "list for a scalar", in: "defaultPort: [1, 2]\n", wantErr: "single value"},
+		{name: "not key-value", in: "chainName Dingocoin\n", wantErr: "key: value"},
+	}
+
+	for _, tt := range tests {
+		got, err := parseConfig(strings.NewReader(tt.in))
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: err = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateConfigValue(t *testing.T) {
+	tests := []struct {
+		key, value string
+		wantErr    bool
+	}{
+		{"apiUrl", "https://staging.example", false},
+		{"apiUrl", "http://127.0.0.1:3000", false},
+		{"apiUrl", "staging.example", true},
+		{"apiUrl", "ftp://staging.example", true},
+		{"defaultPort", "33117", false},
+		{"defaultPort", "0", true},
+		{"daemonNames", "dingocoind, dingod", false},
+		{"daemonNames", "dingocoind,,dingod", true},
+		{"daemonNames", "/usr/bin/dingocoind", true},
+		{"daemonNames", "dingocoind, dingocoind-*", false},
+		{"daemonNames", `re:dingocoind(-\d+\.\d+)?`, false},
+		{"daemonNames", "re:dingocoind(", true},
+		{"daemonNames", "re:", true},
+		{"daemonNames", "dingocoind-[", true},
+		{"chainName", "Dingocoin", false},
+	}
+
+	for _, tt := range tests {
+		err := validateConfigValue(tt.key, tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateConfigValue(%s, %q) err = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSplitConfigFlag(t *testing.T) {
+	tests := []struct {
+		args     []string
+		wantPath string
+		wantRest []string
+		wantErr  bool
+	}{
+		{[]string{"abc123"}, "", []string{"abc123"}, false},
+		{[]string{"--config", "staging.yaml", "abc123"}, "staging.yaml", []string{"abc123"}, false},
+		{[]string{"peers", "-config=staging.yaml", "--setban"}, "staging.yaml", []string{"peers", "--setban"}, false},
+		{[]string{"--json", "--", "--config", "x"}, "", []string{"--json", "--", "--config", "x"}, false},
+		{[]string{"--configure"}, "", []string{"--configure"}, false},
+		{[]string{"abc123", "--config"}, "", nil, true},
+	}
+
+	for _, tt := range tests {
+		path, rest, err := splitConfigFlag(tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("splitConfigFlag(%q) err = %v, wantErr %v", tt.args, err, tt.wantErr)
+			continue
+		}
+		if path != tt.wantPath || !reflect.DeepEqual(rest, tt.wantRest) {
+			t.Errorf("splitConfigFlag(%q) = %q, %q; want %q, %q", tt.args, path, rest, tt.wantPath, tt.wantRest)
+		}
+	}
+}
+
+func TestSplitDaemonNameFlag(t *testing.T) {
+	tests := []struct {
+		args      []string
+		wantNames []string
+		wantRest  []string
+		wantErr   bool
+	}{
+		{[]string{"abc123"}, nil, []string{"abc123"}, false},
+		{[]string{"--daemon-name", "dingocoind-*", "preflight"}, []string{"dingocoind-*"}, []string{"preflight"}, false},
+		{[]string{"--daemon-name=a", "-daemon-name", "b", "abc123"}, []string{"a", "b"}, []string{"abc123"}, false},
+		{[]string{"--", "--daemon-name", "x"}, nil, []string{"--", "--daemon-name", "x"}, false},
+		{[]string{"abc123", "--daemon-name"}, nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		names, rest, err := splitDaemonNameFlag(tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("splitDaemonNameFlag(%q) err = %v, wantErr %v", tt.args, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(names, tt.wantNames) || !reflect.DeepEqual(rest, tt.wantRest) {
+			t.Errorf("splitDaemonNameFlag(%q) = %q, %q; want %q, %q", tt.args, names, rest, tt.wantNames, tt.wantRest)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	saved := []string{ApiUrl, DaemonNames, DefaultPort, ChainName}
+	t.Cleanup(func() {
+		ApiUrl, DaemonNames, DefaultPort, ChainName = saved[0], saved[1], saved[2], saved[3]
+		configSources = map[string]string{}
+	})
+	ApiUrl, DaemonNames, DefaultPort, ChainName = "https://map.example", "dingocoind", "33117", "Dingocoin"
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("apiUrl: https://staging.example/\nchainName: Staging\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadConfig(path); err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if ApiUrl != "https://staging.example" || ChainName != "Staging" || DaemonNames != "dingocoind" || DefaultPort != "33117" {
+		t.Errorf("after loadConfig: %s %s %s %s", ApiUrl, DaemonNames, DefaultPort, ChainName)
+	}
+	if want := map[string]string{"apiUrl": path, "chainName": path}; !reflect.DeepEqual(configSources, want) {
+		t.Errorf("configSources = %v, want %v", configSources, want)
+	}
+
+	if err := loadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("missing --config file accepted, want error")
+	}
+	bad := filepath.Join(t.TempDir(), "bad.yaml")
+	if err := os.WriteFile(bad, []byte("apiUrl: staging.example\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadConfig(bad); err == nil || ApiUrl != "https://staging.example" {
+		t.Errorf("invalid apiUrl: err = %v, ApiUrl = %s", err, ApiUrl)
+	}
+}
+
+func TestApplyEnvOverrides(t *testing.T) {
+	saved := []string{ApiUrl, DaemonNames, DefaultPort}
+	t.Cleanup(func() {
+		ApiUrl, DaemonNames, DefaultPort = saved[0], saved[1], saved[2]
+		configSources = map[string]string{}
+	})
+	ApiUrl, DaemonNames, DefaultPort = "https://map.example", "dingocoind", "33117"
+	configSources = map[string]string{"apiUrl": "config.yaml"}
+
+	t.Setenv("DINGO_VERIFY_API_URL", "http://staging.internal:3000/")
+	t.Setenv("DINGO_VERIFY_PORT", "")
+	t.Setenv("DINGO_VERIFY_DAEMONS", "dingocoind,dingod")
+	if err := applyEnvOverrides(); err != nil {
+		t.Fatalf("applyEnvOverrides: %v", err)
+	}
+	if ApiUrl != "http://staging.internal:3000" || DaemonNames != "dingocoind,dingod" || DefaultPort != "33117" {
+		t.Errorf("after applyEnvOverrides: %s %s %s", ApiUrl, DaemonNames, DefaultPort)
+	}
+	want := map[string]string{"apiUrl": "$DINGO_VERIFY_API_URL", "daemonNames": "$DINGO_VERIFY_DAEMONS"}
+	if !reflect.DeepEqual(configSources, want) {
+		t.Errorf("configSources = %v, want %v", configSources, want)
+	}
+
+	t.Setenv("DINGO_VERIFY_PORT", "70000")
+	if err := applyEnvOverrides(); err == nil || !strings.Contains(err.Error(), "DINGO_VERIFY_PORT") {
+		t.Errorf("invalid port: err = %v, want one naming the variable", err)
+	}
+}
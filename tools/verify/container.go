@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContainerInfo identifies the container the daemon runs in. The ID and
+// runtime come from the daemon's cgroup; name, image and published ports
+// from the Docker API when its socket is accessible.
+type ContainerInfo struct {
+	ID      string          `json:"id"`
+	Runtime string          `json:"runtime"`
+	Name    string          `json:"name,omitempty"`
+	Image   string          `json:"image,omitempty"`
+	Ports   []PublishedPort `json:"ports,omitempty"`
+}
+
+// PublishedPort maps a container port to a host port
+type PublishedPort struct {
+	ContainerPort int    `json:"containerPort"`
+	HostIP        string `json:"hostIp,omitempty"`
+	HostPort      int    `json:"hostPort"`
+}
+
+// cgroupContainer matches a container ID in a cgroup path, with the prefix
+// the runtime gives it: /docker/<id>, docker-<id>.scope,
+// cri-containerd-<id>.scope, crio-<id>.scope, libpod-<id>.scope, or a bare
+// <id> under kubepods
+var cgroupContainer = regexp.MustCompile(`(?:/|^)(?:(docker|cri-containerd|crio|libpod)[-/])?([0-9a-f]{64})(?:\.scope)?(?:/|$)`)
+
+// containerRuntimes names the runtime behind each cgroup prefix
+var containerRuntimes = map[string]string{
+	"docker":         "docker",
+	"cri-containerd": "containerd",
+	"crio":           "cri-o",
+	"libpod":         "podman",
+}
+
+// parseContainerCgroup finds the container in /proc/<pid>/cgroup content
+func parseContainerCgroup(cgroup string) (id, runtime string, ok bool) {
+	for _, line := range strings.Split(cgroup, "\n") {
+		// hierarchy-ID:controllers:path
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		m := cgroupContainer.FindStringSubmatch(parts[2])
+		if m == nil {
+			continue
+		}
+		runtime = containerRuntimes[m[1]]
+		if runtime == "" {
+			runtime = "unknown"
+			if strings.Contains(parts[2], "kubepods") {
+				runtime = "kubernetes"
+			}
+		}
+		return m[2], runtime, true
+	}
+	return "", "", false
+}
+
+// dockerInspect is the subset of GET /containers/{id}/json used here
+type dockerInspect struct {
+	Name   string `json:"Name"`
+	Config struct {
+		Image string `json:"Image"`
+	} `json:"Config"`
+	NetworkSettings struct {
+		Ports map[string][]struct {
+			HostIP   string `json:"HostIp"`
+			HostPort string `json:"HostPort"`
+		} `json:"Ports"`
+	} `json:"NetworkSettings"`
+}
+
+// publishedPorts lists the TCP ports a container publishes, sorted
+func (d dockerInspect) publishedPorts() []PublishedPort {
+	var ports []PublishedPort
+	for spec, bindings := range d.NetworkSettings.Ports {
+		port, proto, _ := strings.Cut(spec, "/")
+		containerPort, err := strconv.Atoi(port)
+		if err != nil || proto != "tcp" {
+			continue
+		}
+		for _, b := range bindings {
+			if hostPort, err := strconv.Atoi(b.HostPort); err == nil {
+				ports = append(ports, PublishedPort{containerPort, b.HostIP, hostPort})
+			}
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].ContainerPort != ports[j].ContainerPort {
+			return ports[i].ContainerPort < ports[j].ContainerPort
+		}
+		return ports[i].HostIP < ports[j].HostIP
+	})
+	return ports
+}
+
+// inspectDockerContainer asks the Docker API on socket about container id
+func inspectDockerContainer(ctx context.Context, socket, id string) (dockerInspect, error) {
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		},
+	}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://docker/containers/"+id+"/json", nil)
+	if err != nil {
+		return dockerInspect{}, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return dockerInspect{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return dockerInspect{}, fmt.Errorf("docker API: %s", resp.Status)
+	}
+	var info dockerInspect
+	return info, json.NewDecoder(resp.Body).Decode(&info)
+}
+
+// daemonContainer describes the container pid runs in, or nil when it runs
+// directly on the host (or the cgroup isn't readable, e.g. off Linux)
+func daemonContainer(ctx context.Context, pid int) *ContainerInfo {
+	cgroup, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return nil
+	}
+	id, runtime, ok := parseContainerCgroup(string(cgroup))
+	if !ok {
+		return nil
+	}
+	container := &ContainerInfo{ID: id, Runtime: runtime}
+	if runtime == "docker" && capabilities().DockerSocket {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		if info, err := inspectDockerContainer(ctx, dockerSocket, id); err == nil {
+			container.Name = strings.TrimPrefix(info.Name, "/")
+			container.Image = info.Config.Image
+			container.Ports = info.publishedPorts()
+		}
+	}
+	return container
+}
+
+// printContainer describes the daemon's container and warns when the
+// node's port is published under another number, which the map would
+// probe in vain
+func printContainer(c *ContainerInfo, port int) {
+	label := c.ID[:12]
+	if c.Name != "" {
+		label += " (" + c.Name + ")"
+	}
+	fmt.Print(tr("  ℹ️  Daemon runs in %s container %s\n", c.Runtime, label))
+	if len(c.Ports) == 0 {
+		return
+	}
+	for _, p := range c.Ports {
+		if p.HostPort == port {
+			return
+		}
+	}
+	fmt.Print(tr("  ⚠️  The container doesn't publish port %d on the host; check the -p/ports mapping\n", port))
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testContainerID = "4f2a9c1b7d3e8f6a5b0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a"
+
+func TestParseContainerCgroup(t *testing.T) {
+	tests := []struct {
+		name    string
+		cgroup  string
+		runtime string
+		found   bool
+	}{
+		{"docker cgroup v1", "12:memory:/docker/" + testContainerID + "\n0::/\n", "docker", true},
+		{"docker systemd cgroup v2", "0::/system.slice/docker-" + testContainerID + ".scope\n", "docker", true},
+		{"podman", "0::/user.slice/user-1000.slice/libpod-" + testContainerID + ".scope/container\n", "podman", true},
+		{"containerd", "0::/kubepods.slice/kubepods-pod1.slice/cri-containerd-" + testContainerID + ".scope\n", "containerd", true},
+		{"kubepods", "11:cpu:/kubepods/besteffort/pod1/" + testContainerID + "\n", "kubernetes", true},
+		{"host", "0::/system.slice/dingocoind.service\n", "", false},
+		{"id too short", "0::/docker/4f2a9c1b7d3e\n", "", false},
+	}
+	for _, tt := range tests {
+		id, runtime, ok := parseContainerCgroup(tt.cgroup)
+		if ok != tt.found || runtime != tt.runtime || (ok && id != testContainerID) {
+			t.Errorf("%s: got %q, %q, %v", tt.name, id, runtime, ok)
+		}
+	}
+}
+
+func TestInspectDockerContainer(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("no unix sockets: %v", err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/containers/"+testContainerID+"/json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{
+			"Name": "/dingocoin",
+			"Config": {"Image": "dingocoin/dingocoind:1.18"},
+			"NetworkSettings": {"Ports": {
+				"34646/tcp": null,
+				"33117/tcp": [{"HostIp": "0.0.0.0", "HostPort": "33117"}, {"HostIp": "::", "HostPort": "33117"}],
+				"33117/udp": [{"HostIp": "0.0.0.0", "HostPort": "33117"}]
+			}}
+		}`))
+	})}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	info, err := inspectDockerContainer(context.Background(), socket, testContainerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name != "/dingocoin" || info.Config.Image != "dingocoin/dingocoind:1.18" {
+		t.Errorf("inspect = %+v", info)
+	}
+	want := []PublishedPort{{33117, "0.0.0.0", 33117}, {33117, "::", 33117}}
+	if got := info.publishedPorts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("publishedPorts = %+v, want %+v", got, want)
+	}
+
+	if _, err := inspectDockerContainer(context.Background(), socket, "missing"); err == nil {
+		t.Error("unknown container accepted")
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/atlasp2p/verify/internal/corpus"
+)
+
+func TestPortParsersCorpus(t *testing.T) {
+	parsers := []struct {
+		tool      string
+		listening func(output string, port int) bool
+	}{
+		{"netstat", netstatListening},
+		{"ss", ssListening},
+		{"lsof", lsofListening},
+		{"sockstat", sockstatListening},
+	}
+
+	for _, p := range parsers {
+		samples := corpus.Samples(p.tool)
+		if len(samples) == 0 {
+			t.Fatalf("no %s samples in the corpus", p.tool)
+		}
+		for _, s := range samples {
+			ports := []struct {
+				port int
+				want bool
+			}{
+				{corpus.P2PPort, s.Running()},
+				{3311, false},  // prefix of the P2P port, and a unix socket path
+				{3117, false},  // suffix of the P2P port
+				{50112, false}, // an outbound connection's local port
+			}
+			// lsof is asked about the P2P port only
+			if p.tool != "lsof" {
+				ports = append(ports, struct {
+					port int
+					want bool
+				}{corpus.RPCPort, s.Running()})
+			}
+			for _, tt := range ports {
+				if got := p.listening(s.Output, tt.port); got != tt.want {
+					t.Errorf("%s on %s: listening on %d = %v, want %v", p.tool, s.Platform, tt.port, got, tt.want)
+				}
+			}
+		}
+	}
+}
+
+// The P2P port listens on all addresses and the RPC port on loopback only,
+// which is what the bind address warning tells apart
+func TestListenAddrsCorpus(t *testing.T) {
+	parsers := []struct {
+		tool  string
+		addrs func(output string, port int) []string
+	}{
+		{"netstat", netstatListenAddrs},
+		{"ss", ssListenAddrs},
+		{"lsof", lsofListenAddrs},
+		{"sockstat", sockstatListenAddrs},
+	}
+
+	for _, p := range parsers {
+		for _, s := range corpus.Samples(p.tool) {
+			if !s.Running() {
+				continue
+			}
+			if addrs := p.addrs(s.Output, corpus.P2PPort); len(addrs) == 0 || localOnly(addrs) {
+				t.Errorf("%s on %s: P2P port on %q, want all addresses", p.tool, s.Platform, addrs)
+			}
+			if p.tool == "lsof" {
+				continue
+			}
+			if addrs := p.addrs(s.Output, corpus.RPCPort); !localOnly(addrs) {
+				t.Errorf("%s on %s: RPC port on %q, want loopback only", p.tool, s.Platform, addrs)
+			}
+		}
+	}
+}
+
+// sockstat names the owner of each socket it can see
+func TestSockstatOwnerCorpus(t *testing.T) {
+	for _, s := range corpus.Samples("sockstat") {
+		pid, name, ok := sockstatOwner(s.Output, corpus.P2PPort)
+		if ok != s.Running() || (ok && (pid == 0 || name != corpus.Daemon)) {
+			t.Errorf("%s: owner of the P2P port = %d %q, %v", s.Platform, pid, name, ok)
+		}
+		if _, _, ok := sockstatOwner(s.Output, 111); ok {
+			t.Errorf("%s: owner reported for a socket sockstat shows as ?", s.Platform)
+		}
+	}
+}
+
+func TestProcessTableCorpus(t *testing.T) {
+	samples := corpus.Samples("ps")
+	if len(samples) == 0 {
+		t.Fatal("no ps samples in the corpus")
+	}
+
+	for _, s := range samples {
+		table := parseProcessTable(s.Output)
+		if len(table) == 0 {
+			t.Errorf("%s: no processes parsed", s.Platform)
+			continue
+		}
+		for pid, entry := range table {
+			if _, ok := table[entry.ppid]; !ok && entry.ppid != 0 {
+				t.Errorf("%s: pid %d has unknown parent %d", s.Platform, pid, entry.ppid)
+			}
+		}
+
+		want := 0
+		if s.Running() {
+			want = 1
+		}
+		if pids := daemonCandidates(table, corpus.Daemon, 0); len(pids) != want {
+			t.Errorf("%s: %d %s candidates %v, want %d", s.Platform, len(pids), corpus.Daemon, pids, want)
+		}
+	}
+}
+
+func TestAddressHasPort(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"0.0.0.0:33117", true},
+		{"[::]:33117", true},
+		{":::33117", true},
+		{"*:33117", true},
+		{"*.33117", true},
+		{"127.0.0.53%lo:33117", true},
+		{"192.168.1.23.33117", true},
+		{"0.0.0.0:331170", false},
+		{"0.0.0.0:3311", false},
+		{"*.*", false},
+		{"33117", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := addressHasPort(tt.addr, 33117); got != tt.want {
+			t.Errorf("addressHasPort(%q) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestListenHost(t *testing.T) {
+	tests := map[string]string{
+		"0.0.0.0:33117":        "0.0.0.0",
+		"127.0.0.1.34646":      "127.0.0.1",
+		"[::]:33117":           "::",
+		":::33117":             "::",
+		"::1.34646":            "::1",
+		"*:33117":              "*",
+		"*.33117":              "*",
+		"127.0.0.53%lo:53":     "127.0.0.53",
+		"[fe80::1%eth0]:33117": "fe80::1",
+		"[2001:db8::4]:33117":  "2001:db8::4",
+		"33117":                "",
+	}
+	for addr, want := range tests {
+		if got := listenHost(addr); got != want {
+			t.Errorf("listenHost(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
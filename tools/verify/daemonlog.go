@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/atlasp2p/verify/internal/flags"
+)
+
+// How much of the existing log to scan before following new lines
+// (--log-backlog)
+var daemonLogBacklog = flags.ByteSize(64 << 10)
+
+// Log lines worth showing next to the process/port checks
+var daemonLogPatterns = []struct {
+	match string
+	icon  string
+}{
+	{"Bound to", "✅"},
+	{"Unable to bind", "❌"},
+	{"Cannot bind", "❌"},
+	{"Address already in use", "❌"},
+	{"Failed to listen", "❌"},
+	{"Error:", "⚠️ "},
+	{"Shutdown: done", "⚠️ "},
+}
+
+// dataDir is the daemon's data directory (--datadir, or the OS default)
+var dataDir string
+
+// daemonConfPath returns the daemon's config file inside dataDir
+func daemonConfPath() string {
+	return filepath.Join(dataDir, strings.ToLower(ChainName)+".conf")
+}
+
+// defaultDataDir returns the daemon's default data directory for this OS,
+// following the Bitcoin-derived layout used by the chain daemons
+func defaultDataDir() string {
+	name := strings.ToLower(ChainName)
+	home, _ := os.UserHomeDir()
+
+	switch runtime.GOOS {
+	case "windows":
+		return filepath.Join(os.Getenv("APPDATA"), ChainName)
+	case "darwin":
+		return filepath.Join(home, "Library", "Application Support", ChainName)
+	default:
+		return filepath.Join(home, "."+name)
+	}
+}
+
+// followDaemonLog prints relevant debug.log lines to out, starting with the
+// recent backlog, until stop is closed. Lines logged before since (the
+// daemon's start time, zero if unknown) belong to an earlier run and are
+// skipped. The returned channel closes when it exits.
+func followDaemonLog(path string, since time.Time, out io.Writer, stop <-chan struct{}) <-chan struct{} {
+	done := make(chan struct{})
+
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(out, "  ⚠️  Cannot read daemon log: %v\n", err)
+		close(done)
+		return done
+	}
+
+	// The backlog usually starts mid-line; drop that fragment
+	skipFirst := false
+	backlog := int64(daemonLogBacklog)
+	if info, err := f.Stat(); err == nil && info.Size() > backlog {
+		f.Seek(-backlog-1, io.SeekEnd)
+		prev := make([]byte, 1)
+		f.Read(prev)
+		skipFirst = prev[0] != '\n'
+	}
+
+	go func() {
+		defer close(done)
+		defer f.Close()
+
+		reader := bufio.NewReader(f)
+		partial := ""
+		for {
+			line, err := reader.ReadString('\n')
+			if err == nil {
+				line, partial = partial+line, ""
+				if skipFirst {
+					skipFirst = false
+					continue
+				}
+				if !loggedBefore(line, since) {
+					printDaemonLogLine(out, line)
+				}
+				continue
+			}
+			partial += line
+
+			select {
+			case <-stop:
+				return
+			case <-time.After(200 * time.Millisecond):
+			}
+		}
+	}()
+
+	return done
+}
+
+// loggedBefore reports whether line carries a timestamp (as debug.log lines
+// start with, e.g. "2024-01-02T15:04:05Z") earlier than since
+func loggedBefore(line string, since time.Time) bool {
+	if since.IsZero() {
+		return false
+	}
+	stamp, _, _ := strings.Cut(line, " ")
+	logged, err := time.Parse(time.RFC3339Nano, stamp)
+	return err == nil && logged.Before(since)
+}
+
+func printDaemonLogLine(out io.Writer, line string) {
+	line = strings.TrimSpace(line)
+	for _, p := range daemonLogPatterns {
+		if strings.Contains(line, p.match) {
+			fmt.Fprintf(out, "     %s log: %s\n", p.icon, line)
+			return
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readDaemonLog runs followDaemonLog over a finished log and returns what
+// it printed
+func readDaemonLog(t *testing.T, content string, since time.Time) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "debug.log")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	stop := make(chan struct{})
+	close(stop)
+	<-followDaemonLog(path, since, &out, stop)
+	return out.String()
+}
+
+func TestFollowDaemonLogBacklog(t *testing.T) {
+	filler := strings.Repeat("2026-10-15T11:00:00Z UpdateTip: new best\n", 3000)
+	straddling := "2026-10-15T11:00:00Z Bound to [::]:33117 (old)\n"
+	last := "2026-10-15T11:00:01Z Bound to 0.0.0.0:33117\n"
+	pad := 65536 + 10 - len(straddling) - len(last)
+	tail := strings.Repeat("x", pad-1) + "\n" + last
+
+	tests := []struct {
+		name    string
+		content string
+		since   time.Time
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "smaller than backlog",
+			content: "2026-10-15T11:00:00Z Bound to 0.0.0.0:33117\n2026-10-15T11:00:01Z Unable to bind to [::]:33117\n",
+			want:    []string{"Bound to 0.0.0.0:33117", "Unable to bind"},
+		},
+		{
+			name:    "partial first line dropped",
+			content: filler + straddling + tail,
+			want:    []string{"Bound to 0.0.0.0:33117"},
+			notWant: []string{"(old)"},
+		},
+		{
+			name:    "backlog starts on a line boundary",
+			content: filler + "Bound to first" + strings.Repeat("y", 65536-len(last)-15) + "\n" + last,
+			want:    []string{"Bound to first", "Bound to 0.0.0.0:33117"},
+		},
+		{
+			name: "lines from an earlier run skipped",
+			content: "2026-10-15T09:00:00Z Error: Unable to bind, shutting down\n" +
+				"2026-10-15T09:00:01Z Shutdown: done\n" +
+				"2026-10-15T10:00:05.123456Z Bound to 0.0.0.0:33117\n" +
+				"no timestamp Error: kept\n",
+			since:   time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC),
+			want:    []string{"Bound to 0.0.0.0:33117", "Error: kept"},
+			notWant: []string{"shutting down", "Shutdown: done"},
+		},
+	}
+
+	for _, tt := range tests {
+		got := readDaemonLog(t, tt.content, tt.since)
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s: output lacks %q:\n%s", tt.name, want, got)
+			}
+		}
+		for _, notWant := range tt.notWant {
+			if strings.Contains(got, notWant) {
+				t.Errorf("%s: output contains %q:\n%s", tt.name, notWant, got)
+			}
+		}
+	}
+}
+
+func TestProcessEvidenceStarted(t *testing.T) {
+	tests := []struct {
+		evidence *ProcessEvidence
+		want     time.Time
+	}{
+		{nil, time.Time{}},
+		{&ProcessEvidence{}, time.Time{}},
+		{&ProcessEvidence{StartTime: "garbage"}, time.Time{}},
+		{&ProcessEvidence{StartTime: "Thu Oct  1 09:05:03 2026"}, time.Date(2026, 10, 1, 9, 5, 3, 0, time.Local)},
+		{&ProcessEvidence{StartTime: "Thu Oct 15 12:26:21 2026"}, time.Date(2026, 10, 15, 12, 26, 21, 0, time.Local)},
+	}
+
+	for _, tt := range tests {
+		if got := tt.evidence.started(); !got.Equal(tt.want) {
+			t.Errorf("started(%+v) = %v, want %v", tt.evidence, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// daemonPattern is one entry of DaemonNames: a process name, a glob such as
+// dingocoind-* for versioned or renamed binaries, or a regular expression
+// after "re:". Globs and expressions match the whole name.
+type daemonPattern string
+
+// daemonPatterns splits DaemonNames into its entries
+func daemonPatterns() []daemonPattern {
+	var patterns []daemonPattern
+	for _, entry := range strings.Split(DaemonNames, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			patterns = append(patterns, daemonPattern(entry))
+		}
+	}
+	return patterns
+}
+
+// literal reports whether the pattern is a plain process name
+func (p daemonPattern) literal() bool {
+	return !strings.HasPrefix(string(p), "re:") && !strings.ContainsAny(string(p), "*?[")
+}
+
+func (p daemonPattern) match(name string) bool {
+	if expr, ok := strings.CutPrefix(string(p), "re:"); ok {
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		return err == nil && re.MatchString(name)
+	}
+	ok, _ := path.Match(string(p), name)
+	return ok
+}
+
+// validate rejects a pattern that can't match a process name
+func (p daemonPattern) validate() bool {
+	if expr, ok := strings.CutPrefix(string(p), "re:"); ok {
+		_, err := regexp.Compile("^(?:" + expr + ")$")
+		return expr != "" && err == nil
+	}
+	if p == "" || strings.ContainsAny(string(p), ` /\`) {
+		return false
+	}
+	_, err := path.Match(string(p), "")
+	return err == nil
+}
+
+// primaryDaemon is the daemon named in suggestions and derived names (the
+// RPC client, the service to start): the first plain name in DaemonNames
+func primaryDaemon() string {
+	patterns := daemonPatterns()
+	for _, p := range patterns {
+		if p.literal() {
+			return string(p)
+		}
+	}
+	if len(patterns) == 0 {
+		return ""
+	}
+	return string(patterns[0])
+}
+
+// isDaemonName reports whether name matches one of the configured daemon
+// names
+func isDaemonName(name string) bool {
+	for _, p := range daemonPatterns() {
+		if p.match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// daemonNames returns the process names to look for: DaemonNames' plain
+// names in order, followed by the running programs its patterns match by
+// argv[0] or by executable, so a binary renamed to dingocoind-1.17 or
+// started through a symlink of another name is found too, and by the
+// names snaps and Flatpaks of the daemon run under
+func daemonNames(ctx context.Context) []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	var patterns []daemonPattern
+	for _, p := range daemonPatterns() {
+		if p.literal() {
+			add(string(p))
+		} else {
+			patterns = append(patterns, p)
+		}
+	}
+
+	table, err := processTable(ctx)
+	if err != nil {
+		return names
+	}
+	pids := make([]int, 0, len(table))
+	for pid := range table {
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+	for _, pid := range pids {
+		name := table[pid].name()
+		exe, _ := processExe(pid)
+		for _, p := range patterns {
+			if p.match(name) || (exe != "" && p.match(filepath.Base(exe))) {
+				add(name)
+				break
+			}
+		}
+		if _, ok := packagedDaemon(table, pid); ok {
+			add(name)
+		}
+	}
+	return names
+}
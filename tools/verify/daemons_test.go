@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestDaemonPatternMatch(t *testing.T) {
+	tests := []struct {
+		pattern daemonPattern
+		name    string
+		want    bool
+	}{
+		{"dingocoind", "dingocoind", true},
+		{"dingocoind", "dingocoind-1.17", false},
+		{"dingocoind*", "dingocoind-1.17", true},
+		{"dingocoind-?.??", "dingocoind-1.17", true},
+		{"dingocoind*", "dingocoin-cli", false},
+		{`re:dingocoind(-\d+\.\d+)?`, "dingocoind", true},
+		{`re:dingocoind(-\d+\.\d+)?`, "dingocoind-1.17", true},
+		{`re:dingocoind(-\d+\.\d+)?`, "xdingocoind", false},
+		{"re:dingocoind(", "dingocoind(", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.pattern.match(tt.name); got != tt.want {
+			t.Errorf("%q.match(%q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPrimaryDaemon(t *testing.T) {
+	saved := DaemonNames
+	defer func() { DaemonNames = saved }()
+
+	tests := []struct{ names, want string }{
+		{"dingocoind", "dingocoind"},
+		{"dingocoind-*, dingocoind", "dingocoind"},
+		{"re:dingo.*", "re:dingo.*"},
+	}
+	for _, tt := range tests {
+		DaemonNames = tt.names
+		if got := primaryDaemon(); got != tt.want {
+			t.Errorf("primaryDaemon() with %q = %q, want %q", tt.names, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cap on each logged body or command output
+const debugLogMaxBytes = 4 << 10
+
+// debugLog records what the tool does behind its progress output: API
+// requests, RPC calls and the commands it runs. It is silent unless
+// --verbose (requests and commands) or --debug (also bodies and outputs)
+// is given, and writes to stderr so --json output stays clean.
+var debugLog = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// splitLogFlags removes --verbose and --debug from args. Like --config
+// they apply to every command, so they are handled before subcommands.
+func splitLogFlags(args []string) (level slog.Level, enabled bool, rest []string) {
+	level = slog.LevelInfo
+	for i, arg := range args {
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		switch strings.TrimLeft(arg, "-") {
+		case "verbose":
+			enabled = true
+			continue
+		case "debug":
+			level, enabled = slog.LevelDebug, true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return level, enabled, rest
+}
+
+func setupDebugLog(level slog.Level) {
+	debugLog = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+}
+
+// Secrets that must never reach the log, such as the challenge, which
+// proves ownership of the node until it is used
+var (
+	redactMu   sync.Mutex
+	redactions []string
+)
+
+func addRedaction(secret string) {
+	if secret == "" {
+		return
+	}
+	redactMu.Lock()
+	defer redactMu.Unlock()
+	redactions = append(redactions, secret)
+}
+
+func redact(s string) string {
+	redactMu.Lock()
+	defer redactMu.Unlock()
+	for _, secret := range redactions {
+		s = strings.ReplaceAll(s, secret, "[REDACTED]")
+	}
+	return s
+}
+
+// logExcerpt prepares a body or output for the log
+func logExcerpt(b []byte) string {
+	s := redact(string(b))
+	if len(s) > debugLogMaxBytes {
+		s = s[:debugLogMaxBytes] + "...(truncated)"
+	}
+	return s
+}
+
+// loggingTransport logs API requests, and with --debug their bodies.
+// Headers are never logged; they may carry an API key.
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+func (t loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	withBodies := debugLog.Enabled(ctx, slog.LevelDebug)
+	if withBodies && req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			body.Close()
+			debugLog.Debug("api request body", "url", redact(req.URL.String()), "body", logExcerpt(data))
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		debugLog.Info("api request failed", "method", req.Method, "url", redact(req.URL.String()), "err", redact(err.Error()))
+		return nil, err
+	}
+	debugLog.Info("api request", "method", req.Method, "url", redact(req.URL.String()),
+		"status", resp.StatusCode, "duration", time.Since(start).Round(time.Millisecond))
+
+	if withBodies {
+		data, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		if readErr != nil {
+			return nil, readErr
+		}
+		debugLog.Debug("api response body", "url", redact(req.URL.String()), "body", logExcerpt(data))
+	}
+	return resp, nil
+}
+
+// commandOutput runs cmd like cmd.Output and logs it. A command made with
+// exec.CommandContext is killed when its context expires; children it left
+// holding the output pipe get a second before the pipe is closed.
+func commandOutput(cmd *exec.Cmd) ([]byte, error) {
+	cmd.WaitDelay = time.Second
+	start := time.Now()
+	output, err := cmd.Output()
+	args := redact(strings.Join(cmd.Args, " "))
+	attrs := []any{"cmd", args, "duration", time.Since(start).Round(time.Millisecond)}
+	if err != nil {
+		attrs = append(attrs, "err", err)
+	}
+	debugLog.Info("command", attrs...)
+	if debugLog.Enabled(context.Background(), slog.LevelDebug) {
+		debugLog.Debug("command output", "cmd", args, "output", logExcerpt(output))
+	}
+	return output, err
+}
+
+// CloseIdleConnections lets pinAPIFamily drop pooled connections
+func (t loggingTransport) CloseIdleConnections() {
+	if c, ok := t.next.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
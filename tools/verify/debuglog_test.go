@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitLogFlags(t *testing.T) {
+	tests := []struct {
+		args        []string
+		wantLevel   slog.Level
+		wantEnabled bool
+		wantRest    []string
+	}{
+		{[]string{"abc123"}, slog.LevelInfo, false, []string{"abc123"}},
+		{[]string{"--verbose", "abc123"}, slog.LevelInfo, true, []string{"abc123"}},
+		{[]string{"status", "-debug", "abc123"}, slog.LevelDebug, true, []string{"status", "abc123"}},
+		{[]string{"--debug", "--verbose"}, slog.LevelDebug, true, nil},
+		{[]string{"note", "--", "--debug"}, slog.LevelInfo, false, []string{"note", "--", "--debug"}},
+	}
+
+	for _, tt := range tests {
+		level, enabled, rest := splitLogFlags(tt.args)
+		if level != tt.wantLevel || enabled != tt.wantEnabled || !reflect.DeepEqual(rest, tt.wantRest) {
+			t.Errorf("splitLogFlags(%q) = %v, %v, %q; want %v, %v, %q", tt.args, level, enabled, rest, tt.wantLevel, tt.wantEnabled, tt.wantRest)
+		}
+	}
+}
+
+func TestLoggingTransportRedacts(t *testing.T) {
+	const challenge = "abcdefghij0123456789secret"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	var logged bytes.Buffer
+	saved, savedRedactions := debugLog, redactions
+	t.Cleanup(func() { debugLog, redactions = saved, savedRedactions })
+	debugLog = slog.New(slog.NewTextHandler(&logged, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	addRedaction(challenge)
+
+	client := &http.Client{Transport: loggingTransport{http.DefaultTransport}}
+	resp, err := client.Post(server.URL+"/api/verify-node/init", "application/json",
+		strings.NewReader(`{"challenge":"`+challenge+`"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	// The caller still gets the untouched body
+	if !strings.Contains(string(body), challenge) {
+		t.Errorf("response body altered: %s", body)
+	}
+	out := logged.String()
+	if strings.Contains(out, challenge) {
+		t.Errorf("challenge in log:\n%s", out)
+	}
+	for _, want := range []string{"api request body", "api response body", "status=200", "[REDACTED]"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestCommandOutputKilledOnDeadline(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// The background sleep keeps stdout open after sh is killed
+	start := time.Now()
+	_, err := commandOutput(exec.CommandContext(ctx, "sh", "-c", "sleep 30 & sleep 30"))
+	if err == nil {
+		t.Fatal("hung command succeeded")
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("hung command returned after %s", d)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// decodeResponse unmarshals an API response into v. The backend and the
+// tool are upgraded separately, so a response may carry fields this build
+// does not know or change the type of one it does. A field of the wrong
+// type is left empty with a warning instead of failing the run, and the
+// unknown top-level fields are returned so they can be passed on. Only a
+// body that is not JSON is an error.
+func decodeResponse(body []byte, v any) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(body, v); err != nil {
+		// encoding/json keeps decoding after a type mismatch and reports
+		// the first one, so everything else is already filled in
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			return nil, err
+		}
+		fmt.Printf("⚠️  Ignoring API response field %q: expected %s, got %s\n", typeErr.Field, typeErr.Type, typeErr.Value)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, nil
+	}
+	known := jsonFieldNames(reflect.TypeOf(v))
+	for name := range fields {
+		if known[strings.ToLower(name)] {
+			delete(fields, name)
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// jsonFieldNames returns the lowercased JSON names of a struct's fields, as
+// encoding/json matches them case-insensitively
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	names := make(map[string]bool)
+	if t.Kind() != reflect.Struct {
+		return names
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+	return names
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeResponse(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		want      ConfirmResponse
+		wantExtra []string
+		wantErr   bool
+	}{
+		{
+			name: "known fields",
+			body: `{"success":true,"status":"pending_approval"}`,
+			want: ConfirmResponse{Success: true, Status: "pending_approval"},
+		},
+		{
+			name:      "new fields kept",
+			body:      `{"success":true,"reviewEta":"2h","queue":{"position":3}}`,
+			want:      ConfirmResponse{Success: true},
+			wantExtra: []string{"queue", "reviewEta"},
+		},
+		{
+			name: "changed type left empty",
+			body: `{"success":true,"status":{"state":"pending"},"message":"ok"}`,
+			want: ConfirmResponse{Success: true, Message: "ok"},
+		},
+		{
+			name: "nested changed type",
+			body: `{"success":true,"addrRelay":{"success":true,"addressCount":"12","relays":true}}`,
+			want: ConfirmResponse{Success: true, AddrRelay: &AddrRelayResult{Success: true, Relays: true}},
+		},
+		{
+			name: "field names match case-insensitively",
+			body: `{"Success":true,"STATUS":"pending_approval"}`,
+			want: ConfirmResponse{Success: true, Status: "pending_approval"},
+		},
+		{
+			name:    "not JSON",
+			body:    `<html>502 Bad Gateway</html>`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		var got ConfirmResponse
+		extra, err := decodeResponse([]byte(tt.body), &got)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+		var gotExtra []string
+		for name := range extra {
+			gotExtra = append(gotExtra, name)
+		}
+		if len(gotExtra) != len(tt.wantExtra) {
+			t.Errorf("%s: extra fields %v, want %v", tt.name, gotExtra, tt.wantExtra)
+			continue
+		}
+		for _, name := range tt.wantExtra {
+			if _, ok := extra[name]; !ok {
+				t.Errorf("%s: extra fields %v lack %q", tt.name, gotExtra, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// ipFamily selects the address family for API connections: "4" or "6"
+// forces one, "" prefers IPv4 and falls back to IPv6
+var ipFamily string
+
+// Per-family connect timeout, short enough that a broken route fails over
+// quickly instead of hanging for the whole request timeout
+const dialTimeout = 10 * time.Second
+
+// The IPv6 fallback is announced once, not on every request
+var ipv6FallbackWarning sync.Once
+
+// dialAPI connects to the API preferring IPv4, because the backend compares
+// the request IP with the IPv4 address the crawler recorded for the node.
+// IPv6 is only used when forced or when no IPv4 connection can be made.
+func dialAPI(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout:   dialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	switch ipFamily {
+	case "4":
+		return dialer.DialContext(ctx, "tcp4", addr)
+	case "6":
+		return dialer.DialContext(ctx, "tcp6", addr)
+	}
+
+	conn, err4 := dialer.DialContext(ctx, "tcp4", addr)
+	if err4 == nil {
+		return conn, nil
+	}
+	if ctx.Err() != nil {
+		return nil, err4
+	}
+
+	conn, err6 := dialer.DialContext(ctx, "tcp6", addr)
+	if err6 == nil {
+		ipv6FallbackWarning.Do(func() {
+			fmt.Printf("  ⚠️  Could not reach the API over IPv4 (%v), using IPv6\n", err4)
+			fmt.Println("     The map may see a different address than your node's IPv4 address.")
+		})
+		return conn, nil
+	}
+	return nil, errors.Join(
+		fmt.Errorf("ipv4: %w", err4),
+		fmt.Errorf("ipv6: %w", err6),
+	)
+}
+
+// pinAPIFamily locks API connections to the node's address family once init
+// has returned it, so confirm comes from the same address the backend
+// compares against. Pooled connections from the other family are dropped.
+func pinAPIFamily(nodeIP string) {
+	if ipFamily != "" {
+		return
+	}
+	ip := net.ParseIP(nodeIP)
+	if ip == nil || ip.To4() == nil {
+		return
+	}
+	ipFamily = "4"
+	httpClient.CloseIdleConnections()
+}
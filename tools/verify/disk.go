@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Block data written within this window is used to estimate growth
+const diskGrowthWindow = 30 * 24 * time.Hour
+
+// Warn when the data directory's filesystem drops below either limit
+const (
+	diskMinFreeBytes   = 5 << 30
+	diskMinFreePercent = 5
+	diskMinDaysLeft    = 30
+)
+
+// DiskHealth describes the filesystem holding the daemon's data directory
+type DiskHealth struct {
+	FreeGB      float64 `json:"freeGB"`
+	TotalGB     float64 `json:"totalGB"`
+	ReadOnly    bool    `json:"readOnly"`
+	GrowthGBDay float64 `json:"growthGBPerDay,omitempty"`
+	DaysLeft    int     `json:"daysLeft,omitempty"`
+}
+
+// checkDiskHealth inspects dir's filesystem. ok is false where the OS
+// exposes no usage figures or dir is unreadable.
+func checkDiskHealth(dir string) (*DiskHealth, bool) {
+	free, total, readOnly, ok := diskUsage(dir)
+	if !ok || total == 0 {
+		return nil, false
+	}
+
+	health := &DiskHealth{
+		FreeGB:   float64(free) / (1 << 30),
+		TotalGB:  float64(total) / (1 << 30),
+		ReadOnly: readOnly,
+	}
+	if perDay, ok := blockDataGrowth(filepath.Join(dir, "blocks")); ok && perDay > 0 {
+		health.GrowthGBDay = perDay / (1 << 30)
+		health.DaysLeft = int(float64(free) / perDay)
+	}
+	return health, true
+}
+
+// blockDataGrowth estimates bytes written per day from the finished blk
+// files completed within diskGrowthWindow. A finished file is never
+// rewritten, so its mtime dates its data. The newest blk file is still
+// filling and rev files are rewritten by -reindex, so neither counts. A
+// node that synced within the window has no history and gets no estimate.
+func blockDataGrowth(blocksDir string) (float64, bool) {
+	entries, err := os.ReadDir(blocksDir)
+	if err != nil {
+		return 0, false
+	}
+
+	// ReadDir sorts by name, so the zero-padded blk files come in order
+	var finished []fs.FileInfo
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, "blk") || !strings.HasSuffix(name, ".dat") {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			finished = append(finished, info)
+		}
+	}
+	if len(finished) < 2 {
+		return 0, false
+	}
+	finished = finished[:len(finished)-1]
+
+	since := time.Now().Add(-diskGrowthWindow)
+	if !finished[0].ModTime().Before(since) {
+		return 0, false
+	}
+
+	var recent int64
+	for _, info := range finished {
+		if info.ModTime().After(since) {
+			recent += info.Size()
+		}
+	}
+	return float64(recent) / diskGrowthWindow.Hours() * 24, true
+}
+
+// printDiskHealth reports the data directory's filesystem and warns before
+// the node runs out of space
+func printDiskHealth(health *DiskHealth) {
+	fmt.Printf("  ℹ️  Data directory disk: %.1f GB free of %.1f GB\n", health.FreeGB, health.TotalGB)
+
+	if health.ReadOnly {
+		fmt.Println("  ⚠️  The filesystem is mounted read-only; the daemon cannot write blocks")
+		fmt.Println("     (often a remount after disk errors, check dmesg)")
+	}
+	if health.FreeGB*(1<<30) < diskMinFreeBytes || health.FreeGB < health.TotalGB*diskMinFreePercent/100 {
+		fmt.Println("  ⚠️  Disk space is low; the daemon shuts down when the disk fills up")
+	}
+	if health.DaysLeft > 0 {
+		fmt.Printf("     Block data grows about %.2f GB/day, roughly %d days until full\n", health.GrowthGBDay, health.DaysLeft)
+		if health.DaysLeft < diskMinDaysLeft {
+			fmt.Println("  ⚠️  Free up space or move the data directory soon")
+		}
+	}
+}
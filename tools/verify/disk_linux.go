@@ -0,0 +1,16 @@
+package main
+
+import "syscall"
+
+// ST_RDONLY from statvfs(3)
+const stRdonly = 0x1
+
+// diskUsage returns the free and total bytes of path's filesystem and
+// whether it is mounted read-only
+func diskUsage(path string) (free, total uint64, readOnly, ok bool) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, 0, false, false
+	}
+	return st.Bavail * uint64(st.Bsize), st.Blocks * uint64(st.Bsize), st.Flags&stRdonly != 0, true
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package main
+
+// Filesystem usage is only read on Linux (statfs)
+func diskUsage(path string) (free, total uint64, readOnly, ok bool) {
+	return 0, 0, false, false
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBlockDataGrowth(t *testing.T) {
+	day := 24 * time.Hour
+	type file struct {
+		name string
+		size int
+		age  time.Duration
+	}
+	tests := []struct {
+		name   string
+		files  []file
+		want   float64
+		wantOK bool
+	}{
+		{"no blocks dir", nil, 0, false},
+		{"single file", []file{{"blk00000.dat", 100, 90 * day}}, 0, false},
+		{"synced within window", []file{
+			{"blk00000.dat", 300, 5 * day},
+			{"blk00001.dat", 300, 1 * day},
+			{"blk00002.dat", 10, 0},
+		}, 0, false},
+		{"finished files only", []file{
+			{"blk00000.dat", 1000, 90 * day},
+			{"blk00001.dat", 600, 20 * day},
+			{"blk00002.dat", 300, 10 * day},
+			{"blk00003.dat", 50, 0},
+			{"rev00002.dat", 5000, 0},
+			{"index", 0, 0},
+		}, 30, true},
+		{"no recent growth", []file{
+			{"blk00000.dat", 1000, 90 * day},
+			{"blk00001.dat", 50, 60 * day},
+		}, 0, true},
+	}
+
+	for _, tt := range tests {
+		dir := t.TempDir()
+		blocks := filepath.Join(dir, "blocks")
+		if tt.files != nil {
+			if err := os.Mkdir(blocks, 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, f := range tt.files {
+			path := filepath.Join(blocks, f.name)
+			if err := os.WriteFile(path, make([]byte, f.size), 0644); err != nil {
+				t.Fatal(err)
+			}
+			mtime := time.Now().Add(-f.age)
+			if err := os.Chtimes(path, mtime, mtime); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		got, ok := blockDataGrowth(blocks)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("%s: blockDataGrowth = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestCheckDiskHealth(t *testing.T) {
+	if _, _, _, ok := diskUsage(t.TempDir()); !ok {
+		t.Skip("no filesystem usage on this OS")
+	}
+
+	tests := []struct {
+		name   string
+		dir    string
+		wantOK bool
+	}{
+		{"temp dir", t.TempDir(), true},
+		{"missing dir", filepath.Join(t.TempDir(), "missing"), false},
+	}
+
+	for _, tt := range tests {
+		health, ok := checkDiskHealth(tt.dir)
+		if ok != tt.wantOK {
+			t.Errorf("%s: ok = %v, want %v", tt.name, ok, tt.wantOK)
+			continue
+		}
+		if ok && (health.TotalGB <= 0 || health.FreeGB > health.TotalGB) {
+			t.Errorf("%s: implausible usage %+v", tt.name, health)
+		}
+		if ok && health.DaysLeft != 0 {
+			t.Errorf("%s: growth estimated without block data: %+v", tt.name, health)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// DoctorCheck is one line of the pass/fail matrix verify doctor prints
+// before an operator spends a challenge
+type DoctorCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+	// Failures of network checks exit with exitAPIUnreachable
+	network bool
+}
+
+// environmentChecks tests what a verification needs besides the evidence
+// sources: the daemon's data directory, and DNS, a connection and an
+// HTTP(S) request to the map. A network check whose predecessor failed is
+// reported as skipped.
+func environmentChecks(ctx context.Context, apiURL, dir string) []DoctorCheck {
+	var checks []DoctorCheck
+
+	dirCheck := DoctorCheck{Name: "Data directory", Detail: dir}
+	if info, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		dirCheck.Detail = dir + " doesn't exist (see --datadir)"
+	} else if err != nil {
+		dirCheck.Detail = err.Error()
+	} else if !info.IsDir() {
+		dirCheck.Detail = dir + " is not a directory"
+	} else {
+		dirCheck.OK = true
+	}
+	checks = append(checks, dirCheck)
+
+	u, err := url.Parse(apiURL)
+	if err != nil || u.Hostname() == "" {
+		return append(checks, DoctorCheck{Name: "API URL", Detail: fmt.Sprintf("%q is not usable", apiURL), network: true})
+	}
+	host, port := u.Hostname(), u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+	resolve := DoctorCheck{Name: "API host resolves", network: true}
+	connect := DoctorCheck{Name: "API reachable (TCP " + port + ")", network: true}
+	request := DoctorCheck{Name: strings.ToUpper(u.Scheme) + " request to the API", network: true}
+
+	if net.ParseIP(host) != nil {
+		resolve.OK, resolve.Detail = true, host+" is an IP address"
+	} else if addrs, err := net.DefaultResolver.LookupHost(ctx, host); err != nil {
+		resolve.Detail = err.Error()
+	} else {
+		resolve.OK, resolve.Detail = true, strings.Join(addrs, ", ")
+	}
+
+	switch {
+	case !resolve.OK:
+		connect.Detail = "skipped, the host doesn't resolve"
+	default:
+		conn, err := dialAPI(ctx, "tcp", net.JoinHostPort(host, port))
+		if err != nil {
+			connect.Detail = strings.ReplaceAll(err.Error(), "\n", "; ")
+		} else {
+			connect.OK, connect.Detail = true, conn.RemoteAddr().String()
+			conn.Close()
+		}
+	}
+
+	// Any answer below 500 shows that DNS, the route, TLS and proxies let
+	// the tool through; a 5xx means the map itself is down
+	if !connect.OK {
+		request.Detail = "skipped, no connection"
+	} else if req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL+"/api/health", nil); err != nil {
+		request.Detail = err.Error()
+	} else if resp, err := httpClient.Do(req); err != nil {
+		request.Detail = err.Error()
+	} else {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		resp.Body.Close()
+		request.OK = resp.StatusCode < http.StatusInternalServerError
+		request.Detail = "HTTP " + resp.Status
+	}
+
+	return append(checks, resolve, connect, request)
+}
+
+// doctorExitCode picks the exit code for the checks: the platform lacking a
+// check method first, then the map being out of reach, then anything else
+func doctorExitCode(checks []DoctorCheck, support SupportReport) int {
+	if !support.Supported {
+		return exitUnsupported
+	}
+	code := 0
+	for _, c := range checks {
+		switch {
+		case c.OK:
+		case c.network:
+			return exitAPIUnreachable
+		default:
+			code = 1
+		}
+	}
+	return code
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnvironmentChecks(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/health" {
+			t.Errorf("requested %s", r.URL.Path)
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	checks := environmentChecks(ctx, srv.URL, dir)
+	if len(checks) != 4 {
+		t.Fatalf("got %d checks: %+v", len(checks), checks)
+	}
+	for _, c := range checks {
+		if !c.OK {
+			t.Errorf("%s failed: %s", c.Name, c.Detail)
+		}
+	}
+	if code := doctorExitCode(checks, SupportReport{Supported: true}); code != 0 {
+		t.Errorf("all passed: exit %d", code)
+	}
+	if code := doctorExitCode(checks, SupportReport{}); code != exitUnsupported {
+		t.Errorf("unsupported platform: exit %d", code)
+	}
+
+	// A map that is down fails the request, a missing directory its check
+	status = http.StatusServiceUnavailable
+	checks = environmentChecks(ctx, srv.URL, filepath.Join(dir, "missing"))
+	if checks[0].OK || checks[3].OK {
+		t.Errorf("want data directory and request failed: %+v", checks)
+	}
+	if code := doctorExitCode(checks, SupportReport{Supported: true}); code != exitAPIUnreachable {
+		t.Errorf("map down: exit %d", code)
+	}
+	if code := doctorExitCode(checks[:1], SupportReport{Supported: true}); code != 1 {
+		t.Errorf("missing directory: exit %d", code)
+	}
+
+	// Nothing listening: the request is skipped
+	srv.Close()
+	checks = environmentChecks(ctx, srv.URL, dir)
+	if checks[2].OK || checks[3].Detail != "skipped, no connection" {
+		t.Errorf("closed server: %+v", checks[2:])
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/atlasp2p/verify/internal/flags"
+)
+
+// runEmitCurl prints the init/confirm requests as curl commands, for hosts
+// where this binary cannot run. Payloads come from the same builders the
+// normal flow uses; the local checks are marked "manual" since the operator
+// performs them by hand.
+func runEmitCurl(args []string) {
+	fs := flag.NewFlagSet("emit-curl", flag.ExitOnError)
+	port := defaultPort
+	fs.Var(&port, "port", "P2P port the node listens on")
+	maxTime := flags.Duration(30 * time.Second)
+	fs.Var(&maxTime, "max-time", "Timeout for each printed curl command")
+	fs.Usage = commandUsage("emit-curl")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	challenge := fs.Arg(0)
+	if !isValidChallenge(challenge) {
+		log.Fatal("❌ Invalid challenge format. Must be alphanumeric, 20-128 characters.")
+	}
+
+	daemon := primaryDaemon()
+
+	initReq := buildInitRequest(challenge)
+	initReq.Hostname = ""
+
+	confirmReq := buildConfirmRequest(challenge, true, "manual", daemon, true, "manual", int(port))
+	confirmReq.SystemInfo = SystemInfo{}
+
+	fmt.Println("# 1. Make sure the daemon is running and the port is listening:")
+	fmt.Printf("#      pidof %s && (ss -lnt || netstat -an) | grep ':%d'\n", daemon, port)
+	fmt.Println("#    Only continue if both commands show output.")
+	fmt.Println()
+	fmt.Println("# 2. Start the verification (returns your node's IP and port):")
+	fmt.Println(curlCommand(ApiUrl+"/api/verify-node/init", initReq, time.Duration(maxTime)))
+	fmt.Println()
+	fmt.Println("# 3. Submit the results (must run from the same IP as step 2):")
+	fmt.Println(curlCommand(ApiUrl+"/api/verify-node/confirm", confirmReq, time.Duration(maxTime)))
+}
+
+// curlCommand renders a JSON POST as a copy-pasteable curl command. IPv4 is
+// forced for the same reason as the HTTP client (see dialAPI); curl gives
+// up after maxTime (whole seconds, at least one).
+func curlCommand(url string, payload interface{}, maxTime time.Duration) string {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		log.Fatalf("❌ Failed to marshal request: %v", err)
+	}
+	body := strings.ReplaceAll(string(jsonData), "'", `'\''`)
+	seconds := int(math.Ceil(maxTime.Seconds()))
+	return fmt.Sprintf("curl -4 -sS -m %d -X POST '%s' \\\n  -H 'Content-Type: application/json' \\\n  -d '%s'", seconds, url, body)
+}
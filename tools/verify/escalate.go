@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EscalationStep is one piece of extra evidence gathered when the process
+// and port checks disagree. Steps are submitted in the order they ran.
+type EscalationStep struct {
+	Method string `json:"method"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// escalateChecks collects deeper evidence about the daemon and its port:
+// the daemon config, a direct TCP dial, the daemon's own RPC view, and
+// which process owns the socket
+func escalateChecks(port int) []EscalationStep {
+	var steps []EscalationStep
+
+	// The config may explain a closed port (listen=0 or a different port)
+	confPath := daemonConfPath()
+	if conf, err := readDaemonConf(confPath); err != nil {
+		steps = append(steps, EscalationStep{Method: "conf", Detail: err.Error()})
+	} else {
+		step := EscalationStep{Method: "conf", Passed: true, Detail: confPath}
+		if conf["listen"] == "0" {
+			step.Passed = false
+			step.Detail = "listen=0 disables inbound connections"
+		} else if p, ok := conf["port"]; ok && p != strconv.Itoa(port) {
+			step.Passed = false
+			step.Detail = fmt.Sprintf("port=%s does not match node port %d", p, port)
+		}
+		steps = append(steps, step)
+	}
+
+	// Connect directly, in case the port tools are missing or misparsed
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	if conn, err := net.DialTimeout("tcp", addr, 3*time.Second); err != nil {
+		steps = append(steps, EscalationStep{Method: "dial", Detail: err.Error()})
+	} else {
+		conn.Close()
+		steps = append(steps, EscalationStep{Method: "dial", Passed: true, Detail: addr})
+	}
+
+	// Ask the daemon itself: a live RPC proves it runs, and its network
+	// info shows whether it has networking enabled and peers connected
+	steps = append(steps, rpcEscalationStep())
+
+	// Find who owns the listening socket (Linux only)
+	if pid, comm, ok := listeningSocketOwner(port); ok {
+		steps = append(steps, EscalationStep{
+			Method: "socket-pid",
+			Passed: isDaemonName(comm),
+			Detail: fmt.Sprintf("port %d owned by %s (PID %d)", port, comm, pid),
+		})
+	}
+
+	return steps
+}
+
+// rpcEscalationStep queries getnetworkinfo and getblockchaininfo
+func rpcEscalationStep() EscalationStep {
+	var netInfo NetworkInfo
+	if err := rpcCall("getnetworkinfo", &netInfo); err != nil {
+		return EscalationStep{Method: "rpc", Detail: err.Error()}
+	}
+	var chainInfo BlockchainInfo
+	if err := rpcCall("getblockchaininfo", &chainInfo); err != nil {
+		return EscalationStep{Method: "rpc", Detail: err.Error()}
+	}
+
+	step := EscalationStep{
+		Method: "rpc",
+		Passed: true,
+		Detail: fmt.Sprintf("%s on %s at height %d, %d peers", netInfo.Subversion, chainInfo.Chain, chainInfo.Blocks, netInfo.Connections),
+	}
+	if netInfo.NetworkActive != nil && !*netInfo.NetworkActive {
+		step.Passed = false
+		step.Detail += ", networking disabled (setnetworkactive false)"
+	}
+	return step
+}
+
+func printEscalation(steps []EscalationStep) {
+	for _, step := range steps {
+		icon := "❌"
+		if step.Passed {
+			icon = "✅"
+		}
+		fmt.Printf("    %s %s: %s\n", icon, step.Method, step.Detail)
+	}
+}
+
+// readDaemonConf parses a Bitcoin-style key=value config file for mainnet.
+// Keys under a [test] or [regtest] section only apply to that network and
+// are skipped; top-level and [main] keys are kept, later ones winning.
+func readDaemonConf(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	conf := make(map[string]string)
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[] ")
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") || (section != "" && section != "main") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		conf[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return conf, scanner.Err()
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadDaemonConf(t *testing.T) {
+	tests := []struct {
+		name string
+		conf string
+		key  string
+		want string
+	}{
+		{"top level", "rpcport=22555\n", "rpcport", "22555"},
+		{"spaces and comments", "# rpcport=1\n  rpcport = 22555 \n", "rpcport", "22555"},
+		{"later key wins", "rpcport=1\nrpcport=2\n", "rpcport", "2"},
+		{"main section applies", "[main]\nrpcport=22555\n", "rpcport", "22555"},
+		{"test section skipped", "rpcport=22555\n[test]\nrpcport=44555\n", "rpcport", "22555"},
+		{"regtest section skipped", "[regtest]\nrpcuser=reg\n", "rpcuser", ""},
+		{"main after test", "[test]\nrpcport=44555\n[main]\nrpcport=22555\n", "rpcport", "22555"},
+		{"chain selector at top", "testnet=1\n[test]\nrpcport=44555\n", "testnet", "1"},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "daemon.conf")
+		if err := os.WriteFile(path, []byte(tt.conf), 0600); err != nil {
+			t.Fatal(err)
+		}
+		conf, err := readDaemonConf(path)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := conf[tt.key]; got != tt.want {
+			t.Errorf("%s: %s = %q, want %q", tt.name, tt.key, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Retention for what the tool leaves behind on the node
+const (
+	// --uacomment backs up the daemon config on every run
+	gcKeepConfigBackups = 3
+	// writeFileAtomic temp files this old belong to a crashed run
+	gcTempFileAge = time.Hour
+)
+
+// gcItem is one piece of stale state, a file or a baseline entry
+type gcItem struct {
+	Path   string
+	Reason string
+	// Baseline entries live in baseline.json and are not files
+	baseline bool
+}
+
+// staleConfigBackups lists the daemon config backups made by --uacomment
+// beyond the newest keep. Their names end in a sortable timestamp.
+func staleConfigBackups(confPath string, keep int) []string {
+	matches, _ := filepath.Glob(confPath + ".bak-*")
+	if len(matches) <= keep {
+		return nil
+	}
+	sort.Strings(matches)
+	return matches[:len(matches)-keep]
+}
+
+// staleTempFiles lists temp files of writeFileAtomic in dir that are older
+// than gcTempFileAge, left by a run that was killed mid-write
+func staleTempFiles(dir string, now time.Time) []string {
+	matches, _ := filepath.Glob(filepath.Join(dir, ".*.tmp-*"))
+	var stale []string
+	for _, m := range matches {
+		if info, err := os.Stat(m); err == nil && info.Mode().IsRegular() && now.Sub(info.ModTime()) > gcTempFileAge {
+			stale = append(stale, m)
+		}
+	}
+	return stale
+}
+
+// staleBaselines lists the performance baselines of data directories that
+// no longer exist
+func staleBaselines(baselines map[string]PerfMetrics) []string {
+	var stale []string
+	for dir := range baselines {
+		if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+			stale = append(stale, dir)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// findGarbage lists the stale state of the data directory in confPath's
+// directory and of the tool's config directory
+func findGarbage(confPath string, keep int, now time.Time) ([]gcItem, error) {
+	var items []gcItem
+	for _, path := range staleConfigBackups(confPath, keep) {
+		items = append(items, gcItem{Path: path, Reason: fmt.Sprintf("config backup beyond the newest %d", keep)})
+	}
+	dirs := []string{filepath.Dir(confPath)}
+	if dir := configDir(); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	if dir := stateDir(); dir != "" && dir != configDir() {
+		dirs = append(dirs, dir)
+	}
+	for _, dir := range dirs {
+		for _, path := range staleTempFiles(dir, now) {
+			items = append(items, gcItem{Path: path, Reason: "temp file of an interrupted run"})
+		}
+	}
+
+	if path := perfBaselinePath(); path != "" {
+		baselines, err := loadBaselines(path)
+		if err != nil {
+			return items, err
+		}
+		for _, dir := range staleBaselines(baselines) {
+			items = append(items, gcItem{Path: dir, Reason: "baseline of a removed data directory", baseline: true})
+		}
+	}
+	return items, nil
+}
+
+// removeGarbage deletes the items, returning the first error
+func removeGarbage(items []gcItem) error {
+	var dropped []string
+	var firstErr error
+	for _, item := range items {
+		if item.baseline {
+			dropped = append(dropped, item.Path)
+			continue
+		}
+		if err := os.Remove(item.Path); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if len(dropped) == 0 {
+		return firstErr
+	}
+
+	path := perfBaselinePath()
+	baselines, err := loadBaselines(path)
+	if err != nil {
+		return err
+	}
+	for _, dir := range dropped {
+		delete(baselines, dir)
+	}
+	if err := saveBaselines(path, baselines); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
+
+// runGC removes state the tool left behind: old daemon config backups,
+// temp files of interrupted runs and baselines of removed data directories
+func runGC(args []string) {
+	fs := flag.NewFlagSet("gc", flag.ExitOnError)
+	keep := fs.Int("keep", gcKeepConfigBackups, "Daemon config backups to keep")
+	dryRun := fs.Bool("dry-run", false, "List what would be removed, remove nothing")
+	fs.StringVar(&dataDir, "datadir", defaultDataDir(), "Daemon data directory (config backups)")
+	fs.StringVar(&stateDirFlag, "state-dir", "", "State directory used by verify --state-dir")
+	fs.Usage = commandUsage("gc")
+	fs.Parse(args)
+
+	if *keep < 1 {
+		fmt.Println("❌ Invalid --keep: keep at least 1 backup")
+		os.Exit(1)
+	}
+
+	items, err := findGarbage(daemonConfPath(), *keep, time.Now())
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+	if len(items) == 0 {
+		fmt.Println("✅ Nothing to clean up")
+		return
+	}
+
+	verb := "Would remove"
+	var removeErr error
+	if !*dryRun {
+		verb = "Removed"
+		removeErr = removeGarbage(items)
+	}
+	for _, item := range items {
+		fmt.Printf("  %s %s (%s)\n", verb, item.Path, item.Reason)
+	}
+	if removeErr != nil {
+		fmt.Printf("❌ %v\n", removeErr)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStaleConfigBackups(t *testing.T) {
+	dir := t.TempDir()
+	conf := filepath.Join(dir, "dingocoin.conf")
+	for _, stamp := range []string{"20260101-120000", "20260301-120000", "20260201-120000", "20260401-120000"} {
+		os.WriteFile(conf+".bak-"+stamp, nil, 0o600)
+	}
+
+	got := staleConfigBackups(conf, 2)
+	want := []string{conf + ".bak-20260101-120000", conf + ".bak-20260201-120000"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stale = %v, want %v", got, want)
+	}
+	if got := staleConfigBackups(conf, 4); got != nil {
+		t.Errorf("keep 4: stale = %v, want none", got)
+	}
+}
+
+func TestStaleTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	old := filepath.Join(dir, ".dingocoin.conf.tmp-123")
+	fresh := filepath.Join(dir, ".dingocoin.conf.tmp-456")
+	for _, path := range []string{old, fresh, filepath.Join(dir, "dingocoin.conf")} {
+		os.WriteFile(path, nil, 0o600)
+	}
+	os.Chtimes(old, now.Add(-2*time.Hour), now.Add(-2*time.Hour))
+
+	if got := staleTempFiles(dir, now); !reflect.DeepEqual(got, []string{old}) {
+		t.Errorf("stale = %v, want [%s]", got, old)
+	}
+}
+
+func TestGarbageCollection(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	conf := filepath.Join(dir, "dingocoin.conf")
+	for _, stamp := range []string{"20260101-120000", "20260201-120000"} {
+		os.WriteFile(conf+".bak-"+stamp, nil, 0o600)
+	}
+	gone := filepath.Join(dir, "removed-node")
+	baselines := map[string]PerfMetrics{
+		dir:  {RPCLatencyMs: 1},
+		gone: {RPCLatencyMs: 2},
+	}
+	if err := saveBaselines(perfBaselinePath(), baselines); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := findGarbage(conf, 1, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, item := range items {
+		paths = append(paths, item.Path)
+	}
+	want := []string{conf + ".bak-20260101-120000", gone}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("garbage = %v, want %v", paths, want)
+	}
+
+	if err := removeGarbage(items); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(conf + ".bak-20260101-120000"); !os.IsNotExist(err) {
+		t.Errorf("old backup still there: %v", err)
+	}
+	if _, err := os.Stat(conf + ".bak-20260201-120000"); err != nil {
+		t.Errorf("newest backup removed: %v", err)
+	}
+	left, err := loadBaselines(perfBaselinePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := left[gone]; ok || len(left) != 1 {
+		t.Errorf("baselines after gc = %v", left)
+	}
+}
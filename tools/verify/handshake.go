@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// APICapabilities is what a backend supports, from
+// /api/verify-node/capabilities
+type APICapabilities struct {
+	APIVersion       int             `json:"apiVersion"`
+	Chain            string          `json:"chain"`
+	MinClientVersion string          `json:"minClientVersion"`
+	Endpoints        []string        `json:"endpoints"`
+	Features         map[string]bool `json:"features"`
+}
+
+// verifyAPIVersion is the API version this build speaks
+const verifyAPIVersion = 1
+
+// clientEndpoints are the backend routes this build calls
+var clientEndpoints = []string{
+	"/api/verify-node/init",
+	"/api/verify-node/confirm",
+	"/api/verify-node/confirm/[token]",
+	"/api/verify-node/conversation",
+	"/api/verify-node/pair",
+	"/api/verify-node/pair/[token]",
+	"/api/blocklist",
+	"/api/nodes/[id]/badges",
+	"/api/nodes/[id]/notes",
+	"/api/nodes/[id]/retire",
+	"/api/nodes/[id]/uptime",
+}
+
+// compareVersions compares dotted numeric versions such as 2.0.0; missing
+// or non-numeric parts count as 0
+func compareVersions(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var na, nb int
+		if i < len(pa) {
+			na, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			nb, _ = strconv.Atoi(pb[i])
+		}
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// handshakeProblems lists what keeps this build from working with a
+// backend that has caps; none means compatible
+func handshakeProblems(caps APICapabilities) []string {
+	var problems []string
+	if caps.APIVersion != verifyAPIVersion {
+		problems = append(problems, fmt.Sprintf("the backend speaks API version %d, this build version %d", caps.APIVersion, verifyAPIVersion))
+	}
+	if caps.MinClientVersion != "" && compareVersions(Version, caps.MinClientVersion) < 0 {
+		problems = append(problems, fmt.Sprintf("the backend needs verify %s or newer, this is %s", caps.MinClientVersion, Version))
+	}
+	if !strings.EqualFold(caps.Chain, ChainName) {
+		problems = append(problems, fmt.Sprintf("the backend maps %s nodes, this build verifies %s nodes", caps.Chain, ChainName))
+	}
+	for _, endpoint := range clientEndpoints {
+		if !slices.Contains(caps.Endpoints, endpoint) {
+			problems = append(problems, "the backend lacks "+endpoint)
+		}
+	}
+	return problems
+}
+
+// runHandshake fetches the backend's capabilities and checks this build
+// against them, for fork maintainers standing up a new backend
+func runHandshake(args []string) {
+	fs := flag.NewFlagSet("handshake", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Print the capabilities and problems as JSON")
+	fs.Usage = commandUsage("handshake")
+	fs.Parse(args)
+
+	var caps APICapabilities
+	if err := nodeAPIRequest(http.MethodGet, "/api/verify-node/capabilities", "", nil, &caps); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound {
+			fatal(1, "❌ %s has no capabilities endpoint; the backend predates verify handshake", ApiUrl)
+		}
+		fatal(exitAPIUnreachable, "❌ Failed to fetch the capabilities: %v", err)
+	}
+	problems := handshakeProblems(caps)
+
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(struct {
+			APICapabilities
+			Compatible bool     `json:"compatible"`
+			Problems   []string `json:"problems,omitempty"`
+		}{caps, len(problems) == 0, problems})
+	} else {
+		fmt.Printf("Backend: %s\n", ApiUrl)
+		fmt.Printf("  API version: %d (this build: %d)\n", caps.APIVersion, verifyAPIVersion)
+		fmt.Printf("  Chain: %s\n", caps.Chain)
+		fmt.Printf("  Minimum client: %s (this build: %s)\n", caps.MinClientVersion, Version)
+		fmt.Printf("  Endpoints: %d\n", len(caps.Endpoints))
+		features := make([]string, 0, len(caps.Features))
+		for name := range caps.Features {
+			features = append(features, name)
+		}
+		sort.Strings(features)
+		fmt.Println("Features:")
+		for _, name := range features {
+			fmt.Printf("  %s %s\n", yesNo(caps.Features[name]), name)
+		}
+		fmt.Println()
+		if len(problems) == 0 {
+			fmt.Println("✅ This build is compatible with the backend")
+		}
+		for _, p := range problems {
+			fmt.Printf("❌ %s\n", p)
+		}
+	}
+	if len(problems) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"2.0.0", "2.0.0", 0},
+		{"2.0", "2.0.0", 0},
+		{"2.0.1", "2.0.0", 1},
+		{"2.10.0", "2.9.0", 1},
+		{"1.9.9", "2.0.0", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestHandshakeProblems(t *testing.T) {
+	defer func(chain string) { ChainName = chain }(ChainName)
+	ChainName = "Dingocoin"
+
+	caps := APICapabilities{
+		APIVersion:       verifyAPIVersion,
+		Chain:            "dingocoin",
+		MinClientVersion: Version,
+		Endpoints:        clientEndpoints,
+	}
+	if problems := handshakeProblems(caps); len(problems) != 0 {
+		t.Errorf("compatible backend: %q", problems)
+	}
+
+	caps.MinClientVersion = "99.0.0"
+	caps.Chain = "Dogecoin"
+	caps.Endpoints = clientEndpoints[1:]
+	want := []string{
+		"the backend needs verify 99.0.0 or newer, this is " + Version,
+		"the backend maps Dogecoin nodes, this build verifies Dingocoin nodes",
+		"the backend lacks /api/verify-node/init",
+	}
+	if problems := handshakeProblems(caps); !reflect.DeepEqual(problems, want) {
+		t.Errorf("got %q, want %q", problems, want)
+	}
+}
@@ -0,0 +1,489 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// commandHelp documents one command. Help text and --help-json are both
+// generated from it, so the website and the terminal never disagree.
+// Usage lines and examples write the binary as {bin}.
+type commandHelp struct {
+	Name        string        `json:"name"`
+	Usage       []string      `json:"usage"`
+	Summary     string        `json:"summary"`
+	Description []string      `json:"description,omitempty"`
+	Flags       []flagHelp    `json:"flags,omitempty"`
+	Examples    []exampleHelp `json:"examples,omitempty"`
+	ExitCodes   []exitHelp    `json:"exitCodes"`
+	Privileges  string        `json:"privileges"`
+	// Hidden commands work but are left out of the overview
+	Hidden bool `json:"-"`
+}
+
+type flagHelp struct {
+	Name  string `json:"name"`
+	Arg   string `json:"arg,omitempty"`
+	Usage string `json:"usage"`
+}
+
+type exampleHelp struct {
+	Command string `json:"command"`
+	Explain string `json:"explain,omitempty"`
+}
+
+type exitHelp struct {
+	Code    int    `json:"code"`
+	Meaning string `json:"meaning"`
+}
+
+// exitMeanings describe the exit codes shared by several commands
+var exitMeanings = map[int]string{
+	1:                     "Other failure",
+	exitDaemonNotFound:    "Daemon process not found",
+	exitPortNotListening:  "Node port not listening",
+	exitAPIUnreachable:    "API unreachable or unusable response",
+	exitChallengeRejected: "Challenge not found or malformed",
+	exitChallengeExpired:  "Challenge expired",
+	exitChallengeUsed:     "Challenge already used",
+	exitChainMismatch:     "Daemon runs on a different chain (e.g. testnet)",
+	exitUnsupported:       "Platform lacks a method for a required check (see doctor)",
+}
+
+// exits lists the exit codes of a command, 0 meaning success and 1 any
+// other failure
+func exits(success string, codes ...int) []exitHelp {
+	list := []exitHelp{{0, success}, {1, exitMeanings[1]}}
+	for _, code := range codes {
+		list = append(list, exitHelp{code, exitMeanings[code]})
+	}
+	return list
+}
+
+const (
+	privilegesNodeUser = "None. Run it as the user that runs the node, or as root when the node runs as another user (see doctor)."
+	privilegesRPC      = "Read access to the daemon's config or .cookie in the data directory, for RPC."
+	privilegesAPIKey   = "An API key with the write:nodes scope in --api-key or $" + apiKeyEnv + "."
+)
+
+var (
+	flagDatadir  = flagHelp{"datadir", "path", "Daemon data directory (default: OS-specific)"}
+	flagRPCAddr  = flagHelp{"rpc-addr", "h:p", "Daemon RPC address (default: 127.0.0.1:<rpcport>)"}
+	flagStateDir = flagHelp{"state-dir", "path", "Keep state here (default: config dir, XDG_RUNTIME_DIR when read-only)"}
+	flagAPIKey   = flagHelp{"api-key", "key", "API key with the write:nodes scope (default: $" + apiKeyEnv + ")"}
+	flagJSON     = flagHelp{"json", "", "Print the result as JSON"}
+
+	// Accepted by every command
+	flagConfig  = flagHelp{"config", "path", "Override the built-in API URL, daemon names, port or chain"}
+	flagDaemon  = flagHelp{"daemon-name", "name", "Look for this daemon instead: a name, a glob like dingocoind-* or re:<regexp>; repeatable"}
+	flagVerbose = flagHelp{"verbose", "", "Log API requests, RPC calls and commands to stderr (any command)"}
+	flagDebug   = flagHelp{"debug", "", "Like --verbose, plus bodies and outputs (the challenge is redacted)"}
+	flagLang    = flagHelp{"lang", "code", "Language of the verification messages: en, es, pt, zh (default: $LANG)"}
+	flagNoColor = flagHelp{"no-color", "", "No colors, emoji or box drawing (also when $NO_COLOR is set)"}
+	flagASCII   = flagHelp{"ascii", "", "Like --no-color, and print nothing but ASCII"}
+)
+
+// commands documents every command, the verification first
+var commands = []commandHelp{
+	{
+		Name:    "verify",
+		Usage:   []string{"{bin} verify [options] <challenge-token>", "{bin} verify [options] --challenge-file <path>", "{bin} verify [options] <challenge-token> <challenge-token>...", "{bin} verify [options] --challenges-file <path>", "{bin} [options] <challenge-token>"},
+		Summary: "Prove that you run the node you added on the map",
+		Description: []string{
+			"Checks that the node daemon is running and its port is listening, and",
+			"submits the results from the node's IP address.",
+			"Started without a challenge in a terminal, it shows a short code to enter",
+			"in the website's verification dialog, picks up the challenge from there",
+			"and guides you.",
+			"Given several challenges it verifies them one after another and prints a",
+			"summary; it exits with the code of the first one that failed.",
+		},
+		Flags: []flagHelp{
+			flagConfig,
+			flagDaemon,
+			{"challenge-file", "path", "Read the challenge from a file, or stdin with -, not the command line"},
+			{"challenges-file", "path", "Verify every challenge in a file (one per line, # comments), or stdin with -"},
+			{"uacomment", "", "Also prove ownership via a token in the daemon's user agent"},
+			{"reachability-proof", "", "Accept an inbound probe from the map on a port it picks"},
+			{"port", "port", "Check this local P2P port, not the one the map knows (e.g. behind port forwarding)"},
+			flagDatadir,
+			{"follow-daemon-log", "", "Show bind errors and \"Bound to\" lines from debug.log"},
+			{"daemon-log", "path", "Path to debug.log (default: <datadir>/debug.log)"},
+			{"log-backlog", "size", "Scan this much of debug.log first (default: 64K)"},
+			flagRPCAddr,
+			{"report-template", "f", "Render a Go template with the final result"},
+			{"report-output", "f", "Write the rendered report to a file"},
+			{"force-ipv4", "", "Only reach the API over IPv4"},
+			{"force-ipv6", "", "Only reach the API over IPv6 (IPv6-only nodes)"},
+			{"no-provider", "", "Don't report the hosting provider (aws, hetzner, ...) or the daemon's container"},
+			{"share-disk", "", "Report free disk space of the data directory (opt-in)"},
+			{"check-script", "name", "Run a script from the checks.d directory, submit its JSON"},
+			{"check-timeout", "d", "Time limit of the process and port checks, and time and CPU limit per check script (default: 10s)"},
+			{"api-timeout", "d", "Time limit per request to the map's API (default: 30s)"},
+			flagStateDir,
+			{"reset-baseline", "", "Record a new performance baseline (e.g. after a hardware change)"},
+			{"dry-run", "", "Run the checks, print the payload instead of submitting it"},
+			{"encrypt-payload", "", "Encrypt the results to the map's key (TLS-terminating proxies)"},
+			{"strict", "", "Only pass with RPC, signmessage or P2P handshake proofs"},
+			{"sign-address", "addr", "With --strict, sign the challenge with this wallet address"},
+			{"journal", "", "Log the outcome to journald (journalctl -t nodesmap-verify)"},
+			{"quiet", "", "No output; the exit code tells the outcome"},
+			{"json", "", "Print the result as JSON on stdout (messages go to stderr)"},
+			flagLang,
+			flagNoColor,
+			flagASCII,
+			flagVerbose,
+			flagDebug,
+		},
+		Examples: []exampleHelp{
+			{"{bin}", "Pair with the website by code instead of copying the challenge"},
+			{"{bin} verify abc123xyz456def789ghi0", "Verify with the challenge from the website"},
+			{"{bin} verify --dry-run abc123xyz456def789ghi0", "Show what would be submitted, submit nothing"},
+			{"pass show nodes-map/challenge | {bin} verify -", "Read the challenge from a password manager, keeping it out of shell history"},
+			{"{bin} verify --quiet abc123xyz456def789ghi0 || echo \"failed: $?\"", "Unattended run, outcome in the exit code"},
+			{"{bin} verify --challenges-file nodes.txt --json > results.json", "Verify several nodes from one host, all results in one JSON array"},
+			{"{bin} verify --strict --sign-address <address> abc123xyz456def789ghi0", "Strict verification for the higher trust tier"},
+		},
+		ExitCodes:  exits("Verification submitted (or --dry-run checks passed)", exitDaemonNotFound, exitPortNotListening, exitAPIUnreachable, exitChallengeRejected, exitChallengeExpired, exitChallengeUsed, exitChainMismatch, exitUnsupported),
+		Privileges: privilegesNodeUser + " --journal needs access to the journald socket.",
+	},
+	{
+		Name:        "status",
+		Usage:       []string{"{bin} status [--json] <challenge-token>"},
+		Summary:     "Show where a verification stands",
+		Description: []string{"Shows the current state of the verification started with the challenge."},
+		Flags:       []flagHelp{flagJSON},
+		Examples:    []exampleHelp{{"{bin} status abc123xyz456def789ghi0", ""}},
+		ExitCodes:   exits("Status shown", exitAPIUnreachable, exitChallengeRejected, exitChallengeExpired, exitChallengeUsed),
+		Privileges:  "None.",
+	},
+	{
+		Name:    "recheck",
+		Usage:   []string{"{bin} recheck [options]"},
+		Summary: "Re-run the process and port checks, submit nothing",
+		Description: []string{
+			"Runs the daemon process and port checks again without contacting the map,",
+			"e.g. after fixing what made a verification fail.",
+		},
+		Flags: []flagHelp{
+			{"port", "port", "Node P2P port to check (default: the chain's port)"},
+			flagDatadir,
+			{"check-timeout", "d", "Time limit of the process check and of the port check (default: 10s)"},
+		},
+		Examples:   []exampleHelp{{"{bin} recheck --port 33117", ""}},
+		ExitCodes:  exits("Both checks pass", exitDaemonNotFound, exitPortNotListening),
+		Privileges: privilegesNodeUser,
+	},
+	{
+		Name:    "preflight",
+		Usage:   []string{"{bin} preflight [options]"},
+		Summary: "Check that the node would pass, before requesting a challenge",
+		Description: []string{
+			"Runs the process and port checks, checks that the port accepts connections",
+			"on more than localhost, and asks the daemon over RPC for its chain, tip",
+			"and inbound peers. Nothing is sent to the map, so no challenge is needed.",
+			"Problems the verification survives (no RPC, no inbound peers yet) only warn.",
+		},
+		Flags: []flagHelp{
+			{"port", "port", "Node P2P port to check (default: the chain's port)"},
+			flagDatadir,
+			flagRPCAddr,
+			{"check-timeout", "d", "Time limit of each local check (default: 10s)"},
+			{"json", "", "Print the checks as JSON on stdout (messages go to stderr)"},
+		},
+		ExitCodes:  exits("The node should pass", exitDaemonNotFound, exitPortNotListening, exitChainMismatch),
+		Privileges: privilegesNodeUser + " " + privilegesRPC,
+	},
+	{
+		Name:       "version",
+		Usage:      []string{"{bin} version [--json]"},
+		Summary:    "Show the version and built-in settings",
+		Flags:      []flagHelp{flagJSON},
+		ExitCodes:  exits("Version shown"),
+		Privileges: "None.",
+	},
+	{
+		Name:        "badge",
+		Usage:       []string{"{bin} badge [options] <node-id>"},
+		Summary:     "Render an SVG uptime badge for a node",
+		Description: []string{"The node ID is the last part of your node's map URL (/node/<id>)."},
+		Flags: []flagHelp{
+			{"window", "days", "Availability window, e.g. 90d (default 30d)"},
+			{"o", "file", "Write the SVG to a file instead of stdout"},
+		},
+		Examples:   []exampleHelp{{"{bin} badge --window 90d -o uptime.svg <node-id>", ""}},
+		ExitCodes:  exits("Badge written"),
+		Privileges: "None.",
+	},
+	{
+		Name:    "emit-curl",
+		Usage:   []string{"{bin} emit-curl [--port <port>] [--max-time <duration>] <challenge-token>"},
+		Summary: "Print curl commands to verify manually",
+		Description: []string{
+			"Prints curl commands that perform the verification manually.",
+			"Run the printed commands ON YOUR NODE SERVER, in order.",
+		},
+		Flags: []flagHelp{
+			{"port", "port", "P2P port the node listens on"},
+			{"max-time", "d", "Timeout for each printed curl command (default: 30s)"},
+		},
+		ExitCodes:  exits("Commands printed"),
+		Privileges: "None.",
+	},
+	{
+		Name:        "attach-badge",
+		Usage:       []string{"{bin} attach-badge [options] <node-id> <badge-token>"},
+		Summary:     "Attach an admin-issued badge token",
+		Description: []string{"Badge tokens are issued by the map admins to sponsors and community members."},
+		Flags:       []flagHelp{flagAPIKey},
+		ExitCodes:   exits("Badge attached"),
+		Privileges:  privilegesAPIKey,
+	},
+	{
+		Name:    "retire",
+		Usage:   []string{"{bin} retire --at <YYYY-MM-DD> <node-id>", "{bin} retire --cancel <node-id>"},
+		Summary: "Announce a planned shutdown of a node",
+		Flags: []flagHelp{
+			{"at", "date", "Planned shutdown date (the node retires at 00:00 UTC)"},
+			{"cancel", "", "Cancel the announced shutdown"},
+			flagAPIKey,
+		},
+		ExitCodes:  exits("Shutdown announced or cancelled"),
+		Privileges: privilegesAPIKey,
+	},
+	{
+		Name:        "questions",
+		Usage:       []string{"{bin} questions [options] <challenge-token>"},
+		Summary:     "Answer admin questions during review",
+		Description: []string{"Shows follow-up questions from the map admins about a submitted", "verification and sends your answers back."},
+		Flags: []flagHelp{
+			{"wait", "d", "Keep polling for new questions this long (default 30m)"},
+			{"interval", "d", "Time between polls (default 60s, minimum 30s)"},
+			{"once", "", "Check once instead of polling"},
+		},
+		ExitCodes:  exits("Questions shown or answered"),
+		Privileges: "None.",
+	},
+	{
+		Name:       "note",
+		Usage:      []string{"{bin} note [options] <node-id> <text>"},
+		Summary:    "Attach an operator note to a node",
+		Flags:      []flagHelp{{"public", "", "Also show the note on the node's map page"}, flagAPIKey},
+		Examples:   []exampleHelp{{"{bin} note --public <node-id> \"migrating disks tonight, back by 02:00 UTC\"", ""}},
+		ExitCodes:  exits("Note saved"),
+		Privileges: privilegesAPIKey,
+	},
+	{
+		Name:    "peers",
+		Usage:   []string{"{bin} peers [options]"},
+		Summary: "Check connected peers against the blocklist",
+		Flags: []flagHelp{
+			{"max-flagged", "pct", "Warn above this share of flagged peers (default: 25)"},
+			{"setban", "", "Print setban commands for the flagged ranges"},
+			{"ban-time", "d", "Ban duration for --setban (default: 1d)"},
+			flagDatadir,
+			flagRPCAddr,
+		},
+		Examples:   []exampleHelp{{"{bin} peers --setban", ""}},
+		ExitCodes:  exits("Peers checked"),
+		Privileges: privilegesRPC,
+	},
+	{
+		Name:    "doctor",
+		Usage:   []string{"{bin} doctor [options]"},
+		Summary: "Show which evidence sources this host allows",
+		Description: []string{
+			"Lists the tools and kernel interfaces the checks can use, and checks that",
+			"the data directory exists and the map resolves, accepts connections and",
+			"answers over HTTPS. Run it before spending a one-time challenge.",
+		},
+		Flags: []flagHelp{
+			{"json", "", "Print the capability set and the checks as JSON"},
+			flagDatadir,
+			flagRPCAddr,
+			{"api-timeout", "d", "Time limit for the checks that reach the map (default: 30s)"},
+		},
+		ExitCodes:  exits("Everything a verification needs is in place", exitAPIUnreachable, exitUnsupported),
+		Privileges: "None; run it as the user you verify with to see what that user can use.",
+	},
+	{
+		Name:    "handshake",
+		Usage:   []string{"{bin} handshake [--json]"},
+		Summary: "Check this build against the map backend's capabilities",
+		Description: []string{
+			"Fetches the backend's API version, minimum client version, endpoints and",
+			"enabled features, and reports anything that keeps this build from",
+			"working with it. Meant for forks standing up a new backend.",
+		},
+		Flags:      []flagHelp{{"json", "", "Print the capabilities and problems as JSON"}},
+		ExitCodes:  exits("Compatible", exitAPIUnreachable),
+		Privileges: "None.",
+	},
+	{
+		Name:    "gc",
+		Usage:   []string{"{bin} gc [options]"},
+		Summary: "Remove state the tool left behind",
+		Description: []string{
+			"Removes daemon config backups made by --uacomment beyond the newest few,",
+			"temp files of interrupted runs, and performance baselines of data",
+			"directories that no longer exist. Verifications keep backups pruned too.",
+		},
+		Flags: []flagHelp{
+			{"keep", "n", "Daemon config backups to keep (default: 3)"},
+			{"dry-run", "", "List what would be removed, remove nothing"},
+			flagDatadir,
+			flagStateDir,
+		},
+		Examples:   []exampleHelp{{"{bin} gc --dry-run", ""}},
+		ExitCodes:  exits("Cleaned up, or nothing to clean"),
+		Privileges: "Write access to the data directory, like --uacomment.",
+	},
+	{
+		Name:    "completion",
+		Usage:   []string{"{bin} completion <bash|zsh|fish>"},
+		Summary: "Print a shell completion script",
+		Description: []string{
+			"Completes commands and their options. Load the script in your shell's",
+			"startup file to keep it.",
+		},
+		Examples: []exampleHelp{
+			{"source <({bin} completion bash)", "Enable completion in the current bash"},
+			{"{bin} completion zsh > \"${fpath[1]}/_verify\"", "Install for zsh"},
+			{"{bin} completion fish > ~/.config/fish/completions/verify.fish", "Install for fish"},
+		},
+		ExitCodes:  exits("Script printed"),
+		Privileges: "None.",
+	},
+	{
+		Name:    "bench-checks",
+		Usage:   []string{"{bin} bench-checks [options]"},
+		Summary: "Time every detection backend on this host",
+		Flags: []flagHelp{
+			{"n", "runs", "Number of runs per backend (default: 5)"},
+			{"port", "port", "Port to probe"},
+			{"dial-timeout", "d", "Timeout for the port/dial backend (default: 3s)"},
+		},
+		ExitCodes:  exits("Benchmark printed"),
+		Privileges: privilegesNodeUser,
+		Hidden:     true,
+	},
+}
+
+func lookupCommand(name string) (commandHelp, bool) {
+	for _, c := range commands {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return commandHelp{}, false
+}
+
+// writeCommandHelp renders a command's help, with bin as the binary name
+func writeCommandHelp(w io.Writer, c commandHelp, bin string) {
+	sub := func(s string) string { return strings.ReplaceAll(s, "{bin}", bin) }
+
+	fmt.Fprintln(w, "Usage:")
+	for _, u := range c.Usage {
+		fmt.Fprintf(w, "  %s\n", sub(u))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, c.Summary+".")
+	for _, line := range c.Description {
+		fmt.Fprintln(w, line)
+	}
+
+	if len(c.Flags) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Options:")
+		names := make([]string, len(c.Flags))
+		width := 0
+		for i, f := range c.Flags {
+			names[i] = "--" + f.Name
+			if len(f.Name) == 1 {
+				names[i] = "-" + f.Name
+			}
+			if f.Arg != "" {
+				names[i] += " <" + f.Arg + ">"
+			}
+			width = max(width, len(names[i]))
+		}
+		for i, f := range c.Flags {
+			fmt.Fprintf(w, "  %s %s\n", padRight(names[i], width), f.Usage)
+		}
+	}
+
+	if len(c.Examples) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Examples:")
+		for _, e := range c.Examples {
+			fmt.Fprintf(w, "  %s\n", sub(e.Command))
+			if e.Explain != "" {
+				fmt.Fprintf(w, "      %s\n", e.Explain)
+			}
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Exit codes:")
+	for _, e := range c.ExitCodes {
+		fmt.Fprintf(w, "  %d  %s\n", e.Code, e.Meaning)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Privileges:")
+	fmt.Fprintf(w, "  %s\n", c.Privileges)
+}
+
+// commandUsage is the flag.FlagSet Usage of a subcommand
+func commandUsage(name string) func() {
+	return func() {
+		c, _ := lookupCommand(name)
+		writeCommandHelp(os.Stdout, c, os.Args[0])
+	}
+}
+
+// writeHelpJSON documents every visible command for the website. Usage
+// lines and examples name the binary "verify".
+func writeHelpJSON(w io.Writer) error {
+	visible := make([]commandHelp, 0, len(commands))
+	for _, c := range commands {
+		if c.Hidden {
+			continue
+		}
+		c.Usage = append([]string(nil), c.Usage...)
+		for i := range c.Usage {
+			c.Usage[i] = strings.ReplaceAll(c.Usage[i], "{bin}", "verify")
+		}
+		c.Examples = append([]exampleHelp(nil), c.Examples...)
+		for i := range c.Examples {
+			c.Examples[i].Command = strings.ReplaceAll(c.Examples[i].Command, "{bin}", "verify")
+		}
+		visible = append(visible, c)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(struct {
+		Version  string        `json:"version"`
+		Chain    string        `json:"chain"`
+		Commands []commandHelp `json:"commands"`
+	}{Version, ChainName, visible})
+}
+
+// runHelp prints the overview, or one command's help
+func runHelp(args []string) {
+	if len(args) == 0 {
+		printUsage()
+		return
+	}
+	c, ok := lookupCommand(args[0])
+	if !ok {
+		fmt.Printf("Unknown command %q\n\n", args[0])
+		printUsage()
+		os.Exit(1)
+	}
+	writeCommandHelp(os.Stdout, c, os.Args[0])
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCommandHelpComplete(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, c := range commands {
+		if seen[c.Name] {
+			t.Errorf("%s documented twice", c.Name)
+		}
+		seen[c.Name] = true
+		if len(c.Usage) == 0 || c.Summary == "" || c.Privileges == "" {
+			t.Errorf("%s: usage, summary and privileges are required", c.Name)
+		}
+		for _, e := range c.ExitCodes {
+			if e.Meaning == "" {
+				t.Errorf("%s: exit code %d has no meaning", c.Name, e.Code)
+			}
+		}
+	}
+
+	// The verification documents every exit code it can produce
+	verify, _ := lookupCommand("verify")
+	documented := make(map[int]bool)
+	for _, e := range verify.ExitCodes {
+		documented[e.Code] = true
+	}
+	for code := range exitMeanings {
+		if !documented[code] {
+			t.Errorf("verify does not document exit code %d", code)
+		}
+	}
+}
+
+func TestWriteCommandHelp(t *testing.T) {
+	c, _ := lookupCommand("badge")
+	var buf bytes.Buffer
+	writeCommandHelp(&buf, c, "./verify")
+	out := buf.String()
+	for _, want := range []string{"./verify badge [options] <node-id>", "  --window <days> Availability", "  -o <file>", "Exit codes:\n  0  Badge written\n  1  Other failure", "Privileges:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("help lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteHelpJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeHelpJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "{bin}") {
+		t.Error("help JSON contains the {bin} placeholder")
+	}
+	var doc struct {
+		Commands []commandHelp `json:"commands"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range doc.Commands {
+		if c.Name == "bench-checks" {
+			t.Error("hidden command in help JSON")
+		}
+	}
+	if len(doc.Commands) == 0 || doc.Commands[0].Name != "verify" || doc.Commands[0].Usage[0] != "verify verify [options] <challenge-token>" {
+		t.Errorf("unexpected first command %+v", doc.Commands[0])
+	}
+	// The table is shared; rendering JSON must not rewrite it
+	if commands[0].Usage[0] != "{bin} verify [options] <challenge-token>" {
+		t.Error("writeHelpJSON modified the command table")
+	}
+}
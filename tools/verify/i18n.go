@@ -0,0 +1,327 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Terminal messages of the verification and the first-run wizard are
+// translated; help, diagnostics and command output for scripts stay
+// English. Catalogs are keyed by the English format string, gettext style,
+// so untranslated messages fall back to the English text.
+
+var supportedLangs = []string{"en", "es", "pt", "zh"}
+
+// lang is the language of the terminal messages, set once at startup
+var lang = "en"
+
+// tr translates an English format string and formats it with args
+func tr(format string, args ...any) string {
+	if translated, ok := catalogs[lang][format]; ok {
+		format = translated
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// splitLangFlag removes --lang from args. Like --config it applies to every
+// command, so it is handled before subcommands.
+func splitLangFlag(args []string) (value string, rest []string, err error) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		name, v, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "lang" {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return "", nil, errors.New("--lang needs a language: " + strings.Join(supportedLangs, ", "))
+			}
+			i++
+			v = args[i]
+		}
+		value = v
+	}
+	return value, rest, nil
+}
+
+// detectLang picks the language from --lang, else from the locale
+// variables in POSIX order. Locales like pt_BR.UTF-8 select their
+// language; unsupported ones fall back to English.
+func detectLang(flagValue string) (string, error) {
+	if flagValue != "" {
+		if code, ok := langCode(flagValue); ok {
+			return code, nil
+		}
+		return "en", fmt.Errorf("unsupported language %q: use %s", flagValue, strings.Join(supportedLangs, ", "))
+	}
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(env); value != "" {
+			code, _ := langCode(value)
+			return code, nil
+		}
+	}
+	return "en", nil
+}
+
+// langCode maps a language or locale name to a supported language
+func langCode(value string) (string, bool) {
+	value = strings.ToLower(value)
+	if i := strings.IndexAny(value, "_-.@"); i >= 0 {
+		value = value[:i]
+	}
+	i := sort.SearchStrings(supportedLangs, value)
+	if i < len(supportedLangs) && supportedLangs[i] == value {
+		return value, true
+	}
+	return "en", false
+}
+
+// isYes accepts yes in the supported languages
+func isYes(answer string) bool {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	for _, prefix := range []string{"y", "s", "是"} {
+		if strings.HasPrefix(answer, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+var catalogs = map[string]map[string]string{
+	"es": {
+		"Starting node verification process...":       "Iniciando la verificación del nodo...",
+		"Step 1/3: Fetching node details from API...": "Paso 1/3: Obteniendo los datos del nodo desde la API...",
+		"  ✅ Node IP: %s\n":                           "  ✅ IP del nodo: %s\n",
+		"  ✅ Node Port: %d\n":                         "  ✅ Puerto del nodo: %d\n",
+		"  ℹ️  Running as a sidecar in pod %s, checked the daemon over the pod network\n": "  ℹ️  Ejecutándose como sidecar en el pod %s; se comprobó el daemon a través de la red del pod\n",
+		"  ℹ️  Installed as %s\n": "  ℹ️  Instalado como %s\n",
+		"  ⚠️  Port %d only listens on %s, which other hosts can't reach; check bind= in %s\n":  "  ⚠️  El puerto %d solo escucha en %s, que otros equipos no pueden alcanzar; revisa bind= en %s\n",
+		"  ✅ RPC port %d is listening (method: %s)\n":                                           "  ✅ El puerto RPC %d está escuchando (método: %s)\n",
+		"  ⚠️  RPC port %d is not listening; the node is reported without RPC\n":                "  ⚠️  El puerto RPC %d no está escuchando; el nodo se informa sin RPC\n",
+		"  ⚠️  Port %d doesn't listen on %s, but the map knows the node as %s\n":                "  ⚠️  El puerto %d no escucha en %s, pero el mapa conoce el nodo como %s\n",
+		"  ⚠️  Port %d belongs to %s (PID %d), not the daemon\n":                                "  ⚠️  El puerto %d pertenece a %s (PID %d), no al daemon\n",
+		"  ℹ️  Managed by systemd unit %s, up %s\n":                                             "  ℹ️  Gestionado por la unidad systemd %s, activo desde hace %s\n",
+		"  ℹ️  Managed by systemd unit %s\n":                                                    "  ℹ️  Gestionado por la unidad systemd %s\n",
+		"  ℹ️  Daemon runs in %s container %s\n":                                                "  ℹ️  El daemon se ejecuta en el contenedor %s %s\n",
+		"  ⚠️  The container doesn't publish port %d on the host; check the -p/ports mapping\n": "  ⚠️  El contenedor no publica el puerto %d en el host; revisa el mapeo -p/ports\n",
+		"━━ Challenge %d of %d ━━\n":                                                            "━━ Desafío %d de %d ━━\n",
+		"Summary: %d of %d verified\n":                                                          "Resumen: %d de %d verificados\n",
+		"Node":                                                                                  "Nodo",
+		"Outcome":                                                                               "Resultado",
+		"Details":                                                                               "Detalles",
+		"  ℹ️  Checking port %d locally instead (--port); the map decides whether it accepts that\n": "  ℹ️  Comprobando el puerto %d localmente (--port); el mapa decide si lo acepta\n",
+		"Step 2/3: Checking local node process and port...":                                          "Paso 2/3: Comprobando el proceso y el puerto del nodo local...",
+		"  ✅ Found daemon: %s (method: %s)\n":                                                        "  ✅ Daemon encontrado: %s (método: %s)\n",
+		"  ❌ No node daemon found. Expected: %s\n":                                                   "  ❌ No se encontró el daemon del nodo. Se esperaba: %s\n",
+		"  ✅ Port %d is listening (method: %s)\n":                                                    "  ✅ El puerto %d está escuchando (método: %s)\n",
+		"  ❌ Port %d is not listening\n":                                                             "  ❌ El puerto %d no está escuchando\n",
+		"Step 3/3: Dry run, not submitting":                                                          "Paso 3/3: Simulación, no se envía nada",
+		"Step 3/3: Submitting verification to API...":                                                "Paso 3/3: Enviando la verificación a la API...",
+		"✅ Verification submitted successfully!":                                                     "✅ ¡Verificación enviada correctamente!",
+		"   Your verification will be reviewed by an admin.":                                         "   Un administrador revisará tu verificación.",
+		"   If the admins have follow-up questions, answer them with:":                               "   Si los administradores tienen preguntas, respóndelas con:",
+		"   Your node on the map: %s\n":                                                              "   Tu nodo en el mapa: %s\n",
+		"   Receipt saved to %s\n":                                                                   "   Recibo guardado en %s\n",
+		"❌ Invalid challenge format. Must be alphanumeric, 20-128 characters.":                       "❌ Formato de desafío no válido. Debe ser alfanumérico, de 20 a 128 caracteres.",
+		"❌ Failed to initialize verification: %v":                                                    "❌ No se pudo iniciar la verificación: %v",
+		"❌ Failed to submit verification: %v":                                                        "❌ No se pudo enviar la verificación: %v",
+		"❌ Challenge not found.":                                                                     "❌ Desafío no encontrado.",
+		"   Check that you copied the full challenge from the website.":                              "   Comprueba que copiaste el desafío completo del sitio web.",
+		"❌ Challenge has expired.":                                                                   "❌ El desafío ha caducado.",
+		"   Challenges are only valid for a limited time. Generate a new one.":                       "   Los desafíos solo son válidos por un tiempo limitado. Genera uno nuevo.",
+		"❌ Challenge can no longer be used.":                                                         "❌ El desafío ya no se puede usar.",
+		"   Start a new verification at: %s\n":                                                       "   Inicia una nueva verificación en: %s\n",
+		"   Open it in your browser now? [y/N] ":                                                     "   ¿Abrirlo ahora en el navegador? [s/N] ",
+
+		"Welcome! This checks that you run the %s node you added on the map.\n":         "¡Bienvenido! Esto comprueba que ejecutas el nodo de %s que añadiste al mapa.\n",
+		"Paste the challenge from the website (or press Enter to quit): ":               "Pega el desafío del sitio web (o pulsa Enter para salir): ",
+		"  That doesn't look like a challenge: it should be 20-128 letters and digits.": "  Eso no parece un desafío: debe tener de 20 a 128 letras y dígitos.",
+		"⚠️  Pairing with the website didn't work: %v\n":                                "⚠️  No se pudo vincular con el sitio web: %v\n",
+		"You can paste the challenge shown on the website instead.":                     "Puedes pegar el desafío que muestra el sitio web.",
+		"What went wrong:": "Qué salió mal:",
+		"Fix this, then press Enter to try again (or type q to quit): ":                          "Corrígelo y pulsa Enter para reintentar (o escribe q para salir): ",
+		"Open %s, start verifying your node with the binary method,\n":                           "Abre %s, inicia la verificación de tu nodo con el método del binario\n",
+		"and enter this code in the verification dialog:":                                        "e introduce este código en el diálogo de verificación:",
+		"Waiting for the code (valid until %s, Ctrl-C to cancel)...\n":                           "Esperando el código (válido hasta las %s, Ctrl-C para cancelar)...\n",
+		"✅ Paired with the website, continuing with its challenge.":                              "✅ Vinculado con el sitio web, continuando con su desafío.",
+		"Your node software doesn't seem to be running on this computer.":                        "El software del nodo no parece estar ejecutándose en este equipo.",
+		"Start it (for example: sudo systemctl start %s) and wait a minute.":                     "Inícialo (por ejemplo: sudo systemctl start %s) y espera un minuto.",
+		"Run this tool on the server where the node runs, not on your own PC.":                   "Ejecuta esta herramienta en el servidor donde corre el nodo, no en tu PC.",
+		"Your node is running but isn't accepting connections on its port (usually %d).":         "Tu nodo está en marcha pero no acepta conexiones en su puerto (normalmente %d).",
+		"Make sure its config has listen=1 and no other port= setting, then restart it.":         "Asegúrate de que su configuración tenga listen=1 y ningún otro port=, y reinícialo.",
+		"A node that has just started can take a minute before it listens.":                      "Un nodo recién iniciado puede tardar un minuto en escuchar.",
+		"The map's server couldn't be reached.":                                                  "No se pudo contactar con el servidor del mapa.",
+		"Check that this computer can open %s (internet access, firewall, proxy).":               "Comprueba que este equipo puede abrir %s (acceso a internet, cortafuegos, proxy).",
+		"The map didn't recognise this challenge.":                                               "El mapa no reconoció este desafío.",
+		"Copy it again from the website; it must be copied in full.":                             "Cópialo de nuevo del sitio web; debe copiarse completo.",
+		"The challenge has expired.":                                                             "El desafío ha caducado.",
+		"Create a new one at %s/my-nodes.":                                                       "Crea uno nuevo en %s/my-nodes.",
+		"This challenge was already used, or the node is already verified.":                      "Este desafío ya se usó, o el nodo ya está verificado.",
+		"Check %s/my-nodes, or create a new challenge there.":                                    "Revisa %s/my-nodes, o crea allí un desafío nuevo.",
+		"The node on this computer runs on a test network, not the main network.":                "El nodo de este equipo funciona en una red de pruebas, no en la red principal.",
+		"Point the tool at your main node's data directory with --datadir.":                      "Indica a la herramienta el directorio de datos de tu nodo principal con --datadir.",
+		"This computer lacks the tools the checks need; the message above says what to install.": "Este equipo no tiene las herramientas que necesitan las comprobaciones; el mensaje de arriba indica qué instalar.",
+		"Something else went wrong; the message above says what.":                                "Algo más falló; el mensaje de arriba lo explica.",
+	},
+	"pt": {
+		"Starting node verification process...":       "Iniciando a verificação do nó...",
+		"Step 1/3: Fetching node details from API...": "Passo 1/3: Obtendo os dados do nó pela API...",
+		"  ✅ Node IP: %s\n":                           "  ✅ IP do nó: %s\n",
+		"  ✅ Node Port: %d\n":                         "  ✅ Porta do nó: %d\n",
+		"  ℹ️  Running as a sidecar in pod %s, checked the daemon over the pod network\n": "  ℹ️  Executando como sidecar no pod %s; o daemon foi verificado pela rede do pod\n",
+		"  ℹ️  Installed as %s\n": "  ℹ️  Instalado como %s\n",
+		"  ⚠️  Port %d only listens on %s, which other hosts can't reach; check bind= in %s\n":  "  ⚠️  A porta %d só escuta em %s, que outros hosts não alcançam; verifique bind= em %s\n",
+		"  ✅ RPC port %d is listening (method: %s)\n":                                           "  ✅ A porta RPC %d está escutando (método: %s)\n",
+		"  ⚠️  RPC port %d is not listening; the node is reported without RPC\n":                "  ⚠️  A porta RPC %d não está escutando; o nó é informado sem RPC\n",
+		"  ⚠️  Port %d doesn't listen on %s, but the map knows the node as %s\n":                "  ⚠️  A porta %d não escuta em %s, mas o mapa conhece o nó como %s\n",
+		"  ⚠️  Port %d belongs to %s (PID %d), not the daemon\n":                                "  ⚠️  A porta %d pertence a %s (PID %d), não ao daemon\n",
+		"  ℹ️  Managed by systemd unit %s, up %s\n":                                             "  ℹ️  Gerenciado pela unidade systemd %s, ativo há %s\n",
+		"  ℹ️  Managed by systemd unit %s\n":                                                    "  ℹ️  Gerenciado pela unidade systemd %s\n",
+		"  ℹ️  Daemon runs in %s container %s\n":                                                "  ℹ️  O daemon roda no contêiner %s %s\n",
+		"  ⚠️  The container doesn't publish port %d on the host; check the -p/ports mapping\n": "  ⚠️  O contêiner não publica a porta %d no host; verifique o mapeamento -p/ports\n",
+		"━━ Challenge %d of %d ━━\n":                                                            "━━ Desafio %d de %d ━━\n",
+		"Summary: %d of %d verified\n":                                                          "Resumo: %d de %d verificados\n",
+		"Node":                                                                                  "Nó",
+		"Outcome":                                                                               "Resultado",
+		"Details":                                                                               "Detalhes",
+		"  ℹ️  Checking port %d locally instead (--port); the map decides whether it accepts that\n": "  ℹ️  Verificando a porta %d localmente (--port); o mapa decide se aceita isso\n",
+		"Step 2/3: Checking local node process and port...":                                          "Passo 2/3: Verificando o processo e a porta do nó local...",
+		"  ✅ Found daemon: %s (method: %s)\n":                                                        "  ✅ Daemon encontrado: %s (método: %s)\n",
+		"  ❌ No node daemon found. Expected: %s\n":                                                   "  ❌ Nenhum daemon do nó encontrado. Esperado: %s\n",
+		"  ✅ Port %d is listening (method: %s)\n":                                                    "  ✅ A porta %d está escutando (método: %s)\n",
+		"  ❌ Port %d is not listening\n":                                                             "  ❌ A porta %d não está escutando\n",
+		"Step 3/3: Dry run, not submitting":                                                          "Passo 3/3: Simulação, nada será enviado",
+		"Step 3/3: Submitting verification to API...":                                                "Passo 3/3: Enviando a verificação para a API...",
+		"✅ Verification submitted successfully!":                                                     "✅ Verificação enviada com sucesso!",
+		"   Your verification will be reviewed by an admin.":                                         "   Sua verificação será revisada por um administrador.",
+		"   If the admins have follow-up questions, answer them with:":                               "   Se os administradores tiverem perguntas, responda com:",
+		"   Your node on the map: %s\n":                                                              "   Seu nó no mapa: %s\n",
+		"   Receipt saved to %s\n":                                                                   "   Recibo salvo em %s\n",
+		"❌ Invalid challenge format. Must be alphanumeric, 20-128 characters.":                       "❌ Formato de desafio inválido. Deve ser alfanumérico, com 20 a 128 caracteres.",
+		"❌ Failed to initialize verification: %v":                                                    "❌ Falha ao iniciar a verificação: %v",
+		"❌ Failed to submit verification: %v":                                                        "❌ Falha ao enviar a verificação: %v",
+		"❌ Challenge not found.":                                                                     "❌ Desafio não encontrado.",
+		"   Check that you copied the full challenge from the website.":                              "   Confira se você copiou o desafio completo do site.",
+		"❌ Challenge has expired.":                                                                   "❌ O desafio expirou.",
+		"   Challenges are only valid for a limited time. Generate a new one.":                       "   Os desafios valem por tempo limitado. Gere um novo.",
+		"❌ Challenge can no longer be used.":                                                         "❌ O desafio não pode mais ser usado.",
+		"   Start a new verification at: %s\n":                                                       "   Inicie uma nova verificação em: %s\n",
+		"   Open it in your browser now? [y/N] ":                                                     "   Abrir no navegador agora? [s/N] ",
+
+		"Welcome! This checks that you run the %s node you added on the map.\n":         "Bem-vindo! Isto confirma que você executa o nó %s que adicionou ao mapa.\n",
+		"Paste the challenge from the website (or press Enter to quit): ":               "Cole o desafio do site (ou pressione Enter para sair): ",
+		"  That doesn't look like a challenge: it should be 20-128 letters and digits.": "  Isso não parece um desafio: deve ter de 20 a 128 letras e dígitos.",
+		"⚠️  Pairing with the website didn't work: %v\n":                                "⚠️  Não foi possível parear com o site: %v\n",
+		"You can paste the challenge shown on the website instead.":                     "Você pode colar o desafio mostrado no site.",
+		"What went wrong:": "O que deu errado:",
+		"Fix this, then press Enter to try again (or type q to quit): ":                          "Corrija isso e pressione Enter para tentar de novo (ou digite q para sair): ",
+		"Open %s, start verifying your node with the binary method,\n":                           "Abra %s, inicie a verificação do seu nó com o método do binário\n",
+		"and enter this code in the verification dialog:":                                        "e digite este código na janela de verificação:",
+		"Waiting for the code (valid until %s, Ctrl-C to cancel)...\n":                           "Aguardando o código (válido até %s, Ctrl-C para cancelar)...\n",
+		"✅ Paired with the website, continuing with its challenge.":                              "✅ Pareado com o site, continuando com o desafio dele.",
+		"Your node software doesn't seem to be running on this computer.":                        "O software do nó não parece estar rodando neste computador.",
+		"Start it (for example: sudo systemctl start %s) and wait a minute.":                     "Inicie-o (por exemplo: sudo systemctl start %s) e aguarde um minuto.",
+		"Run this tool on the server where the node runs, not on your own PC.":                   "Execute esta ferramenta no servidor onde o nó roda, não no seu PC.",
+		"Your node is running but isn't accepting connections on its port (usually %d).":         "Seu nó está rodando mas não aceita conexões na porta (normalmente %d).",
+		"Make sure its config has listen=1 and no other port= setting, then restart it.":         "Confira se a configuração tem listen=1 e nenhum outro port=, e reinicie-o.",
+		"A node that has just started can take a minute before it listens.":                      "Um nó recém-iniciado pode levar um minuto para escutar.",
+		"The map's server couldn't be reached.":                                                  "Não foi possível contatar o servidor do mapa.",
+		"Check that this computer can open %s (internet access, firewall, proxy).":               "Confira se este computador consegue abrir %s (acesso à internet, firewall, proxy).",
+		"The map didn't recognise this challenge.":                                               "O mapa não reconheceu este desafio.",
+		"Copy it again from the website; it must be copied in full.":                             "Copie-o de novo do site; ele deve ser copiado por inteiro.",
+		"The challenge has expired.":                                                             "O desafio expirou.",
+		"Create a new one at %s/my-nodes.":                                                       "Crie um novo em %s/my-nodes.",
+		"This challenge was already used, or the node is already verified.":                      "Este desafio já foi usado, ou o nó já está verificado.",
+		"Check %s/my-nodes, or create a new challenge there.":                                    "Confira %s/my-nodes, ou crie lá um novo desafio.",
+		"The node on this computer runs on a test network, not the main network.":                "O nó deste computador roda em uma rede de testes, não na rede principal.",
+		"Point the tool at your main node's data directory with --datadir.":                      "Aponte a ferramenta para o diretório de dados do seu nó principal com --datadir.",
+		"This computer lacks the tools the checks need; the message above says what to install.": "Este computador não tem as ferramentas de que as verificações precisam; a mensagem acima diz o que instalar.",
+		"Something else went wrong; the message above says what.":                                "Algo mais deu errado; a mensagem acima explica o quê.",
+	},
+	"zh": {
+		"Starting node verification process...":       "开始验证节点……",
+		"Step 1/3: Fetching node details from API...": "第 1/3 步：从 API 获取节点信息……",
+		"  ✅ Node IP: %s\n":                           "  ✅ 节点 IP：%s\n",
+		"  ✅ Node Port: %d\n":                         "  ✅ 节点端口：%d\n",
+		"  ℹ️  Running as a sidecar in pod %s, checked the daemon over the pod network\n": "  ℹ️  以 sidecar 方式运行于 pod %s，已通过 pod 网络检查守护进程\n",
+		"  ℹ️  Installed as %s\n": "  ℹ️  安装方式：%s\n",
+		"  ⚠️  Port %d only listens on %s, which other hosts can't reach; check bind= in %s\n":  "  ⚠️  端口 %d 仅在 %s 上监听，其他主机无法访问；请检查 %s 中的 bind=\n",
+		"  ✅ RPC port %d is listening (method: %s)\n":                                           "  ✅ RPC 端口 %d 正在监听（方式：%s）\n",
+		"  ⚠️  RPC port %d is not listening; the node is reported without RPC\n":                "  ⚠️  RPC 端口 %d 未在监听；节点将以无 RPC 状态上报\n",
+		"  ⚠️  Port %d doesn't listen on %s, but the map knows the node as %s\n":                "  ⚠️  端口 %d 未在 %s 上监听，但地图记录的节点地址为 %s\n",
+		"  ⚠️  Port %d belongs to %s (PID %d), not the daemon\n":                                "  ⚠️  端口 %d 属于 %s（PID %d），而不是守护进程\n",
+		"  ℹ️  Managed by systemd unit %s, up %s\n":                                             "  ℹ️  由 systemd 单元 %s 管理，已运行 %s\n",
+		"  ℹ️  Managed by systemd unit %s\n":                                                    "  ℹ️  由 systemd 单元 %s 管理\n",
+		"  ℹ️  Daemon runs in %s container %s\n":                                                "  ℹ️  守护进程运行在 %s 容器 %s 中\n",
+		"  ⚠️  The container doesn't publish port %d on the host; check the -p/ports mapping\n": "  ⚠️  容器未在主机上发布端口 %d；请检查 -p/ports 映射\n",
+		"━━ Challenge %d of %d ━━\n":                                                            "━━ 挑战 %d / %d ━━\n",
+		"Summary: %d of %d verified\n":                                                          "汇总：%d / %d 已验证\n",
+		"Node":                                                                                  "节点",
+		"Outcome":                                                                               "结果",
+		"Details":                                                                               "详情",
+		"  ℹ️  Checking port %d locally instead (--port); the map decides whether it accepts that\n": "  ℹ️  改为在本地检查端口 %d（--port）；由地图决定是否接受\n",
+		"Step 2/3: Checking local node process and port...":                                          "第 2/3 步：检查本机节点进程和端口……",
+		"  ✅ Found daemon: %s (method: %s)\n":                                                        "  ✅ 找到守护进程：%s（方式：%s）\n",
+		"  ❌ No node daemon found. Expected: %s\n":                                                   "  ❌ 未找到节点守护进程。应为：%s\n",
+		"  ✅ Port %d is listening (method: %s)\n":                                                    "  ✅ 端口 %d 正在监听（方式：%s）\n",
+		"  ❌ Port %d is not listening\n":                                                             "  ❌ 端口 %d 未在监听\n",
+		"Step 3/3: Dry run, not submitting":                                                          "第 3/3 步：演练模式，不提交",
+		"Step 3/3: Submitting verification to API...":                                                "第 3/3 步：向 API 提交验证……",
+		"✅ Verification submitted successfully!":                                                     "✅ 验证已成功提交！",
+		"   Your verification will be reviewed by an admin.":                                         "   管理员将审核你的验证。",
+		"   If the admins have follow-up questions, answer them with:":                               "   如果管理员有后续问题，请用以下命令回答：",
+		"   Your node on the map: %s\n":                                                              "   你在地图上的节点：%s\n",
+		"   Receipt saved to %s\n":                                                                   "   收据已保存到 %s\n",
+		"❌ Invalid challenge format. Must be alphanumeric, 20-128 characters.":                       "❌ 挑战码格式无效。必须是 20 到 128 位的字母和数字。",
+		"❌ Failed to initialize verification: %v":                                                    "❌ 无法开始验证：%v",
+		"❌ Failed to submit verification: %v":                                                        "❌ 无法提交验证：%v",
+		"❌ Challenge not found.":                                                                     "❌ 找不到挑战码。",
+		"   Check that you copied the full challenge from the website.":                              "   请确认你从网站复制了完整的挑战码。",
+		"❌ Challenge has expired.":                                                                   "❌ 挑战码已过期。",
+		"   Challenges are only valid for a limited time. Generate a new one.":                       "   挑战码只在有限时间内有效。请生成一个新的。",
+		"❌ Challenge can no longer be used.":                                                         "❌ 挑战码已无法使用。",
+		"   Start a new verification at: %s\n":                                                       "   在此开始新的验证：%s\n",
+		"   Open it in your browser now? [y/N] ":                                                     "   现在在浏览器中打开吗？[y/N] ",
+
+		"Welcome! This checks that you run the %s node you added on the map.\n":         "欢迎！此工具确认你运行着添加到地图上的 %s 节点。\n",
+		"Paste the challenge from the website (or press Enter to quit): ":               "粘贴网站上的挑战码（或按 Enter 退出）：",
+		"  That doesn't look like a challenge: it should be 20-128 letters and digits.": "  这不像挑战码：应为 20 到 128 位字母和数字。",
+		"⚠️  Pairing with the website didn't work: %v\n":                                "⚠️  无法与网站配对：%v\n",
+		"You can paste the challenge shown on the website instead.":                     "你也可以粘贴网站上显示的挑战码。",
+		"What went wrong:": "出了什么问题：",
+		"Fix this, then press Enter to try again (or type q to quit): ":                          "修复后按 Enter 重试（或输入 q 退出）：",
+		"Open %s, start verifying your node with the binary method,\n":                           "打开 %s，用二进制程序方式开始验证你的节点，\n",
+		"and enter this code in the verification dialog:":                                        "并在验证对话框中输入此代码：",
+		"Waiting for the code (valid until %s, Ctrl-C to cancel)...\n":                           "正在等待输入代码（有效期至 %s，按 Ctrl-C 取消）……\n",
+		"✅ Paired with the website, continuing with its challenge.":                              "✅ 已与网站配对，继续使用其挑战码。",
+		"Your node software doesn't seem to be running on this computer.":                        "这台电脑上似乎没有运行节点软件。",
+		"Start it (for example: sudo systemctl start %s) and wait a minute.":                     "请启动它（例如：sudo systemctl start %s）并等待一分钟。",
+		"Run this tool on the server where the node runs, not on your own PC.":                   "请在运行节点的服务器上运行此工具，而不是在你自己的电脑上。",
+		"Your node is running but isn't accepting connections on its port (usually %d).":         "你的节点正在运行，但其端口（通常是 %d）不接受连接。",
+		"Make sure its config has listen=1 and no other port= setting, then restart it.":         "请确认配置中有 listen=1 且没有其他 port= 设置，然后重启节点。",
+		"A node that has just started can take a minute before it listens.":                      "刚启动的节点可能需要一分钟才开始监听。",
+		"The map's server couldn't be reached.":                                                  "无法连接地图服务器。",
+		"Check that this computer can open %s (internet access, firewall, proxy).":               "请确认这台电脑可以访问 %s（网络、防火墙、代理）。",
+		"The map didn't recognise this challenge.":                                               "地图无法识别此挑战码。",
+		"Copy it again from the website; it must be copied in full.":                             "请从网站重新复制，必须完整复制。",
+		"The challenge has expired.":                                                             "挑战码已过期。",
+		"Create a new one at %s/my-nodes.":                                                       "请在 %s/my-nodes 创建新的挑战码。",
+		"This challenge was already used, or the node is already verified.":                      "此挑战码已被使用，或节点已通过验证。",
+		"Check %s/my-nodes, or create a new challenge there.":                                    "请查看 %s/my-nodes，或在那里创建新的挑战码。",
+		"The node on this computer runs on a test network, not the main network.":                "这台电脑上的节点运行在测试网络上，而不是主网络。",
+		"Point the tool at your main node's data directory with --datadir.":                      "请用 --datadir 指向主网节点的数据目录。",
+		"This computer lacks the tools the checks need; the message above says what to install.": "这台电脑缺少检查所需的工具；上面的信息说明了需要安装什么。",
+		"Something else went wrong; the message above says what.":                                "出现了其他问题，请参阅上面的消息。",
+	},
+}
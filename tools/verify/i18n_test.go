@@ -0,0 +1,142 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var formatVerb = regexp.MustCompile(`%[-+# 0]*[0-9.]*[a-zA-Z%]`)
+
+func TestCatalogsComplete(t *testing.T) {
+	// Every message passed to tr needs a translation in each catalog.
+	// fatal translates its message too, when the catalogs have it.
+	files, _ := filepath.Glob("*.go")
+	fset := token.NewFileSet()
+	messages, fatalMessages := map[string]bool{}, map[string]bool{}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			ident, ok := call.Fun.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			arg := map[string]int{"tr": 0, "fatal": 1}[ident.Name]
+			if (ident.Name != "tr" && ident.Name != "fatal") || len(call.Args) <= arg {
+				return true
+			}
+			if lit, ok := call.Args[arg].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				msg, _ := strconv.Unquote(lit.Value)
+				if ident.Name == "tr" {
+					messages[msg] = true
+				} else {
+					fatalMessages[msg] = true
+				}
+			}
+			return true
+		})
+	}
+	if len(messages) == 0 {
+		t.Fatal("no tr calls found")
+	}
+
+	for code, catalog := range catalogs {
+		for msg := range messages {
+			if _, ok := catalog[msg]; !ok {
+				t.Errorf("%s: missing %q", code, msg)
+			}
+		}
+		for msg, translated := range catalog {
+			if !messages[msg] && !fatalMessages[msg] {
+				t.Errorf("%s: %q is not used", code, msg)
+			}
+			if want, got := formatVerb.FindAllString(msg, -1), formatVerb.FindAllString(translated, -1); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: %q has verbs %v, want %v", code, translated, got, want)
+			}
+		}
+	}
+}
+
+func TestDetectLang(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    string
+		env     map[string]string
+		want    string
+		wantErr bool
+	}{
+		{"default", "", nil, "en", false},
+		{"flag", "es", map[string]string{"LANG": "pt_BR.UTF-8"}, "es", false},
+		{"flag locale", "zh_CN", nil, "zh", false},
+		{"flag unsupported", "fr", nil, "en", true},
+		{"LANG", "", map[string]string{"LANG": "pt_BR.UTF-8"}, "pt", false},
+		{"LC_ALL wins", "", map[string]string{"LC_ALL": "es_ES.UTF-8", "LANG": "zh_CN.UTF-8"}, "es", false},
+		{"LC_MESSAGES", "", map[string]string{"LC_MESSAGES": "zh_TW", "LANG": "en_US.UTF-8"}, "zh", false},
+		{"C locale", "", map[string]string{"LANG": "C.UTF-8"}, "en", false},
+		{"unsupported locale", "", map[string]string{"LANG": "de_DE.UTF-8"}, "en", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+				t.Setenv(env, tt.env[env])
+			}
+			got, err := detectLang(tt.flag)
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("detectLang(%q) = %q, %v; want %q, error %v", tt.flag, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSplitLangFlag(t *testing.T) {
+	tests := []struct {
+		args      []string
+		wantValue string
+		wantRest  []string
+		wantErr   bool
+	}{
+		{[]string{"verify", "abc"}, "", []string{"verify", "abc"}, false},
+		{[]string{"--lang", "es", "verify"}, "es", []string{"verify"}, false},
+		{[]string{"verify", "-lang=pt", "abc"}, "pt", []string{"verify", "abc"}, false},
+		{[]string{"verify", "--", "--lang", "es"}, "", []string{"verify", "--", "--lang", "es"}, false},
+		{[]string{"verify", "--lang"}, "", nil, true},
+	}
+	for _, tt := range tests {
+		value, rest, err := splitLangFlag(tt.args)
+		if value != tt.wantValue || !reflect.DeepEqual(rest, tt.wantRest) || (err != nil) != tt.wantErr {
+			t.Errorf("splitLangFlag(%q) = %q, %q, %v", tt.args, value, rest, err)
+		}
+	}
+}
+
+func TestTr(t *testing.T) {
+	defer func(saved string) { lang = saved }(lang)
+
+	lang = "es"
+	if got := tr("  ❌ Port %d is not listening\n", 33117); got != "  ❌ El puerto 33117 no está escuchando\n" {
+		t.Errorf("es: %q", got)
+	}
+	if got := tr("untranslated %s", "x"); got != "untranslated x" {
+		t.Errorf("fallback: %q", got)
+	}
+	lang = "en"
+	if got := tr("  ❌ Port %d is not listening\n", 33117); got != "  ❌ Port 33117 is not listening\n" {
+		t.Errorf("en: %q", got)
+	}
+}
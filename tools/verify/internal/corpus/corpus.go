@@ -0,0 +1,58 @@
+// Package corpus holds outputs of ps, netstat, ss, lsof and sockstat as
+// printed on the platforms verify runs on, so the parsers are tested against
+// every format in the field instead of only the developer's machine.
+//
+// Each platform directory under samples/ has one file per tool, named after
+// the tool and holding the output of the command verify runs: ps -eo
+// pid=,ppid=,args=, netstat -an, ss -lntp, lsof -nP -iTCP:33117 or sockstat
+// -46 -l -P tcp. All samples describe the same host layout:
+//
+//   - Daemon listens on P2PPort on all addresses and on RPCPort on loopback
+//   - there is an outbound connection to a peer's P2PPort (not in sockstat,
+//     which lists listening sockets only)
+//   - ps shows a shell command that mentions Daemon without being it
+//
+// Platforms ending in "-stopped" are the same hosts with Daemon stopped: only
+// the shell command and the peer connection remain. Platforms ending in
+// "-ipv6only" bind Daemon to IPv6 addresses only.
+package corpus
+
+import (
+	"embed"
+	"path"
+	"strings"
+)
+
+const (
+	Daemon  = "dingocoind"
+	P2PPort = 33117
+	RPCPort = 34646
+)
+
+//go:embed samples
+var samples embed.FS
+
+// Sample is one tool's output on one platform
+type Sample struct {
+	Platform string
+	Output   string
+}
+
+// Running reports whether Daemon was running when the sample was taken
+func (s Sample) Running() bool {
+	return !strings.HasSuffix(s.Platform, "-stopped")
+}
+
+// Samples returns every platform's output for tool, in platform order
+func Samples(tool string) []Sample {
+	var out []Sample
+	platforms, _ := samples.ReadDir("samples")
+	for _, platform := range platforms {
+		data, err := samples.ReadFile(path.Join("samples", platform.Name(), tool+".txt"))
+		if err != nil {
+			continue
+		}
+		out = append(out, Sample{Platform: platform.Name(), Output: string(data)})
+	}
+	return out
+}
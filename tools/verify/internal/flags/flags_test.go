@@ -0,0 +1,241 @@
+package flags
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPortSet(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Port
+		wantErr bool
+	}{
+		{"33117", 33117, false},
+		{" 8333 ", 8333, false},
+		{"1", 1, false},
+		{"65535", 65535, false},
+		{"0", 0, true},
+		{"65536", 0, true},
+		{"-1", 0, true},
+		{"", 0, true},
+		{"http", 0, true},
+		{"33117x", 0, true},
+	}
+
+	for _, tt := range tests {
+		var p Port
+		err := p.Set(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Set(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err != nil && !strings.Contains(err.Error(), `"`+tt.in+`"`) {
+			t.Errorf("Set(%q) error %q does not quote the input", tt.in, err)
+		}
+		if p != tt.want {
+			t.Errorf("Set(%q) = %d, want %d", tt.in, p, tt.want)
+		}
+	}
+}
+
+func TestPortString(t *testing.T) {
+	tests := []struct {
+		p    Port
+		want string
+	}{
+		{0, ""},
+		{1, "1"},
+		{33117, "33117"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.p.String(); got != tt.want {
+			t.Errorf("Port(%d).String() = %q, want %q", int(tt.p), got, tt.want)
+		}
+	}
+}
+
+func TestDurationSet(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30", 30 * time.Second, false},
+		{"30s", 30 * time.Second, false},
+		{" 2m ", 2 * time.Minute, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"500ms", 500 * time.Millisecond, false},
+		{"0", 0, true},
+		{"0s", 0, true},
+		{"-5s", 0, true},
+		{"-1d", 0, true},
+		{"", 0, true},
+		{"soon", 0, true},
+		{"1.5d", 0, true},
+	}
+
+	for _, tt := range tests {
+		var d Duration
+		err := d.Set(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Set(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err != nil && !strings.Contains(err.Error(), `"`+tt.in+`"`) {
+			t.Errorf("Set(%q) error %q does not quote the input", tt.in, err)
+		}
+		if time.Duration(d) != tt.want {
+			t.Errorf("Set(%q) = %v, want %v", tt.in, time.Duration(d), tt.want)
+		}
+	}
+}
+
+func TestDurationString(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Second, "30s"},
+		{90 * time.Minute, "1h30m0s"},
+		{24 * time.Hour, "1d"},
+		{30 * 24 * time.Hour, "30d"},
+	}
+
+	for _, tt := range tests {
+		d := Duration(tt.d)
+		if got := d.String(); got != tt.want {
+			t.Errorf("Duration(%v).String() = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestByteSizeSet(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ByteSize
+		wantErr bool
+	}{
+		{"0", 0, false},
+		{"4096", 4096, false},
+		{"512K", 512 << 10, false},
+		{"512k", 512 << 10, false},
+		{"64KB", 64 << 10, false},
+		{"10MiB", 10 << 20, false},
+		{"2G", 2 << 30, false},
+		{"1T", 1 << 40, false},
+		{" 8 M ", 8 << 20, false},
+		{"-1K", 0, true},
+		{"1.5G", 0, true},
+		{"lots", 0, true},
+		{"", 0, true},
+		{"9999999999T", 0, true},
+	}
+
+	for _, tt := range tests {
+		var b ByteSize
+		err := b.Set(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Set(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err != nil && !strings.Contains(err.Error(), `"`+tt.in+`"`) {
+			t.Errorf("Set(%q) error %q does not quote the input", tt.in, err)
+		}
+		if b != tt.want {
+			t.Errorf("Set(%q) = %d, want %d", tt.in, b, tt.want)
+		}
+	}
+}
+
+func TestByteSizeString(t *testing.T) {
+	tests := []struct {
+		b    ByteSize
+		want string
+	}{
+		{0, "0"},
+		{1000, "1000"},
+		{64 << 10, "64K"},
+		{3 << 30, "3G"},
+		{(1 << 20) + 1, "1048577"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.b.String(); got != tt.want {
+			t.Errorf("ByteSize(%d).String() = %q, want %q", int64(tt.b), got, tt.want)
+		}
+	}
+}
+
+func TestHostPortSet(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantHost string
+		wantPort Port
+		wantErr  bool
+	}{
+		{"127.0.0.1:22555", "127.0.0.1", 22555, false},
+		{"node.example:8332", "node.example", 8332, false},
+		{"[::1]:22555", "::1", 22555, false},
+		{"127.0.0.1", "", 0, true},
+		{":22555", "", 0, true},
+		{"127.0.0.1:0", "", 0, true},
+		{"127.0.0.1:70000", "", 0, true},
+		{"::1:22555", "", 0, true},
+		{"", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		var h HostPort
+		err := h.Set(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Set(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err != nil && !strings.Contains(err.Error(), `"`+tt.in+`"`) {
+			t.Errorf("Set(%q) error %q does not quote the input", tt.in, err)
+		}
+		if h.Host != tt.wantHost || h.Port != tt.wantPort {
+			t.Errorf("Set(%q) = %s:%d, want %s:%d", tt.in, h.Host, h.Port, tt.wantHost, tt.wantPort)
+		}
+	}
+}
+
+func TestHostPortString(t *testing.T) {
+	tests := []struct {
+		h    HostPort
+		want string
+	}{
+		{HostPort{}, ""},
+		{HostPort{"127.0.0.1", 22555}, "127.0.0.1:22555"},
+		{HostPort{"::1", 22555}, "[::1]:22555"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.h.String(); got != tt.want {
+			t.Errorf("HostPort%+v.String() = %q, want %q", tt.h, got, tt.want)
+		}
+	}
+}
+
+func TestListSet(t *testing.T) {
+	var l List
+	for _, in := range []string{"a.sh", " b.sh , c.sh"} {
+		if err := l.Set(in); err != nil {
+			t.Fatalf("Set(%q) error = %v", in, err)
+		}
+	}
+	if got := l.String(); got != "a.sh,b.sh,c.sh" {
+		t.Errorf("String() = %q, want %q", got, "a.sh,b.sh,c.sh")
+	}
+
+	for _, in := range []string{"", "a.sh,,b.sh", " "} {
+		var l List
+		if err := l.Set(in); err == nil {
+			t.Errorf("Set(%q) succeeded, want error", in)
+		}
+	}
+}
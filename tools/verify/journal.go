@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Identifier for journalctl -t, e.g.
+// journalctl -t nodesmap-verify EVENT_TYPE=verification_failed
+const journalIdentifier = "nodesmap-verify"
+
+// syslog priorities used for journal entries
+const (
+	journalPriorityErr  = 3
+	journalPriorityInfo = 6
+)
+
+// journalFields turns the outcome of a run into structured journal fields
+func journalFields(result *Result) map[string]string {
+	fields := map[string]string{
+		"SYSLOG_IDENTIFIER": journalIdentifier,
+		"CHAIN":             result.Chain,
+		"VERIFY_VERSION":    result.Version,
+		"PROCESS_FOUND":     strconv.FormatBool(result.ProcessCheck.Found),
+		"PORT_LISTENING":    strconv.FormatBool(result.PortCheck.Listening),
+	}
+	if result.Node.IP != "" {
+		fields["NODE_ADDRESS"] = fmt.Sprintf("%s:%d", result.Node.IP, result.Node.Port)
+	}
+
+	switch {
+	case result.DryRun:
+		fields["EVENT_TYPE"] = "verification_dry_run"
+		fields["PRIORITY"] = strconv.Itoa(journalPriorityInfo)
+		fields["MESSAGE"] = "Verification checks run without submitting (--dry-run)"
+	case result.Submitted:
+		fields["EVENT_TYPE"] = "verification_submitted"
+		fields["PRIORITY"] = strconv.Itoa(journalPriorityInfo)
+		fields["STATUS"] = result.Status
+		fields["MESSAGE"] = "Verification submitted: " + result.Status
+	default:
+		fields["EVENT_TYPE"] = "verification_failed"
+		fields["PRIORITY"] = strconv.Itoa(journalPriorityErr)
+		fields["ERROR"] = result.Error
+		fields["MESSAGE"] = "Verification failed: " + result.Error
+	}
+	if result.Tip != nil && result.Tip.Stale != "" {
+		fields["TIP_STALE"] = result.Tip.Stale
+	}
+	if result.Performance != nil && len(result.Performance.Regressions) > 0 {
+		fields["PERF_REGRESSION"] = strings.Join(result.Performance.Regressions, "; ")
+	}
+	return fields
+}
+
+// logToJournal records the run's outcome in the systemd journal. Failing
+// to log never changes the outcome.
+func logToJournal(result *Result) {
+	if err := sendJournal(journalFields(result)); err != nil {
+		fmt.Printf("⚠️  Could not write to the journal: %v\n", err)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"sort"
+	"strings"
+)
+
+const journalSocket = "/run/systemd/journal/socket"
+
+// sendJournal sends one entry over journald's native protocol. Values with
+// a newline use the length-prefixed form.
+func sendJournal(fields map[string]string) error {
+	conn, err := net.Dial("unixgram", journalSocket)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write(journalEntry(fields))
+	return err
+}
+
+// journalEntry serializes fields in key order
+func journalEntry(fields map[string]string) []byte {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b bytes.Buffer
+	for _, key := range keys {
+		value := fields[key]
+		if !strings.Contains(value, "\n") {
+			b.WriteString(key + "=" + value + "\n")
+			continue
+		}
+		b.WriteString(key + "\n")
+		binary.Write(&b, binary.LittleEndian, uint64(len(value)))
+		b.WriteString(value + "\n")
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestJournalEntry(t *testing.T) {
+	got := journalEntry(map[string]string{
+		"PRIORITY": "3",
+		"MESSAGE":  "a\nb",
+	})
+	want := append([]byte("MESSAGE\n\x03\x00\x00\x00\x00\x00\x00\x00a\nb\n"), "PRIORITY=3\n"...)
+	if !bytes.Equal(got, want) {
+		t.Errorf("journalEntry = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+// journald only exists on Linux
+func sendJournal(fields map[string]string) error {
+	return errors.New("the systemd journal is only available on Linux")
+}
@@ -0,0 +1,51 @@
+package main
+
+import "testing"
+
+func TestJournalFields(t *testing.T) {
+	submitted := &Result{
+		Chain:     "Dingocoin",
+		Node:      NodeAddress{IP: "203.0.113.5", Port: 33117},
+		Submitted: true,
+		Status:    "pending_approval",
+	}
+	submitted.ProcessCheck.Found = true
+	failed := &Result{Chain: "Dingocoin", Error: "API error: Daemon process not found"}
+	dryRun := &Result{Chain: "Dingocoin", DryRun: true}
+
+	tests := []struct {
+		name   string
+		result *Result
+		want   map[string]string
+	}{
+		{"submitted", submitted, map[string]string{
+			"EVENT_TYPE":        "verification_submitted",
+			"PRIORITY":          "6",
+			"NODE_ADDRESS":      "203.0.113.5:33117",
+			"PROCESS_FOUND":     "true",
+			"PORT_LISTENING":    "false",
+			"SYSLOG_IDENTIFIER": journalIdentifier,
+		}},
+		{"failed before init", failed, map[string]string{
+			"EVENT_TYPE": "verification_failed",
+			"PRIORITY":   "3",
+			"ERROR":      "API error: Daemon process not found",
+		}},
+		{"dry run", dryRun, map[string]string{
+			"EVENT_TYPE": "verification_dry_run",
+			"PRIORITY":   "6",
+		}},
+	}
+
+	for _, tt := range tests {
+		fields := journalFields(tt.result)
+		for key, want := range tt.want {
+			if fields[key] != want {
+				t.Errorf("%s: %s = %q, want %q", tt.name, key, fields[key], want)
+			}
+		}
+	}
+	if _, ok := journalFields(failed)["NODE_ADDRESS"]; ok {
+		t.Error("NODE_ADDRESS set without a node")
+	}
+}
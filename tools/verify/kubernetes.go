@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// A sidecar container shares the pod's network with the daemon's
+// container but not its processes (unless the pod sets
+// shareProcessNamespace), so ps and /proc don't show the daemon. The pod is
+// identified by the Downward API environment variables, set in the
+// sidecar's spec:
+//
+//	env:
+//	- name: POD_NAME
+//	  valueFrom: {fieldRef: {fieldPath: metadata.name}}
+//	- name: POD_NAMESPACE
+//	  valueFrom: {fieldRef: {fieldPath: metadata.namespace}}
+//	- name: NODE_NAME
+//	  valueFrom: {fieldRef: {fieldPath: spec.nodeName}}
+
+// PodInfo is the Kubernetes pod the tool runs in
+type PodInfo struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Node      string `json:"node,omitempty"`
+}
+
+// Pod, namespace and node names are DNS subdomains or labels
+var k8sName = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]{0,251}[a-z0-9])?$`)
+
+// detectPod returns the pod from the Downward API variables, nil outside
+// Kubernetes or when the pod spec doesn't expose them
+func detectPod() *PodInfo {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return nil
+	}
+	pod := &PodInfo{
+		Name:      strings.TrimSpace(os.Getenv("POD_NAME")),
+		Namespace: strings.TrimSpace(os.Getenv("POD_NAMESPACE")),
+		Node:      strings.TrimSpace(os.Getenv("NODE_NAME")),
+	}
+	if !k8sName.MatchString(pod.Name) || !k8sName.MatchString(pod.Namespace) {
+		return nil
+	}
+	if !k8sName.MatchString(pod.Node) {
+		pod.Node = ""
+	}
+	return pod
+}
+
+func (p *PodInfo) String() string {
+	return p.Namespace + "/" + p.Name
+}
+
+// checkProcessPod looks for the daemon over the pod network: containers in
+// a pod share loopback, so a daemon that answers RPC on 127.0.0.1 runs in
+// this pod. Not used when --rpc-addr points at another host.
+func checkProcessPod() (bool, string) {
+	if localRPCPort() == 0 {
+		return false, ""
+	}
+	var netInfo NetworkInfo
+	if err := rpcCall("getnetworkinfo", &netInfo); err != nil || netInfo.Subversion == "" {
+		return false, ""
+	}
+	return true, "pod-rpc"
+}
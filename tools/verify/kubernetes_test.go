@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestDetectPod(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.96.0.1")
+	t.Setenv("POD_NAME", "dingocoind-0")
+	t.Setenv("POD_NAMESPACE", "nodes")
+	t.Setenv("NODE_NAME", "worker-1.example.com")
+	if got := detectPod(); got == nil || *got != (PodInfo{"dingocoind-0", "nodes", "worker-1.example.com"}) {
+		t.Errorf("detectPod() = %+v", got)
+	}
+
+	// The node name is optional, the pod name is not
+	t.Setenv("NODE_NAME", "")
+	if got := detectPod(); got == nil || got.Node != "" || got.String() != "nodes/dingocoind-0" {
+		t.Errorf("without node: %+v", got)
+	}
+	t.Setenv("POD_NAME", "")
+	if got := detectPod(); got != nil {
+		t.Errorf("without pod name: %+v", got)
+	}
+
+	// Values that aren't Kubernetes names, e.g. an unexpanded $(POD_NAME)
+	t.Setenv("POD_NAME", "$(POD_NAME)")
+	if got := detectPod(); got != nil {
+		t.Errorf("invalid pod name: %+v", got)
+	}
+
+	t.Setenv("POD_NAME", "dingocoind-0")
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	if got := detectPod(); got != nil {
+		t.Errorf("outside Kubernetes: %+v", got)
+	}
+}
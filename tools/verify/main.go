@@ -1,161 +1,977 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"net"
 	"net/http"
+	"net/netip"
+	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"text/template"
 	"time"
+
+	"github.com/atlasp2p/verify/internal/flags"
 )
 
 // Build-time configuration (injected via ldflags from build.sh)
 // These placeholders are overridden at compile time - do not use defaults
 var (
-	Version     = "2.0.0"
-	ApiUrl      = ""  // Injected: -X main.ApiUrl=$API_URL
-	DaemonNames = ""  // Injected: -X main.DaemonNames=$DAEMON_NAMES
-	DefaultPort = ""  // Injected: -X main.DefaultPort=$DEFAULT_PORT
-	ChainName   = ""  // Injected: -X main.ChainName=$CHAIN_NAME
+	Version      = "2.0.0"
+	ApiUrl       = ""  // Injected: -X main.ApiUrl=$API_URL
+	DaemonNames  = ""  // Injected: -X main.DaemonNames=$DAEMON_NAMES
+	DefaultPort  = ""  // Injected: -X main.DefaultPort=$DEFAULT_PORT
+	ChainName    = ""  // Injected: -X main.ChainName=$CHAIN_NAME
+	RpcPort      = ""  // Optional: -X main.RpcPort=$RPC_PORT (RPC-based checks)
+	BlocklistKey = ""  // Optional: -X main.BlocklistKey=$BLOCKLIST_PUBLIC_KEY (verify peers)
+	PayloadKey   = ""  // Optional: -X main.PayloadKey=$PAYLOAD_PUBLIC_KEY (--encrypt-payload)
 )
 
+// defaultPort is DefaultPort, validated once at startup
+var defaultPort flags.Port
+
+// Time limits for the API requests (--api-timeout) and for each local
+// check and check script (--check-timeout)
+var (
+	apiTimeout   = flags.Duration(30 * time.Second)
+	checkTimeout = flags.Duration(10 * time.Second)
+)
+
+// quiet discards all progress output (--quiet); only the exit code and
+// fatal errors on stderr remain
+var quiet bool
+
 // Shared HTTP client to ensure connection reuse and consistent routing
-// Forces IPv4 to match the node's IP in the database (crawlers record IPv4)
+// Prefers IPv4 to match the node's IP in the database (crawlers record IPv4)
 // This prevents dual-stack issues where requests might go via IPv6
 var httpClient = &http.Client{
 	Timeout: 30 * time.Second,
-	Transport: &http.Transport{
+	Transport: loggingTransport{&http.Transport{
 		MaxIdleConns:       10,
 		IdleConnTimeout:    90 * time.Second,
 		DisableCompression: true,
 		DisableKeepAlives:  false, // Keep connections alive
-		// Force IPv4 connections to match node IP recorded by crawler
-		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
-			dialer := &net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-			}
-			// Force tcp4 (IPv4 only) instead of tcp (which prefers IPv6)
-			return dialer.DialContext(ctx, "tcp4", addr)
-		},
-	},
+		// IPv4 first, IPv6 only as fallback or with --force-ipv6
+		DialContext: dialAPI,
+		// System roots plus bundled fallback roots (see certs.go)
+		TLSClientConfig: apiTLSConfig(),
+	}},
 }
 
 // API Request/Response structures
 type InitRequest struct {
 	Challenge string `json:"challenge"`
 	Hostname  string `json:"hostname,omitempty"`
+	// Ask the backend to probe an auxiliary port (see reachability.go)
+	ReachabilityProof bool `json:"reachabilityProof,omitempty"`
 }
 
 type InitResponse struct {
-	Success bool   `json:"success"`
-	Node    struct {
-		IP   string `json:"ip"`
-		Port int    `json:"port"`
-	} `json:"node"`
-	Message string `json:"message,omitempty"`
-	Error   string `json:"error,omitempty"`
+	Success bool        `json:"success"`
+	Node    NodeAddress `json:"node"`
+	Message string      `json:"message,omitempty"`
+	Error   string      `json:"error,omitempty"`
+	Code    string      `json:"code,omitempty"`
+	// Set when the backend accepted a reachabilityProof request
+	ReachabilityProbe *ReachabilityProbe `json:"reachabilityProbe,omitempty"`
+	// Set when the backend accepts sealed confirm payloads
+	PayloadEncryption *PayloadEncryption `json:"payloadEncryption,omitempty"`
+	// Fields from a newer backend, see decodeResponse
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// NodeAddress is the node's address as recorded by the crawler
+type NodeAddress struct {
+	IP   string `json:"ip"`
+	Port int    `json:"port"`
+}
+
+// APIError is an unsuccessful API response, keeping the HTTP status and
+// error code so callers can tell challenge problems apart
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: %s", e.Message)
+}
+
+// Exit codes for failures the operator can act on. Automation relies on
+// them (see --quiet), so they must not change; 1 is any other failure.
+const (
+	exitDaemonNotFound    = 2
+	exitPortNotListening  = 3
+	exitAPIUnreachable    = 4
+	exitChallengeRejected = 5 // not found, or malformed
+	exitChallengeExpired  = 6
+	exitChallengeUsed     = 7
+	exitChainMismatch     = 8
+	exitUnsupported       = 9 // no method for a required check here
+)
+
+// The map tracks mainnet nodes, as named by getblockchaininfo
+const expectedChain = "main"
+
+// challengeExitCode maps an init error to its exit code, or 0 when the
+// error is not about the challenge itself
+func (e *APIError) challengeExitCode() int {
+	switch e.Code {
+	case "VERIFICATION_NOT_FOUND":
+		return exitChallengeRejected
+	case "VERIFICATION_EXPIRED":
+		return exitChallengeExpired
+	case "VERIFICATION_ALREADY_SUBMITTED", "NODE_ALREADY_VERIFIED":
+		return exitChallengeUsed
+	case "":
+		// Older backends without error codes: fall back to the status
+		switch e.Status {
+		case http.StatusNotFound:
+			return exitChallengeRejected
+		case http.StatusGone:
+			return exitChallengeExpired
+		case http.StatusConflict:
+			return exitChallengeUsed
+		}
+	}
+	return 0
 }
 
 type ConfirmRequest struct {
-	Challenge string `json:"challenge"`
-	ProcessCheck struct {
-		Found      bool   `json:"found"`
-		Method     string `json:"method"`
-		DaemonName string `json:"daemonName,omitempty"`
-	} `json:"processCheck"`
-	PortCheck struct {
-		Listening bool   `json:"listening"`
-		Port      int    `json:"port"`
-		Method    string `json:"method"`
-	} `json:"portCheck"`
-	SystemInfo struct {
-		Hostname string `json:"hostname,omitempty"`
-		Platform string `json:"platform,omitempty"`
-		Arch     string `json:"arch,omitempty"`
-	} `json:"systemInfo,omitempty"`
+	Challenge         string              `json:"challenge"`
+	ProcessCheck      ProcessCheck        `json:"processCheck"`
+	PortCheck         PortCheck           `json:"portCheck"`
+	SystemInfo        SystemInfo          `json:"systemInfo,omitempty"`
+	UserAgentCheck    *UserAgentCheck     `json:"userAgentCheck,omitempty"`
+	Escalation        []EscalationStep    `json:"escalation,omitempty"`
+	ReachabilityProof *ReachabilityResult `json:"reachabilityProof,omitempty"`
+	AcceptsPolling    bool                `json:"acceptsPolling,omitempty"`
+	Strict            *StrictEvidence     `json:"strict,omitempty"`
+	// Output of the operator's --check-script scripts by script name
+	CustomChecks map[string]json.RawMessage `json:"customChecks,omitempty"`
+	// How long the init request and the local checks took
+	Timings *StepTimings `json:"timings,omitempty"`
+	// Every port checked, the P2P port (PortCheck) first
+	Ports []PortCheck `json:"ports,omitempty"`
+}
+
+type ProcessCheck struct {
+	Found      bool             `json:"found"`
+	Method     string           `json:"method"`
+	DaemonName string           `json:"daemonName,omitempty"`
+	Evidence   *ProcessEvidence `json:"evidence,omitempty"`
+	Container  *ContainerInfo   `json:"container,omitempty"`
+}
+
+type PortCheck struct {
+	// "p2p" or "rpc" in ConfirmRequest.Ports
+	Role      string       `json:"role,omitempty"`
+	Listening bool         `json:"listening"`
+	Port      int          `json:"port"`
+	Method    string       `json:"method"`
+	Owner     *SocketOwner `json:"owner,omitempty"`
+	// Addresses the listening sockets are bound to, "*" where a tool
+	// doesn't say which family
+	BindAddresses []string `json:"bindAddresses,omitempty"`
+	// Address families with a listening socket, "ipv4" and/or "ipv6"
+	Families []string `json:"families,omitempty"`
+}
+
+type SystemInfo struct {
+	Hostname    string   `json:"hostname,omitempty"`
+	Platform    string   `json:"platform,omitempty"`
+	Arch        string   `json:"arch,omitempty"`
+	Provider    string   `json:"provider,omitempty"`    // Coarse hosting label, see detectEnvironment
+	Environment string   `json:"environment,omitempty"` // container, vm, bare-metal or unknown
+	DiskFreeGB  float64  `json:"diskFreeGB,omitempty"`  // Only with --share-disk
+	Pod         *PodInfo `json:"pod,omitempty"`         // Kubernetes pod, see detectPod
+}
+
+// UserAgentCheck asks the backend to connect to the node's P2P port and
+// look for Token in the advertised user agent (reverse challenge)
+type UserAgentCheck struct {
+	Token  string `json:"token"`
+	Method string `json:"method"`
+}
+
+// AddrRelayResult is the backend's getaddr probe of the node. Nodes that
+// answer with few or no addresses don't help peers discover the network.
+type AddrRelayResult struct {
+	Success      bool   `json:"success"`
+	AddressCount int    `json:"addressCount"`
+	Relays       bool   `json:"relays"`
+	Error        string `json:"error,omitempty"`
 }
 
 type ConfirmResponse struct {
-	Success bool   `json:"success"`
-	Status  string `json:"status,omitempty"`
-	Message string `json:"message,omitempty"`
-	Error   string `json:"error,omitempty"`
+	Success   bool             `json:"success"`
+	Status    string           `json:"status,omitempty"`
+	Message   string           `json:"message,omitempty"`
+	Error     string           `json:"error,omitempty"`
+	Code      string           `json:"code,omitempty"`
+	AddrRelay *AddrRelayResult `json:"addrRelay,omitempty"`
+	// With --strict, the proofs the map accepted
+	Strict *StrictOutcome `json:"strict,omitempty"`
+	// The node's entry on the map
+	NodeID string `json:"nodeId,omitempty"`
+	// Proof of the submission, saved to the receipts directory
+	Receipt *Receipt `json:"receipt,omitempty"`
+	// Set with 202 Accepted while the backend's own checks still run
+	PollToken string `json:"pollToken,omitempty"`
+	// Fields from a newer backend, see decodeResponse
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 func main() {
+	plain, args := splitPlainFlags(os.Args[1:])
+	if plain != plainOff && !isPlainChild() {
+		os.Exit(runPlain(plain))
+	}
+
+	// A config file and DINGO_VERIFY_* variables may override or supply the
+	// build-time values, the variables taking precedence
+	configPath, args, err := splitConfigFlag(args)
+	if err == nil {
+		err = loadConfig(configPath)
+	}
+	if err == nil {
+		err = applyEnvOverrides()
+	}
+	var daemonNameFlags []string
+	if err == nil {
+		daemonNameFlags, args, err = splitDaemonNameFlag(args)
+	}
+	if err == nil {
+		err = applyDaemonNameFlag(daemonNameFlags)
+	}
+	if err != nil {
+		fmt.Printf("ERROR: Invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+	logLevel, logEnabled, args := splitLogFlags(args)
+	if logEnabled {
+		setupDebugLog(logLevel)
+	}
+	langFlag, args, err := splitLangFlag(args)
+	if err == nil {
+		lang, err = detectLang(langFlag)
+	}
+	if err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	os.Args = append(os.Args[:1], args...)
+
 	// Validate build-time configuration
 	if ApiUrl == "" || DaemonNames == "" || DefaultPort == "" || ChainName == "" {
 		fmt.Println("ERROR: This binary was not built correctly.")
-		fmt.Println("Build-time configuration is missing. Use build.sh to compile.")
+		fmt.Println("Build-time configuration is missing. Use build.sh to compile, or set")
+		fmt.Printf("apiUrl, daemonNames, defaultPort and chainName in %s\n", filepath.Join(configDir(), "config.yaml"))
+		fmt.Println("(or a file given with --config). DINGO_VERIFY_API_URL, DINGO_VERIFY_PORT and")
+		fmt.Println("DINGO_VERIFY_DAEMONS (or --daemon-name) override the API URL, port and daemon names.")
 		os.Exit(1)
 	}
+	if err := defaultPort.Set(DefaultPort); err != nil {
+		fmt.Printf("ERROR: DefaultPort is invalid: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Subcommands that don't run a verification. "verify <challenge>" is the
+	// verification itself; a bare challenge still works as it always has.
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "verify":
+			os.Args = append(os.Args[:1], os.Args[2:]...)
+		case "status":
+			runStatus(os.Args[2:])
+			return
+		case "recheck":
+			runRecheck(os.Args[2:])
+			return
+		case "preflight":
+			runPreflight(os.Args[2:])
+			return
+		case "version", "--version":
+			runVersion(os.Args[2:])
+			return
+		case "help":
+			runHelp(os.Args[2:])
+			return
+		case "--help-json":
+			if err := writeHelpJSON(os.Stdout); err != nil {
+				log.Fatalf("❌ %v", err)
+			}
+			return
+		case "badge":
+			runBadge(os.Args[2:])
+			return
+		case "emit-curl":
+			runEmitCurl(os.Args[2:])
+			return
+		case "bench-checks":
+			runBenchChecks(os.Args[2:])
+			return
+		case "attach-badge":
+			runAttachBadge(os.Args[2:])
+			return
+		case "retire":
+			runRetire(os.Args[2:])
+			return
+		case "questions":
+			runQuestions(os.Args[2:])
+			return
+		case "note":
+			runNote(os.Args[2:])
+			return
+		case "peers":
+			runPeers(os.Args[2:])
+			return
+		case "doctor":
+			runDoctor(os.Args[2:])
+			return
+		case "handshake":
+			runHandshake(os.Args[2:])
+			return
+		case "completion":
+			runCompletion(os.Args[2:])
+			return
+		case "gc":
+			runGC(os.Args[2:])
+			return
+		}
+	}
+
+	uaComment := flag.Bool("uacomment", false, "Prove ownership by advertising a challenge-derived token in the daemon's user agent")
+	reachProof := flag.Bool("reachability-proof", false, "Let the map probe an auxiliary port to prove inbound reachability")
+	followLog := flag.Bool("follow-daemon-log", false, "Show relevant daemon debug.log lines during the checks")
+	daemonLog := flag.String("daemon-log", "", "Path to the daemon's debug.log (default: <datadir>/debug.log)")
+	flag.StringVar(&dataDir, "datadir", defaultDataDir(), "Daemon data directory (config, debug.log, .cookie)")
+	reportTemplate := flag.String("report-template", "", "Go template file rendered with the final result")
+	reportOutput := flag.String("report-output", "", "Write the rendered report to this file (default: stdout)")
+	forceIPv4 := flag.Bool("force-ipv4", false, "Only connect to the API over IPv4")
+	forceIPv6 := flag.Bool("force-ipv6", false, "Only connect to the API over IPv6")
+	noProvider := flag.Bool("no-provider", false, "Don't report the hosting provider, environment and the daemon's container")
+	shareDisk := flag.Bool("share-disk", false, "Report the data directory's free disk space to the map")
+	flag.Var(&daemonLogBacklog, "log-backlog", "How much of debug.log to scan before following it (e.g. 64K, 1M)")
+	flag.Var(&rpcAddr, "rpc-addr", "Daemon RPC host:port (default: 127.0.0.1 and rpcport from the config)")
+	var checkScripts flags.List
+	flag.Var(&checkScripts, "check-script", "Run an allow-listed script and submit its JSON output (repeatable)")
+	flag.Var(&checkTimeout, "check-timeout", "Time limit for the process and port checks, time and CPU limit for each --check-script")
+	flag.Var(&apiTimeout, "api-timeout", "Time limit for each request to the map's API")
+	flag.StringVar(&stateDirFlag, "state-dir", "", "Keep state (the performance baseline) and relocated reports here")
+	var portOverride flags.Port
+	flag.Var(&portOverride, "port", "Check this local P2P port instead of the one the map knows (if the map allows it)")
+	jsonOutput := flag.Bool("json", false, "Print the result as JSON on stdout; everything else goes to stderr")
+	resetBaseline := flag.Bool("reset-baseline", false, "Record a new performance baseline for this node, e.g. after a hardware change")
+	challengeFile := flag.String("challenge-file", "", "Read the challenge from this file (- for stdin) instead of the command line")
+	challengesFile := flag.String("challenges-file", "", "Verify each challenge in this file, one per line (- for stdin), then print a summary")
+	dryRun := flag.Bool("dry-run", false, "Run the checks and print the confirm payload instead of submitting it")
+	encryptPayload := flag.Bool("encrypt-payload", false, "Also encrypt the submitted results to the map's key, for TLS-intercepting proxies")
+	strict := flag.Bool("strict", false, "Only pass with cryptographic evidence: RPC, a signmessage signature or the P2P handshake (--uacomment)")
+	signAddress := flag.String("sign-address", "", "With --strict, have the daemon's wallet sign the challenge with this address")
+	journal := flag.Bool("journal", false, "Record the outcome in the systemd journal with structured fields (Linux)")
+	flag.BoolVar(&quiet, "quiet", false, "Print nothing but fatal errors; report the outcome through the exit code")
+	flag.Usage = printUsage
+	flag.Parse()
+	httpClient.Timeout = time.Duration(apiTimeout)
+
+	// With --json stdout carries only the result document, so progress,
+	// prompts and a template report printed to the terminal move to stderr.
+	// --quiet drops them altogether.
+	jsonOut := os.Stdout
+	if quiet {
+		if *uaComment {
+			log.Fatal("❌ --quiet cannot be combined with --uacomment, which waits for a daemon restart")
+		}
+		devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		os.Stdout = devNull
+	} else if *jsonOutput {
+		os.Stdout = os.Stderr
+	}
+
+	// Without a challenge on an interactive terminal, guide the operator
+	// instead of printing usage
+	// The options --config, the log level and --lang, taken off os.Args
+	// above, for runs of the tool itself
+	childArgs := func(args []string) []string {
+		if configPath != "" {
+			args = append([]string{"--config", configPath}, args...)
+		}
+		for i := len(daemonNameFlags) - 1; i >= 0; i-- {
+			args = append([]string{"--daemon-name", daemonNameFlags[i]}, args...)
+		}
+		if logEnabled {
+			args = append([]string{"--" + strings.ToLower(logLevel.String())}, args...)
+		}
+		if langFlag != "" {
+			args = append([]string{"--lang", langFlag}, args...)
+		}
+		return args
+	}
+	if flag.NArg() < 1 && *challengeFile == "" && *challengesFile == "" && !quiet && !*jsonOutput && isTerminal(os.Stdin) && isTerminal(os.Stdout) {
+		os.Exit(runWizard(childArgs(os.Args[1:])))
+	}
+
+	// Several challenges, e.g. for the nodes behind a jump host, are
+	// verified one run each, followed by a summary
+	if flag.NArg() > 1 || *challengesFile != "" {
+		challenges := flag.Args()
+		switch {
+		case *challengesFile != "" && flag.NArg() > 0:
+			log.Fatal("❌ Give the challenges as arguments or with --challenges-file, not both")
+		case *challengeFile != "":
+			log.Fatal("❌ --challenge-file takes a single challenge; use --challenges-file for several")
+		case *uaComment:
+			log.Fatal("❌ --uacomment waits for a daemon restart, so it verifies one challenge at a time")
+		case *reportOutput != "":
+			log.Fatal("❌ --report-output would be overwritten by each challenge; use --json for all results")
+		}
+		if *challengesFile != "" {
+			in := os.Stdin
+			if *challengesFile != "-" {
+				if in, err = os.Open(*challengesFile); err != nil {
+					log.Fatalf("❌ %v", err)
+				}
+			}
+			challenges, err = readChallenges(in)
+			in.Close()
+			if err != nil {
+				fatal(exitChallengeRejected, "❌ Cannot read the challenges: %v", err)
+			}
+		}
+		for _, challenge := range challenges {
+			addRedaction(challenge)
+			if !isValidChallenge(challenge) {
+				fatal(exitChallengeRejected, "❌ Invalid challenge format. Must be alphanumeric, 20-128 characters.")
+			}
+		}
+		options := dropFlag(os.Args[1:len(os.Args)-flag.NArg()], "challenges-file")
+		os.Exit(runBatch(challenges, childArgs(options), *jsonOutput, jsonOut))
+	}
 
 	printBanner()
+	printConfigSources()
+
+	switch {
+	case *forceIPv4 && *forceIPv6:
+		log.Fatal("❌ --force-ipv4 and --force-ipv6 cannot be combined")
+	case *forceIPv4:
+		ipFamily = "4"
+	case *forceIPv6:
+		ipFamily = "6"
+	}
+	if *dryRun && *reachProof {
+		log.Fatal("❌ --dry-run cannot be combined with --reachability-proof, which has the map probe the node")
+	}
+	if *signAddress != "" && !*strict {
+		log.Fatal("❌ --sign-address only applies with --strict")
+	}
 
-	if len(os.Args) < 2 {
+	if flag.NArg() < 1 && *challengeFile == "" {
 		printUsage()
 		os.Exit(1)
 	}
 
-	challenge := os.Args[1]
+	// Parse the report template up front so mistakes fail before any API call
+	var reportTmpl *template.Template
+	if *reportTemplate != "" {
+		tmpl, err := loadReportTemplate(*reportTemplate)
+		if err != nil {
+			log.Fatalf("❌ Invalid report template: %v", err)
+		}
+		reportTmpl = tmpl
+	}
+
+	// Refuse scripts outside the allow-listed directory before any API call
+	scripts, err := resolveCheckScripts(checkScripts, checkScriptsDir())
+	if err != nil {
+		log.Fatalf("❌ Invalid --check-script: %v", err)
+	}
+
+	// From here on every exit writes the report, including early failures
+	result := newResult()
+	report := func() {
+		writeReport(reportTmpl, *reportOutput, result)
+		if *jsonOutput {
+			writeJSONResult(jsonOut, result)
+		}
+		if *journal {
+			logToJournal(result)
+		}
+	}
+
+	// Probe once which evidence sources this host allows; the checks pick
+	// their methods from the result. Without a process lookup the check
+	// could only report a misleading failure, so stop before the API call.
+	if support := platformSupport(); !support.Supported {
+		printSupportIssues(os.Stderr, support, os.Args[0])
+		result.Support = &support
+		result.Error = "platform lacks a method for a required check"
+		report()
+		os.Exit(exitUnsupported)
+	}
+
+	// A challenge from a file or pipe stays out of the shell history
+	challenge, challengeFromStdin, err := resolveChallenge(flag.Arg(0), *challengeFile, os.Stdin)
+	if err != nil {
+		result.Error = err.Error()
+		report()
+		fatal(exitChallengeRejected, "❌ Cannot read the challenge: %v", err)
+	}
+	if challengeFromStdin && *uaComment {
+		log.Fatal("❌ --uacomment waits for Enter on stdin, so the challenge can't come from it; use --challenge-file <path>")
+	}
+	addRedaction(challenge)
 
 	// Validate challenge format
 	if !isValidChallenge(challenge) {
-		log.Fatal("❌ Invalid challenge format. Must be alphanumeric, 20-128 characters.")
+		result.Error = "invalid challenge format"
+		report()
+		fatal(exitChallengeRejected, "❌ Invalid challenge format. Must be alphanumeric, 20-128 characters.")
 	}
 
-	fmt.Println("Starting node verification process...")
+	fmt.Println(tr("Starting node verification process..."))
 	fmt.Println()
 
 	// Step 1: Initialize verification and get node details
-	fmt.Println("Step 1/3: Fetching node details from API...")
-	nodeIP, nodePort, err := initVerification(challenge)
+	fmt.Println(tr("Step 1/3: Fetching node details from API..."))
+	initReq := buildInitRequest(challenge)
+	initReq.ReachabilityProof = *reachProof
+	var timings StepTimings
+	stepStart := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(apiTimeout))
+	initResp, err := initVerification(ctx, initReq)
+	cancel()
+	timings.InitMs = time.Since(stepStart).Milliseconds()
 	if err != nil {
-		log.Fatalf("❌ Failed to initialize verification: %v", err)
+		result.Error = err.Error()
+		report()
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			fatal(exitAPIUnreachable, "❌ Failed to initialize verification: %v", err)
+		}
+		if apiErr.challengeExitCode() != 0 {
+			handleChallengeError(apiErr)
+		}
+		log.Fatal(tr("❌ Failed to initialize verification: %v", err))
+	}
+
+	// Don't trust the API-provided address blindly in the later steps
+	node, warning, err := validateNodeAddress(initResp.Node)
+	if err != nil {
+		result.Error = err.Error()
+		report()
+		log.Fatalf("❌ API returned an unusable node address: %v", err)
+	}
+	initResp.Node = node
+	result.Node = node
+	nodeIP, nodePort := node.IP, node.Port
+	pinAPIFamily(nodeIP)
+	fmt.Print(tr("  ✅ Node IP: %s\n", nodeIP))
+	fmt.Print(tr("  ✅ Node Port: %d\n", nodePort))
+	if portOverride != 0 && int(portOverride) != nodePort {
+		fmt.Print(tr("  ℹ️  Checking port %d locally instead (--port); the map decides whether it accepts that\n", portOverride))
+		nodePort = int(portOverride)
 	}
-	fmt.Printf("  ✅ Node IP: %s\n", nodeIP)
-	fmt.Printf("  ✅ Node Port: %d\n", nodePort)
+	if warning != "" {
+		fmt.Printf("  ⚠️  %s\n", warning)
+	}
+	if *encryptPayload {
+		key, pinned, err := acceptPayloadOffer(initResp.PayloadEncryption, PayloadKey)
+		if err != nil {
+			result.Error = err.Error()
+			report()
+			log.Fatalf("❌ Cannot encrypt the payload: %v", err)
+		}
+		sealTo = key
+		if pinned {
+			fmt.Println("  ✅ Results will be encrypted to the map's pinned key")
+		} else {
+			fmt.Println("  ⚠️  Results will be encrypted, but this build has no pinned key to check")
+			fmt.Println("     the map's key against; a proxy that reads TLS could have swapped it")
+		}
+	}
+
+	// Optional: accept the backend's inbound probe while the checks run
+	var reachDone <-chan *ReachabilityResult
+	if initResp.ReachabilityProbe != nil {
+		probe := initResp.ReachabilityProbe
+		fmt.Printf("  Listening on port %d for the map's reachability probe...\n", probe.Port)
+		reachDone = serveReachabilityProbe(challenge, *probe)
+	} else if *reachProof {
+		fmt.Println("  ⚠️  The API did not request a reachability probe, skipping")
+	}
+	printStepTime(time.Since(stepStart))
 	fmt.Println()
 
+	// Optional: reverse challenge via the daemon's user agent. The daemon
+	// must be restarted with the token before the local checks run.
+	if *uaComment {
+		printUserAgentGuidance(userAgentToken(challenge))
+	}
+
 	// Step 2: Check local node process and port
-	fmt.Println("Step 2/3: Checking local node process and port...")
+	fmt.Println(tr("Step 2/3: Checking local node process and port..."))
+	stepStart = time.Now()
 
 	// Check process
-	processFound, processMethod, daemonName := checkProcess()
+	ctx, cancel = checkContext()
+	processFound, processMethod, daemonName, processEvidence := checkProcess(ctx, nodePort)
+	warnCheckTimeout(ctx, "process")
+	cancel()
+	timings.ProcessCheckMs = time.Since(stepStart).Milliseconds()
 	if processFound {
-		fmt.Printf("  ✅ Found daemon: %s (method: %s)\n", daemonName, processMethod)
+		fmt.Print(tr("  ✅ Found daemon: %s (method: %s)\n", daemonName, processMethod))
+		printSystemdUnit(processEvidence)
+		printPackage(processEvidence)
 	} else {
-		fmt.Printf("  ❌ No node daemon found. Expected: %s\n", DaemonNames)
+		fmt.Print(tr("  ❌ No node daemon found. Expected: %s\n", DaemonNames))
+	}
+
+	// Follow the log from here on, skipping lines from before the daemon
+	// (re)started
+	logPath := *daemonLog
+	if logPath == "" {
+		logPath = filepath.Join(dataDir, "debug.log")
+	}
+	var stopLog chan struct{}
+	var logDone <-chan struct{}
+	if *followLog {
+		stopLog = make(chan struct{})
+		logDone = followDaemonLog(logPath, processEvidence.started(), os.Stdout, stopLog)
 	}
 
 	// Check port (use the port from API, not hardcoded default)
-	portListening, portMethod := checkPort(nodePort)
+	portStart := time.Now()
+	ctx, cancel = checkContext()
+	portListening, portMethod := checkNodePort(ctx, daemonName, nodePort)
+	warnCheckTimeout(ctx, "port")
+	cancel()
+	timings.PortCheckMs = time.Since(portStart).Milliseconds()
 	if portListening {
-		fmt.Printf("  ✅ Port %d is listening (method: %s)\n", nodePort, portMethod)
+		fmt.Print(tr("  ✅ Port %d is listening (method: %s)\n", nodePort, portMethod))
+	} else {
+		fmt.Print(tr("  ❌ Port %d is not listening\n", nodePort))
+	}
+
+	reqBody := buildConfirmRequest(challenge, processFound, processMethod, daemonName, portListening, portMethod, nodePort)
+	reqBody.ProcessCheck.Evidence = processEvidence
+
+	// Tie the listening socket to the daemon, so another program on the
+	// port doesn't pass for it
+	if portListening {
+		ctx, cancel = checkContext()
+		owner := socketOwner(ctx, nodePort, daemonName, processEvidence)
+		cancel()
+		if owner != nil && !owner.IsDaemon {
+			fmt.Print(tr("  ⚠️  Port %d belongs to %s (PID %d), not the daemon\n", nodePort, owner.Name, owner.PID))
+		}
+		reqBody.PortCheck.Owner = owner
+
+		// A daemon bound to 127.0.0.1 passes the check above, but the map
+		// can't reach it. The map reaches the node over the family of its
+		// address.
+		ctx, cancel = checkContext()
+		addrs, _ := listenAddrs(ctx, daemonName, nodePort)
+		families := listenFamilies(ctx, addrs, nodePort)
+		cancel()
+		if localOnly(addrs) {
+			fmt.Print(tr("  ⚠️  Port %d only listens on %s, which other hosts can't reach; check bind= in %s\n", nodePort, strings.Join(addrs, ", "), daemonConfPath()))
+		}
+		want := "ipv4"
+		if net.ParseIP(nodeIP).To4() == nil {
+			want = "ipv6"
+		}
+		if len(families) > 0 && !slices.Contains(families, want) {
+			fmt.Print(tr("  ⚠️  Port %d doesn't listen on %s, but the map knows the node as %s\n", nodePort, strings.Replace(want, "ip", "IP", 1), nodeIP))
+		}
+		reqBody.PortCheck.BindAddresses = addrs
+		reqBody.PortCheck.Families = families
+	}
+
+	// The RPC port too, so the map can tell a fully running node from one
+	// whose RPC server is down. RPC is only checked on this host.
+	reqBody.Ports = []PortCheck{reqBody.PortCheck}
+	reqBody.Ports[0].Role = "p2p"
+	if rpcPort := localRPCPort(); rpcPort != 0 && rpcPort != nodePort {
+		ctx, cancel = checkContext()
+		rpcListening, rpcMethod := checkNodePort(ctx, daemonName, rpcPort)
+		cancel()
+		if rpcListening {
+			fmt.Print(tr("  ✅ RPC port %d is listening (method: %s)\n", rpcPort, rpcMethod))
+		} else {
+			fmt.Print(tr("  ⚠️  RPC port %d is not listening; the node is reported without RPC\n", rpcPort))
+		}
+		if rpcMethod == "" {
+			rpcMethod = "none"
+		}
+		reqBody.Ports = append(reqBody.Ports, PortCheck{Role: "rpc", Listening: rpcListening, Port: rpcPort, Method: rpcMethod})
+	}
+
+	// Coarse hosting label for the map's decentralization statistics
+	if !*noProvider {
+		provider, environment := detectEnvironment()
+		reqBody.SystemInfo.Provider = provider
+		reqBody.SystemInfo.Environment = environment
+		fmt.Printf("  ℹ️  Hosting: %s (%s), opt out with --no-provider\n", provider, environment)
+
+		// A containerized daemon is reported with its container and the
+		// ports it publishes
+		if processEvidence != nil {
+			if container := daemonContainer(context.Background(), processEvidence.PID); container != nil {
+				reqBody.ProcessCheck.Container = container
+				printContainer(container, nodePort)
+			}
+		}
+	}
+
+	// A full or read-only disk stops the node; warn while there is time
+	disk, diskOK := checkDiskHealth(dataDir)
+	if diskOK {
+		printDiskHealth(disk)
+		if *shareDisk {
+			reqBody.SystemInfo.DiskFreeGB = math.Round(disk.FreeGB*10) / 10
+		}
+	}
+	// Site-specific evidence from the operator's own scripts
+	if len(scripts) > 0 {
+		reqBody.CustomChecks = runCheckScripts(scripts, time.Duration(checkTimeout))
+	}
+	result.setChecks(reqBody)
+	result.Disk = disk
+
+	// Refuse to verify a testnet/regtest daemon against the mainnet map.
+	// The config decides even when RPC is unavailable.
+	conf, _ := readDaemonConf(daemonConfPath())
+	if chain := confChain(conf); chain != expectedChain {
+		fmt.Printf("  ❌ %s selects the %q chain, but this tool verifies %s %q nodes\n", daemonConfPath(), chain, ChainName, expectedChain)
+		fmt.Println("     Point the tool at your mainnet node (see --datadir) and try again.")
+		result.Error = fmt.Sprintf("daemon config selects the %q chain", chain)
+		report()
+		os.Exit(exitChainMismatch)
+	}
+	var chainInfo BlockchainInfo
+	rpcErr := rpcCall("getblockchaininfo", &chainInfo)
+	if rpcErr != nil {
+		fmt.Printf("  ℹ️  Chain check skipped: %v\n", rpcErr)
+	} else if chainInfo.Chain != expectedChain {
+		fmt.Printf("  ❌ Daemon is on the %q chain, but this tool verifies %s %q nodes\n", chainInfo.Chain, ChainName, expectedChain)
+		fmt.Println("     Point the tool at your mainnet node (see --datadir) and try again.")
+		result.Error = fmt.Sprintf("daemon is on the %q chain", chainInfo.Chain)
+		report()
+		os.Exit(exitChainMismatch)
+	} else {
+		fmt.Printf("  ✅ Daemon chain: %s (height %d)\n", chainInfo.Chain, chainInfo.Blocks)
+		if tip, err := checkTip(chainInfo); err != nil {
+			fmt.Printf("  ℹ️  Tip check skipped: %v\n", err)
+		} else {
+			printTip(tip)
+			result.Tip = &tip
+		}
+	}
+	// Compare with the node's own baseline to catch a degrading host
+	if stateDir(); stateRelocated {
+		fmt.Printf("  ℹ️  %s is read-only, keeping state in %s\n", configDir(), stateDir())
+	}
+	if perf, err := checkPerformance(logPath, rpcErr == nil, *resetBaseline); err != nil {
+		fmt.Printf("  ℹ️  Performance check skipped: %v\n", err)
 	} else {
-		fmt.Printf("  ❌ Port %d is not listening\n", nodePort)
+		printPerformance(perf)
+		result.Performance = perf
 	}
+	if stopLog != nil {
+		close(stopLog)
+		<-logDone
+	}
+	timings.ChecksMs = time.Since(stepStart).Milliseconds()
+	printStepTime(time.Since(stepStart))
 	fmt.Println()
 
+	// Process and port checks disagree: gather more evidence before submitting
+	if processFound != portListening {
+		fmt.Println("  Checks disagree, gathering more evidence...")
+		reqBody.Escalation = escalateChecks(nodePort)
+		printEscalation(reqBody.Escalation)
+		result.Escalation = reqBody.Escalation
+		fmt.Println()
+	}
+
+	if reachDone != nil {
+		outcome := <-reachDone
+		if outcome.Probed {
+			fmt.Printf("  ✅ Reachability probe received from %s\n", outcome.RemoteAddr)
+		} else {
+			fmt.Printf("  ❌ No reachability probe received on port %d\n", outcome.Port)
+		}
+		fmt.Println()
+		reqBody.ReachabilityProof = outcome
+		result.Reachability = outcome
+	}
+
+	if *uaComment {
+		reqBody.UserAgentCheck = &UserAgentCheck{
+			Token:  userAgentToken(challenge),
+			Method: "uacomment",
+		}
+	}
+
+	// Strict mode: the process and port heuristics alone don't count, at
+	// least one cryptographic proof must hold up
+	if *strict {
+		evidence, problems := gatherStrictEvidence(challenge, chainInfo, rpcErr, *signAddress, *uaComment)
+		printStrictEvidence(evidence, problems)
+		fmt.Println()
+		reqBody.Strict = evidence
+		result.Strict = evidence
+		if len(evidence.methods()) == 0 {
+			result.Error = "strict mode found no cryptographic evidence"
+			report()
+			fatal(1, "❌ --strict needs RPC access to the daemon, --sign-address or --uacomment")
+		}
+	}
+
 	// Step 3: Submit verification results
-	fmt.Println("Step 3/3: Submitting verification to API...")
-	if err := confirmVerification(challenge, processFound, processMethod, daemonName, portListening, portMethod, nodePort); err != nil {
-		log.Fatalf("❌ Failed to submit verification: %v", err)
+	reqBody.AcceptsPolling = true
+	reqBody.Timings = &timings
+	result.Timings = &timings
+	if *dryRun {
+		fmt.Println(tr("Step 3/3: Dry run, not submitting"))
+		if err := printDryRun(reqBody); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		result.DryRun = true
+		report()
+		os.Exit(recheckExitCode(processFound, portListening))
+	}
+	fmt.Println(tr("Step 3/3: Submitting verification to API..."))
+	stepStart = time.Now()
+	ctx, cancel = context.WithTimeout(context.Background(), time.Duration(apiTimeout))
+	confirmResp, err := confirmVerification(ctx, reqBody)
+	cancel()
+	timings.ConfirmMs = time.Since(stepStart).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		report()
+		var apiErr *APIError
+		switch {
+		case !errors.As(err, &apiErr):
+			fatal(exitAPIUnreachable, "❌ Failed to submit verification: %v", err)
+		case !processFound:
+			fatal(exitDaemonNotFound, "❌ Failed to submit verification: %v", err)
+		case !portListening:
+			fatal(exitPortNotListening, "❌ Failed to submit verification: %v", err)
+		}
+		log.Fatal(tr("❌ Failed to submit verification: %v", err))
 	}
+	printStepTime(time.Since(stepStart))
+	result.Submitted = true
+	result.Status = confirmResp.Status
+	result.Message = confirmResp.Message
+	result.AddrRelay = confirmResp.AddrRelay
+	result.Extra = confirmResp.Extra
 
 	fmt.Println()
-	fmt.Println("✅ Verification submitted successfully!")
-	fmt.Println("   Your verification will be reviewed by an admin.")
+	fmt.Println(tr("✅ Verification submitted successfully!"))
+	fmt.Println(tr("   Your verification will be reviewed by an admin."))
+	printAddrRelay(confirmResp.AddrRelay)
+	if confirmResp.Strict != nil {
+		fmt.Printf("   Strict evidence accepted by the map: %s\n", strings.Join(confirmResp.Strict.Methods, ", "))
+	}
+	if confirmResp.Status == "pending_approval" {
+		fmt.Println(tr("   If the admins have follow-up questions, answer them with:"))
+		fmt.Printf("   %s questions %s\n", os.Args[0], challenge)
+	}
+	if confirmResp.NodeID != "" {
+		result.NodeURL = ApiUrl + "/node/" + url.PathEscape(confirmResp.NodeID)
+		fmt.Println()
+		fmt.Print(tr("   Your node on the map: %s\n", result.NodeURL))
+		if !*jsonOutput {
+			printNodeQR(result.NodeURL)
+		}
+	}
+	if confirmResp.Receipt != nil {
+		if path, err := saveReceipt(receiptsDir(), *confirmResp.Receipt, confirmResp.Status); err != nil {
+			fmt.Printf("   ⚠️  Could not save the receipt: %v\n", err)
+		} else {
+			result.ReceiptFile = path
+			fmt.Print(tr("   Receipt saved to %s\n", path))
+		}
+	}
 	fmt.Println()
+
+	report()
+}
+
+// printDryRun shows the confirm request a real run would send. The
+// challenge stays unused, so the real run can follow with the same one.
+func printDryRun(reqBody ConfirmRequest) error {
+	jsonData, err := json.MarshalIndent(reqBody, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	fmt.Printf("  This would be sent to %s/api/verify-node/confirm", ApiUrl)
+	if sealTo != nil {
+		fmt.Print(", encrypted to the map's key")
+	}
+	fmt.Println(":")
+	fmt.Println()
+	fmt.Println(string(jsonData))
+	fmt.Println()
+	fmt.Println("  Nothing was submitted. Run again without --dry-run to verify.")
+	return nil
+}
+
+// printNodeQR shows a QR code of the node page to open it on a phone.
+// Only on a terminal: plain output can't draw the blocks, and logs don't
+// need them.
+func printNodeQR(nodeURL string) {
+	if quiet || isPlainChild() || !isTerminal(os.Stdout) {
+		return
+	}
+	code, err := qrEncode([]byte(nodeURL))
+	if err != nil {
+		return
+	}
+	fmt.Println()
+	renderQR(os.Stdout, code, 2)
+}
+
+// printAddrRelay explains an address relay probe that found the node
+// reachable but not sharing peers. It doesn't affect the verification.
+func printAddrRelay(r *AddrRelayResult) {
+	if r == nil || r.Relays {
+		return
+	}
+	fmt.Println()
+	if !r.Success {
+		fmt.Printf("⚠️  Could not check address relay: %s\n", r.Error)
+		return
+	}
+	fmt.Printf("⚠️  Your node answered getaddr with %d address(es).\n", r.AddressCount)
+	fmt.Println("   It is reachable but doesn't share peers, so it won't count as relaying")
+	fmt.Println("   addresses on the map. Check that the daemon doesn't run with")
+	fmt.Println("   -blocksonly, -connect or -listen=0.")
 }
 
 func printBanner() {
@@ -168,16 +984,29 @@ func printBanner() {
 }
 
 func printUsage() {
-	fmt.Println("Usage:")
-	fmt.Printf("  %s <challenge-token>\n\n", os.Args[0])
-	fmt.Println("Example:")
-	fmt.Printf("  %s abc123xyz456def789\n\n", os.Args[0])
+	verify, _ := lookupCommand("verify")
+	writeCommandHelp(os.Stdout, verify, os.Args[0])
+	fmt.Println()
 	fmt.Println("Description:")
 	fmt.Printf("  Verifies %s node ownership by checking:\n", ChainName)
 	fmt.Printf("  - Node daemon process is running (%s)\n", DaemonNames)
-	fmt.Printf("  - Node port is listening (port %s)\n", DefaultPort)
+	fmt.Printf("  - Node port is listening (port %s)\n", defaultPort.String())
 	fmt.Println("  - Request originates from node's IP address")
 	fmt.Println()
+	fmt.Println("Other commands:")
+	for _, c := range commands {
+		if c.Name != "verify" && !c.Hidden {
+			fmt.Printf("  %s %s %s\n", os.Args[0], padRight(c.Name, 12), c.Summary)
+		}
+	}
+	fmt.Printf("  Run %s help <command> for its options, examples and exit codes.\n", os.Args[0])
+	fmt.Println()
+	fmt.Println("Environment (overrides --config and the build):")
+	fmt.Println("  DINGO_VERIFY_API_URL  Map API base URL")
+	fmt.Println("  DINGO_VERIFY_PORT     Node P2P port")
+	fmt.Println("  DINGO_VERIFY_DAEMONS  Daemon process names, comma-separated")
+	fmt.Println("  NO_COLOR              Plain output, as with --no-color")
+	fmt.Println()
 	fmt.Println("IMPORTANT: Run this command on your node server,")
 	fmt.Println("           not on your local computer!")
 	fmt.Println()
@@ -198,78 +1027,268 @@ func isValidChallenge(s string) bool {
 	return match
 }
 
-func initVerification(challenge string) (string, int, error) {
+// buildInitRequest assembles the init payload for a challenge
+func buildInitRequest(challenge string) InitRequest {
 	// Get hostname
 	hostname, _ := os.Hostname()
 
-	// Prepare request
-	reqBody := InitRequest{
+	return InitRequest{
 		Challenge: challenge,
 		Hostname:  hostname,
 	}
+}
 
+func initVerification(ctx context.Context, reqBody InitRequest) (*InitResponse, error) {
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	// Make API request
 	url := ApiUrl + "/api/verify-node/init"
-	client := httpClient
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	stop := startSpinner("waiting for the API")
+	resp, err := httpClient.Do(req)
+	stop()
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to connect to API: %w", err)
+		return nil, fmt.Errorf("failed to connect to API: %w", explainTLSError(err))
 	}
 	defer resp.Body.Close()
 
 	// Read response
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	// Parse response
 	var initResp InitResponse
-	if err := json.Unmarshal(body, &initResp); err != nil {
-		return "", 0, fmt.Errorf("failed to parse response: %w", err)
+	initResp.Extra, err = decodeResponse(body, &initResp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	if !initResp.Success {
-		return "", 0, fmt.Errorf("API error: %s", initResp.Error)
+		return nil, &APIError{
+			Status:  resp.StatusCode,
+			Code:    initResp.Code,
+			Message: initResp.Error,
+		}
 	}
 
-	return initResp.Node.IP, initResp.Node.Port, nil
+	return &initResp, nil
 }
 
-func checkProcess() (bool, string, string) {
-	daemons := strings.Split(DaemonNames, ",")
+// Special-purpose ranges (RFC 6890 and friends) that net.IP's helpers do not
+// cover: shared CGNAT space, documentation and benchmarking networks
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001:2::/48"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
 
-	for _, daemon := range daemons {
-		daemon = strings.TrimSpace(daemon)
+// isReservedIP reports whether ip falls in one of reservedPrefixes
+func isReservedIP(ip net.IP) bool {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateNodeAddress checks the node address returned by init and
+// normalizes the IP (brackets and IPv4-mapped forms). Private or reserved
+// addresses are returned with a warning, as they usually mean the backend
+// is misconfigured (e.g. the crawler running with development settings).
+func validateNodeAddress(node NodeAddress) (NodeAddress, string, error) {
+	ip := net.ParseIP(strings.Trim(node.IP, "[]"))
+	if ip == nil {
+		return node, "", fmt.Errorf("%q is not an IP address", node.IP)
+	}
+	if node.Port < 1 || node.Port > 65535 {
+		return node, "", fmt.Errorf("port %d is out of range", node.Port)
+	}
+
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	node.IP = ip.String()
+
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || isReservedIP(ip) {
+		return node, fmt.Sprintf("Node IP %s is not a public address. The map's backend may be misconfigured; "+
+			"verification will likely fail. Please report this to the map operators.", node.IP), nil
+	}
+	return node, "", nil
+}
+
+// handleChallengeError explains why the challenge was rejected, offers to
+// open the website to create a new one, and exits with a distinct code
+func handleChallengeError(apiErr *APIError) {
+	code := apiErr.challengeExitCode()
+
+	switch code {
+	case exitChallengeRejected:
+		fmt.Println(tr("❌ Challenge not found."))
+		fmt.Println(tr("   Check that you copied the full challenge from the website."))
+	case exitChallengeExpired:
+		fmt.Println(tr("❌ Challenge has expired."))
+		fmt.Println(tr("   Challenges are only valid for a limited time. Generate a new one."))
+	case exitChallengeUsed:
+		fmt.Println(tr("❌ Challenge can no longer be used."))
+	}
+	if apiErr.Message != "" {
+		fmt.Printf("   %s\n", apiErr.Message)
+	}
+	fmt.Println()
+
+	url := ApiUrl + "/my-nodes"
+	fmt.Print(tr("   Start a new verification at: %s\n", url))
+	if isTerminal(os.Stdin) && !quiet {
+		fmt.Print(tr("   Open it in your browser now? [y/N] "))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(answer)), "y") {
+			if err := openBrowser(url); err != nil {
+				fmt.Printf("   ⚠️  Could not open a browser: %v\n", err)
+			}
+		}
+	}
+
+	os.Exit(code)
+}
+
+// fatal logs like log.Fatalf but exits with code
+func fatal(code int, format string, args ...any) {
+	log.Print(tr(format, args...))
+	os.Exit(code)
+}
+
+func isTerminal(f *os.File) bool {
+	if isPlainChild() && plainTerminal(f.Fd()) {
+		return true
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	case "darwin":
+		cmd = exec.Command("open", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+// checkContext bounds one local check by --check-timeout. Commands still
+// running when it expires are killed, so a hung netstat can't stall the run.
+func checkContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), time.Duration(checkTimeout))
+}
+
+// warnCheckTimeout tells when a check was cut short by --check-timeout
+func warnCheckTimeout(ctx context.Context, check string) {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		fmt.Printf("  ⚠️  The %s check took longer than --check-timeout (%s) and was stopped\n", check, &checkTimeout)
+	}
+}
+
+// checkProcess looks for the first configured daemon that is running.
+// A detection hit only counts once validateDaemonProcess confirms it is the
+// daemon itself, not a grep or editor mentioning the name; otherwise the
+// remaining daemon names are still tried. port is the node's P2P port, used
+// to pick the right process when several daemons run.
+func checkProcess(ctx context.Context, port int) (bool, string, string, *ProcessEvidence) {
+	tools := capabilities().Tools
+
+	for _, daemon := range daemonNames(ctx) {
+		// A service unit names its main PID, which is matched exactly
+		found, method := false, ""
+		unit, mainPID := "", 0
+		if tools["systemctl"] {
+			if found, unit, mainPID = checkProcessSystemd(ctx, daemon); found {
+				method = "systemd"
+			}
+		}
+
+		// Ask the OS directly where supported (Windows Toolhelp snapshot,
+		// Linux /proc)
+		if !found {
+			found, method = checkProcessNative(daemon)
+		}
 
 		// Try ps command (most compatible)
-		if found, method := checkProcessPS(daemon); found {
-			return true, method, daemon
+		if !found && method == "" && tools["ps"] {
+			found, method = checkProcessPS(ctx, daemon)
 		}
 
 		// Try pidof (Linux)
-		if found, method := checkProcessPidof(daemon); found {
-			return true, method, daemon
+		if !found && method == "" && tools["pidof"] {
+			found, method = checkProcessPidof(ctx, daemon)
 		}
 
 		// Try pgrep (Unix-like)
-		if found, method := checkProcessPgrep(daemon); found {
-			return true, method, daemon
+		if !found && method == "" && tools["pgrep"] {
+			found, method = checkProcessPgrep(ctx, daemon)
 		}
+
+		if !found {
+			continue
+		}
+
+		evidence, ok := validateDaemonProcess(ctx, daemon, port)
+		if !ok {
+			fmt.Printf("  ⚠️  Only non-daemon processes mention %s (shell, grep, editor...)\n", daemon)
+			continue
+		}
+		if evidence != nil && evidence.PID == mainPID {
+			evidence.Unit = unit
+		}
+		if evidence == nil {
+			fmt.Printf("  ⚠️  Could not inspect the %s process (no ps), submitting without process evidence\n", daemon)
+		}
+		return true, method, daemon, evidence
 	}
 
-	return false, "", ""
+	// A Kubernetes sidecar can't see the daemon's container; check it over
+	// the pod network instead
+	if pod := detectPod(); pod != nil {
+		if found, method := checkProcessPod(); found {
+			fmt.Print(tr("  ℹ️  Running as a sidecar in pod %s, checked the daemon over the pod network\n", pod))
+			return true, method, primaryDaemon(), nil
+		}
+	}
+
+	return false, "", "", nil
 }
 
-func checkProcessPS(daemon string) (bool, string) {
-	cmd := exec.Command("ps", "aux")
-	output, err := cmd.Output()
+func checkProcessPS(ctx context.Context, daemon string) (bool, string) {
+	output, err := commandOutput(exec.CommandContext(ctx, "ps", "aux"))
 	if err != nil {
 		return false, ""
 	}
@@ -282,91 +1301,431 @@ func checkProcessPS(daemon string) (bool, string) {
 	return false, ""
 }
 
-func checkProcessPidof(daemon string) (bool, string) {
-	cmd := exec.Command("pidof", daemon)
-	err := cmd.Run()
-	if err == nil {
+func checkProcessPidof(ctx context.Context, daemon string) (bool, string) {
+	if _, err := commandOutput(exec.CommandContext(ctx, "pidof", daemon)); err == nil {
 		return true, "pidof"
 	}
 	return false, ""
 }
 
-func checkProcessPgrep(daemon string) (bool, string) {
-	cmd := exec.Command("pgrep", "-x", daemon)
-	err := cmd.Run()
-	if err == nil {
+func checkProcessPgrep(ctx context.Context, daemon string) (bool, string) {
+	if _, err := commandOutput(exec.CommandContext(ctx, "pgrep", "-x", daemon)); err == nil {
 		return true, "pgrep"
 	}
 	return false, ""
 }
 
-func checkPort(port int) (bool, string) {
+// checkNodePort checks the port, inside the daemon's network namespace
+// first when it runs containerized
+func checkNodePort(ctx context.Context, daemonName string, port int) (bool, string) {
+	if pid, ok := daemonNetNamespacePID(ctx, daemonName); ok {
+		fmt.Printf("  ℹ️  Daemon (PID %d) runs in a separate network namespace\n", pid)
+		if listening, method := checkPortInNamespace(ctx, pid, port); listening {
+			return true, method
+		}
+	}
+	return checkPort(ctx, port)
+}
+
+func checkPort(ctx context.Context, port int) (bool, string) {
+	// Ask the kernel directly where supported (Linux netlink / procfs,
+	// Windows IP Helper). A miss that still names a method is authoritative:
+	// on Windows spawning netstat is what trips AV/EDR heuristics.
+	if listening, method := checkPortNative(port); listening || method != "" {
+		return listening, method
+	}
+	tools := capabilities().Tools
+
+	// Try sockstat (FreeBSD, NetBSD, DragonFly)
+	if tools["sockstat"] {
+		if listening, method := checkPortSockstat(ctx, port); listening {
+			return true, method
+		}
+	}
+
 	// Try netstat (most compatible)
-	if listening, method := checkPortNetstat(port); listening {
-		return true, method
+	if tools["netstat"] {
+		if listening, method := checkPortNetstat(ctx, port); listening {
+			return true, method
+		}
 	}
 
 	// Try ss (modern Linux)
-	if listening, method := checkPortSS(port); listening {
-		return true, method
+	if tools["ss"] {
+		if listening, method := checkPortSS(ctx, port); listening {
+			return true, method
+		}
 	}
 
 	// Try lsof (macOS/BSD)
-	if listening, method := checkPortLsof(port); listening {
-		return true, method
+	if tools["lsof"] {
+		if listening, method := checkPortLsof(ctx, port); listening {
+			return true, method
+		}
 	}
 
-	return false, ""
+	// Connect to the port, which needs no tools at all (minimal images)
+	return checkPortDial(ctx, port)
 }
 
-func checkPortNetstat(port int) (bool, string) {
-	cmd := exec.Command("netstat", "-an")
-	output, err := cmd.Output()
+// checkPortDial connects to the port on loopback, then on the host's other
+// addresses for a daemon bound to one of them. A connection shows that
+// something listens, not what, so the socket tables come first.
+func checkPortDial(ctx context.Context, port int) (bool, string) {
+	ips := []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback}
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		ips = append(ips, externalIPs(addrs)...)
+	}
+	if firstAccepting(ctx, ips, port) != "" {
+		return true, "dial"
+	}
+	return false, "dial"
+}
+
+// listenAddrs returns the addresses the sockets listening on port are
+// bound to, e.g. 0.0.0.0, :: or 127.0.0.1, and "*" where a tool doesn't
+// tell the family. They come from the socket tables, else from sockstat,
+// netstat, ss or lsof; ok is false when none of them shows the port.
+func listenAddrs(ctx context.Context, daemonName string, port int) (addrs []string, ok bool) {
+	if pid, inNamespace := daemonNetNamespacePID(ctx, daemonName); inNamespace {
+		addrs, ok = namespaceListenAddrs(pid, port)
+	} else if addrs, ok = nativeListenAddrs(port); !ok {
+		tools := capabilities().Tools
+		for _, t := range []struct {
+			tool  string
+			args  []string
+			parse func(string, int) []string
+		}{
+			{"sockstat", sockstatArgs, sockstatListenAddrs},
+			{"netstat", []string{"-an"}, netstatListenAddrs},
+			{"ss", []string{"-lnt"}, ssListenAddrs},
+			{"lsof", []string{"-nP", fmt.Sprintf("-iTCP:%d", port)}, lsofListenAddrs},
+		} {
+			if !tools[t.tool] {
+				continue
+			}
+			// lsof's exit code doesn't tell (see checkPortLsof)
+			output, _ := commandOutput(exec.CommandContext(ctx, t.tool, t.args...))
+			if addrs = t.parse(string(output), port); len(addrs) > 0 {
+				ok = true
+				break
+			}
+		}
+	}
+	slices.Sort(addrs)
+	return slices.Compact(addrs), ok
+}
+
+// localOnly reports whether every address is a loopback address, i.e. no
+// other host can connect
+func localOnly(addrs []string) bool {
+	for _, addr := range addrs {
+		if ip := net.ParseIP(addr); ip == nil || !ip.IsLoopback() {
+			return false
+		}
+	}
+	return len(addrs) > 0
+}
+
+// listenFamilies tells which address families ("ipv4", "ipv6") the node
+// listens on: from its listening addresses where they tell, otherwise by
+// connecting over each family
+func listenFamilies(ctx context.Context, addrs []string, port int) []string {
+	var v4, v6 bool
+	known := len(addrs) > 0
+	for _, addr := range addrs {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			known = false
+			break
+		}
+		v4 = v4 || ip.To4() != nil
+		v6 = v6 || ip.To4() == nil
+	}
+	if known {
+		return familyList(v4, v6)
+	}
+
+	v4s, v6s := []net.IP{net.IPv4(127, 0, 0, 1)}, []net.IP{net.IPv6loopback}
+	if ifAddrs, err := net.InterfaceAddrs(); err == nil {
+		for _, ip := range externalIPs(ifAddrs) {
+			if ip.To4() != nil {
+				v4s = append(v4s, ip)
+			} else {
+				v6s = append(v6s, ip)
+			}
+		}
+	}
+	return familyList(firstAccepting(ctx, v4s, port) != "", firstAccepting(ctx, v6s, port) != "")
+}
+
+// familyList names the families that are set
+func familyList(v4, v6 bool) []string {
+	var families []string
+	if v4 {
+		families = append(families, "ipv4")
+	}
+	if v6 {
+		families = append(families, "ipv6")
+	}
+	return families
+}
+
+func checkPortNetstat(ctx context.Context, port int) (bool, string) {
+	output, err := commandOutput(exec.CommandContext(ctx, "netstat", "-an"))
 	if err != nil {
 		return false, ""
 	}
 
-	// Look for port in LISTEN state
-	portStr := fmt.Sprintf(":%d", port)
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if strings.Contains(line, portStr) && strings.Contains(line, "LISTEN") {
-			return true, "netstat"
-		}
+	if netstatListening(string(output), port) {
+		return true, "netstat"
 	}
 
 	return false, ""
 }
 
-func checkPortSS(port int) (bool, string) {
-	cmd := exec.Command("ss", "-lntp")
-	output, err := cmd.Output()
+// netstatListening reports whether netstat -an output has a TCP socket in
+// LISTEN state on port
+func netstatListening(output string, port int) bool {
+	return len(netstatListenAddrs(output, port)) > 0
+}
+
+// netstatListenAddrs returns the hosts of the LISTEN sockets on port. The
+// local address is the field two before the state in every netstat flavour
+// (net-tools, BusyBox, BSD/macOS, Windows); BSD separates the port with a
+// dot instead of a colon.
+func netstatListenAddrs(output string, port int) []string {
+	var addrs []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasPrefix(strings.ToLower(fields[0]), "tcp") {
+			continue
+		}
+		for i := 2; i < len(fields); i++ {
+			if (fields[i] == "LISTEN" || fields[i] == "LISTENING") && addressHasPort(fields[i-2], port) {
+				addrs = append(addrs, listenHost(fields[i-2]))
+			}
+		}
+	}
+	return addrs
+}
+
+func checkPortSS(ctx context.Context, port int) (bool, string) {
+	output, err := commandOutput(exec.CommandContext(ctx, "ss", "-lntp"))
 	if err != nil {
 		return false, ""
 	}
 
-	// Look for port in LISTEN state
-	portStr := fmt.Sprintf(":%d", port)
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if strings.Contains(line, portStr) && strings.Contains(line, "LISTEN") {
-			return true, "ss"
-		}
+	if ssListening(string(output), port) {
+		return true, "ss"
 	}
 
 	return false, ""
 }
 
-func checkPortLsof(port int) (bool, string) {
-	cmd := exec.Command("lsof", "-i", fmt.Sprintf(":%d", port))
-	err := cmd.Run()
-	if err == nil {
+// ssListening reports whether ss -lnt output has a socket in LISTEN state on
+// port
+func ssListening(output string, port int) bool {
+	return len(ssListenAddrs(output, port)) > 0
+}
+
+// ssListenAddrs returns the hosts of the LISTEN sockets on port. The local
+// address follows the state and the two queue columns.
+func ssListenAddrs(output string, port int) []string {
+	var addrs []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		for i := 0; i+3 < len(fields); i++ {
+			if fields[i] == "LISTEN" && addressHasPort(fields[i+3], port) {
+				addrs = append(addrs, listenHost(fields[i+3]))
+			}
+		}
+	}
+	return addrs
+}
+
+func checkPortLsof(ctx context.Context, port int) (bool, string) {
+	// lsof exits 1 when it finds nothing and sometimes when it only lacks
+	// permission for other users' files, so the output decides
+	output, _ := commandOutput(exec.CommandContext(ctx, "lsof", "-nP", fmt.Sprintf("-iTCP:%d", port)))
+	if lsofListening(string(output), port) {
 		return true, "lsof"
 	}
 	return false, ""
 }
 
-func confirmVerification(challenge string, processFound bool, processMethod string, daemonName string, portListening bool, portMethod string, port int) error {
+// lsofListening reports whether lsof -nP -iTCP output has a socket in
+// LISTEN state on port
+func lsofListening(output string, port int) bool {
+	return len(lsofListenAddrs(output, port)) > 0
+}
+
+// lsofListenAddrs returns the hosts of the LISTEN sockets on port.
+// Connections to a peer's port of the same number show up too and are
+// ignored.
+func lsofListenAddrs(output string, port int) []string {
+	var addrs []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[len(fields)-1] != "(LISTEN)" {
+			continue
+		}
+		if addr := fields[len(fields)-2]; addressHasPort(addr, port) {
+			addrs = append(addrs, listenHost(addr))
+		}
+	}
+	return addrs
+}
+
+// sockstatArgs lists the listening TCP sockets of both families
+var sockstatArgs = []string{"-46", "-l", "-P", "tcp"}
+
+func checkPortSockstat(ctx context.Context, port int) (bool, string) {
+	output, err := commandOutput(exec.CommandContext(ctx, "sockstat", sockstatArgs...))
+	if err != nil {
+		return false, ""
+	}
+	if sockstatListening(string(output), port) {
+		return true, "sockstat"
+	}
+	return false, ""
+}
+
+// sockstatListening reports whether sockstat -46 -l -P tcp output has a
+// socket on port
+func sockstatListening(output string, port int) bool {
+	return len(sockstatListenAddrs(output, port)) > 0
+}
+
+// sockstatListenAddrs returns the hosts of the listening sockets on port
+func sockstatListenAddrs(output string, port int) []string {
+	var addrs []string
+	for _, s := range parseSockstat(output) {
+		if addressHasPort(s.local, port) {
+			addrs = append(addrs, listenHost(s.local))
+		}
+	}
+	return addrs
+}
+
+// sockstatOwner returns the process holding the listening socket on port
+func sockstatOwner(output string, port int) (pid int, name string, ok bool) {
+	for _, s := range parseSockstat(output) {
+		if s.pid > 0 && addressHasPort(s.local, port) {
+			return s.pid, s.command, true
+		}
+	}
+	return 0, "", false
+}
+
+type sockstatLine struct {
+	command string
+	pid     int
+	local   string
+}
+
+// parseSockstat reads the TCP lines of sockstat output: USER COMMAND PID FD
+// PROTO LOCAL FOREIGN. The columns are counted from the end, as a command
+// may contain spaces. Sockets whose owner sockstat can't see (other users'
+// without root, or the kernel's) show "?" and get PID 0.
+func parseSockstat(output string) []sockstatLine {
+	var lines []sockstatLine
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		n := len(fields)
+		if n < 7 || !strings.HasPrefix(fields[n-3], "tcp") {
+			continue
+		}
+		pid, _ := strconv.Atoi(fields[n-5])
+		lines = append(lines, sockstatLine{
+			command: strings.Join(fields[1:n-5], " "),
+			pid:     pid,
+			local:   fields[n-2],
+		})
+	}
+	return lines
+}
+
+// addressHasPort reports whether a local address as printed by netstat, ss,
+// lsof or sockstat (0.0.0.0:33117, [::]:33117, :::33117, *.33117, *:33117)
+// is on port
+func addressHasPort(addr string, port int) bool {
+	idx := strings.LastIndexAny(addr, ":.")
+	if idx < 0 {
+		return false
+	}
+	return addr[idx+1:] == strconv.Itoa(port)
+}
+
+// listenHost returns the host of a local address as printed by netstat, ss,
+// lsof or sockstat: an IP without brackets or zone, or "*" for any address
+// where the tool doesn't say which family
+func listenHost(addr string) string {
+	idx := strings.LastIndexAny(addr, ":.")
+	if idx < 0 {
+		return ""
+	}
+	host := strings.Trim(addr[:idx], "[]")
+	if zone := strings.IndexByte(host, '%'); zone >= 0 {
+		host = host[:zone]
+	}
+	return host
+}
+
+// userAgentToken derives the short token the daemon advertises for the
+// reverse challenge. Only characters allowed in -uacomment are used.
+func userAgentToken(challenge string) string {
+	sum := sha256.Sum256([]byte(challenge))
+	return userAgentTokenPrefix + hex.EncodeToString(sum[:6])
+}
+
+func printUserAgentGuidance(token string) {
+	daemon := primaryDaemon()
+	confPath := daemonConfPath()
+	stdin := bufio.NewReader(os.Stdin)
+
+	fmt.Println("Reverse challenge: advertise this token in your node's user agent")
+	fmt.Printf("  Token: %s\n", token)
+	fmt.Println()
+
+	edited := false
+	if isTerminal(os.Stdin) {
+		fmt.Printf("  Add uacomment=%s to %s now? [y/N] ", token, confPath)
+		answer, _ := stdin.ReadString('\n')
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(answer)), "y") {
+			backup, err := addUserAgentComment(confPath, token)
+			if err != nil {
+				fmt.Printf("  ⚠️  Could not update the config: %v\n", err)
+			} else {
+				edited = true
+				fmt.Printf("  ✅ Updated %s\n", confPath)
+				if backup != "" {
+					fmt.Printf("     Original saved as %s\n", backup)
+				}
+			}
+		}
+		fmt.Println()
+	}
+
+	if edited {
+		fmt.Println("  The daemon only reads its config at startup: restart it now")
+		fmt.Println("  (e.g. systemctl restart, or stop and start it by hand).")
+	} else {
+		fmt.Println("  Restart the daemon with:")
+		fmt.Printf("    %s -uacomment=%s\n", daemon, token)
+		fmt.Println("  or add this line to its config file and restart:")
+		fmt.Printf("    uacomment=%s\n", token)
+	}
+	fmt.Println()
+	fmt.Println("  The map will connect to your P2P port and look for the token.")
+	fmt.Println("  You can remove it again once verification is approved.")
+	fmt.Print("  Press Enter once the daemon has been restarted...")
+	stdin.ReadString('\n')
+	fmt.Println()
+}
+
+// buildConfirmRequest assembles the confirm payload from the local checks
+func buildConfirmRequest(challenge string, processFound bool, processMethod string, daemonName string, portListening bool, portMethod string, port int) ConfirmRequest {
 	// Get system info
 	hostname, _ := os.Hostname()
 
@@ -375,6 +1734,14 @@ func confirmVerification(challenge string, processFound bool, processMethod stri
 		Challenge: challenge,
 	}
 
+	// A check that found nothing has no method; the API expects "none"
+	if processMethod == "" {
+		processMethod = "none"
+	}
+	if portMethod == "" {
+		portMethod = "none"
+	}
+
 	reqBody.ProcessCheck.Found = processFound
 	reqBody.ProcessCheck.Method = processMethod
 	reqBody.ProcessCheck.DaemonName = daemonName
@@ -386,37 +1753,143 @@ func confirmVerification(challenge string, processFound bool, processMethod stri
 	reqBody.SystemInfo.Hostname = hostname
 	reqBody.SystemInfo.Platform = runtime.GOOS
 	reqBody.SystemInfo.Arch = runtime.GOARCH
+	reqBody.SystemInfo.Pod = detectPod()
 
+	return reqBody
+}
+
+func confirmVerification(ctx context.Context, reqBody ConfirmRequest) (*ConfirmResponse, error) {
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	if jsonData, err = sealedBody(jsonData); err != nil {
+		return nil, err
 	}
 
 	// Make API request
 	url := ApiUrl + "/api/verify-node/confirm"
-	client := httpClient
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	stop := startSpinner("waiting for the API")
+	resp, err := httpClient.Do(req)
+	stop()
 	if err != nil {
-		return fmt.Errorf("failed to connect to API: %w", err)
+		return nil, fmt.Errorf("failed to connect to API: %w", explainTLSError(err))
 	}
 	defer resp.Body.Close()
 
+	confirmResp, err := readConfirmResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusAccepted && confirmResp.PollToken != "" {
+		addRedaction(confirmResp.PollToken)
+		return pollConfirm(confirmResp.PollToken, confirmResp.Message)
+	}
+	return confirmResp, nil
+}
+
+// readConfirmResponse decodes a confirm or confirm poll response
+func readConfirmResponse(resp *http.Response) (*ConfirmResponse, error) {
 	// Read response
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	// Parse response
 	var confirmResp ConfirmResponse
-	if err := json.Unmarshal(body, &confirmResp); err != nil {
-		return fmt.Errorf("failed to parse response: %w", err)
+	confirmResp.Extra, err = decodeResponse(body, &confirmResp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	if !confirmResp.Success {
-		return fmt.Errorf("API error: %s", confirmResp.Error)
+		return nil, &APIError{
+			Status:  resp.StatusCode,
+			Code:    confirmResp.Code,
+			Message: confirmResp.Error,
+		}
 	}
 
-	return nil
+	return &confirmResp, nil
+}
+
+// Polling a confirm that the backend answered with 202 Accepted
+const (
+	confirmPollFirst = 2 * time.Second
+	confirmPollMax   = 15 * time.Second
+	confirmPollLimit = 5 * time.Minute
+)
+
+// confirmPollDelay is the wait before the next poll: the previous wait
+// grown by half, capped at confirmPollMax
+func confirmPollDelay(prev time.Duration) time.Duration {
+	if prev == 0 {
+		return confirmPollFirst
+	}
+	return min(prev*3/2, confirmPollMax)
+}
+
+// pollConfirm waits for the backend to finish its own checks of the node,
+// showing how long it has been waiting, and returns the final response
+func pollConfirm(token, message string) (*ConfirmResponse, error) {
+	if message == "" {
+		message = "The map is still checking your node."
+	}
+	start := time.Now()
+	var delay time.Duration
+
+	for {
+		delay = confirmPollDelay(delay)
+		time.Sleep(delay)
+
+		waited := time.Since(start).Round(time.Second)
+		if isTerminal(os.Stdout) {
+			fmt.Printf("\r   ⏳ %s (%s)", message, waited)
+		} else {
+			fmt.Printf("   ⏳ %s (%s)\n", message, waited)
+		}
+
+		resp, err := httpClient.Get(ApiUrl + "/api/verify-node/confirm/" + token)
+		if err != nil {
+			err = fmt.Errorf("failed to connect to API: %w", explainTLSError(err))
+		} else {
+			var confirmResp *ConfirmResponse
+			confirmResp, err = readConfirmResponse(resp)
+			resp.Body.Close()
+			if err == nil && resp.StatusCode != http.StatusAccepted {
+				if isTerminal(os.Stdout) {
+					fmt.Println()
+				}
+				return confirmResp, nil
+			}
+			if err == nil && confirmResp.Message != "" {
+				message = confirmResp.Message
+			}
+		}
+		if err != nil {
+			// Rate limits, gateway errors and network blips are retried;
+			// a definite answer (unknown token, failed checks) is not
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.Status != http.StatusTooManyRequests && apiErr.Status < http.StatusBadGateway {
+				if isTerminal(os.Stdout) {
+					fmt.Println()
+				}
+				return nil, err
+			}
+		}
+
+		if time.Since(start) > confirmPollLimit {
+			if isTerminal(os.Stdout) {
+				fmt.Println()
+			}
+			return nil, fmt.Errorf("the map did not finish checking your node within %s; the submission may still complete, check its status on the map", confirmPollLimit)
+		}
+	}
 }
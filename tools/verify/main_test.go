@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildConfirmRequestFailedChecks(t *testing.T) {
+	reqBody := buildConfirmRequest("challenge", false, "", "", false, "", 33117)
+
+	data, err := json.Marshal(reqBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sent struct {
+		ProcessCheck struct{ Method string } `json:"processCheck"`
+		PortCheck    struct{ Method string } `json:"portCheck"`
+	}
+	if err := json.Unmarshal(data, &sent); err != nil {
+		t.Fatal(err)
+	}
+	if sent.ProcessCheck.Method != "none" || sent.PortCheck.Method != "none" {
+		t.Errorf("failed checks sent methods %q/%q, want none/none", sent.ProcessCheck.Method, sent.PortCheck.Method)
+	}
+}
+
+// The confirm schema accepts methods it does not list so older deployments
+// keep working, but every method this build sends should be a known one
+func TestConfirmMethodsAcceptedBySchema(t *testing.T) {
+	schema, err := os.ReadFile("../../apps/web/src/lib/validations.ts")
+	if err != nil {
+		t.Skip("backend sources not available")
+	}
+	methodList := regexp.MustCompile(`method: checkMethod\(\[([^\]]*)\]\)`)
+	enum := func(object string) string {
+		i := strings.Index(string(schema), object)
+		if i < 0 {
+			t.Fatalf("%s not found in the confirm schema", object)
+		}
+		m := methodList.FindStringSubmatch(string(schema[i:]))
+		if m == nil {
+			t.Fatalf("%s method list not found", object)
+		}
+		return m[1]
+	}
+
+	tests := []struct {
+		check   string
+		enum    string
+		methods []string
+	}{
+		{"processCheck", enum("processCheck: z.object"), []string{"systemd", "ps", "pidof", "pgrep", "proc", "toolhelp", "manual", "none"}},
+		{"portCheck", enum("portCheckSchema = z.object"), []string{"netstat", "ss", "lsof", "netlink", "proc", "netns:/proc", "netns:nsenter", "iphlpapi", "dial", "manual", "none"}},
+	}
+
+	for _, tt := range tests {
+		for _, method := range tt.methods {
+			if !strings.Contains(tt.enum, "'"+method+"'") {
+				t.Errorf("%s.method %q is not listed in the confirm schema", tt.check, method)
+			}
+		}
+	}
+}
+
+func TestCheckPortDial(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ctx := context.Background()
+
+	if listening, method := checkPortDial(ctx, port); !listening || method != "dial" {
+		t.Errorf("open port: %v, %q", listening, method)
+	}
+	ln.Close()
+	if listening, method := checkPortDial(ctx, port); listening || method != "dial" {
+		t.Errorf("closed port: %v, %q", listening, method)
+	}
+}
+
+func TestIsReservedIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"0.1.2.3", true},
+		{"100.64.0.1", true},
+		{"100.127.255.254", true},
+		{"100.128.0.1", false},
+		{"192.0.0.8", true},
+		{"192.0.2.1", true},
+		{"198.18.0.1", true},
+		{"198.19.255.255", true},
+		{"198.51.100.7", true},
+		{"203.0.113.9", true},
+		{"240.0.0.1", true},
+		{"255.255.255.254", true},
+		{"100::1", true},
+		{"2001:2::1", true},
+		{"2001:db8::1", true},
+		{"::ffff:100.64.0.1", true},
+		{"::ffff:198.51.100.7", true},
+		{"8.8.8.8", false},
+		{"::ffff:8.8.8.8", false},
+		{"2a01:4f8::1", false},
+	}
+
+	for _, tt := range tests {
+		if got := isReservedIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("isReservedIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestValidateNodeAddress(t *testing.T) {
+	tests := []struct {
+		ip       string
+		port     int
+		wantIP   string
+		wantWarn bool
+		wantErr  bool
+	}{
+		{"8.8.8.8", 33117, "8.8.8.8", false, false},
+		{"[2a01:4f8::1]", 33117, "2a01:4f8::1", false, false},
+		{"2a01:4f8::1", 33117, "2a01:4f8::1", false, false},
+		{"::ffff:8.8.8.8", 33117, "8.8.8.8", false, false},
+		{"[::ffff:8.8.8.8]", 33117, "8.8.8.8", false, false},
+		{"10.0.0.5", 33117, "10.0.0.5", true, false},
+		{"127.0.0.1", 33117, "127.0.0.1", true, false},
+		{"100.64.1.1", 33117, "100.64.1.1", true, false},
+		{"203.0.113.9", 33117, "203.0.113.9", true, false},
+		{"[2001:db8::1]", 33117, "2001:db8::1", true, false},
+		{"::ffff:192.0.2.1", 33117, "192.0.2.1", true, false},
+		{"fe80::1", 33117, "fe80::1", true, false},
+		{"not-an-ip", 33117, "", false, true},
+		{"8.8.8.8", 0, "", false, true},
+		{"8.8.8.8", -1, "", false, true},
+		{"8.8.8.8", 65536, "", false, true},
+		{"8.8.8.8", 65535, "8.8.8.8", false, false},
+	}
+
+	for _, tt := range tests {
+		node, warning, err := validateNodeAddress(NodeAddress{IP: tt.ip, Port: tt.port})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateNodeAddress(%s, %d) error = %v, wantErr %v", tt.ip, tt.port, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if node.IP != tt.wantIP {
+			t.Errorf("validateNodeAddress(%s) IP = %s, want %s", tt.ip, node.IP, tt.wantIP)
+		}
+		if (warning != "") != tt.wantWarn {
+			t.Errorf("validateNodeAddress(%s) warning = %q, wantWarn %v", tt.ip, warning, tt.wantWarn)
+		}
+	}
+}
+
+func TestConfirmResponseAddrRelay(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *AddrRelayResult
+	}{
+		{"absent", `{"success":true,"status":"pending_approval"}`, nil},
+		{"relays", `{"success":true,"addrRelay":{"success":true,"addressCount":250,"relays":true}}`, &AddrRelayResult{Success: true, AddressCount: 250, Relays: true}},
+		{"silent", `{"success":true,"addrRelay":{"success":true,"addressCount":1,"relays":false}}`, &AddrRelayResult{Success: true, AddressCount: 1}},
+		{"failed", `{"success":true,"addrRelay":{"success":false,"addressCount":0,"relays":false,"error":"Connection timeout"}}`, &AddrRelayResult{Error: "Connection timeout"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp ConfirmResponse
+			if err := json.Unmarshal([]byte(tt.body), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if (resp.AddrRelay == nil) != (tt.want == nil) || (tt.want != nil && *resp.AddrRelay != *tt.want) {
+				t.Errorf("AddrRelay = %+v, want %+v", resp.AddrRelay, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfirmPollDelay(t *testing.T) {
+	want := []time.Duration{
+		2 * time.Second,
+		3 * time.Second,
+		4500 * time.Millisecond,
+		6750 * time.Millisecond,
+		10125 * time.Millisecond,
+		15 * time.Second,
+		15 * time.Second,
+	}
+
+	var delay time.Duration
+	for i, w := range want {
+		delay = confirmPollDelay(delay)
+		if delay != w {
+			t.Errorf("poll %d: delay %v, want %v", i+1, delay, w)
+		}
+	}
+}
+
+// Automation depends on these values (see --quiet); changing one is a
+// breaking change
+func TestExitCodesStable(t *testing.T) {
+	codes := map[string][2]int{
+		"daemon not found":   {exitDaemonNotFound, 2},
+		"port not listening": {exitPortNotListening, 3},
+		"API unreachable":    {exitAPIUnreachable, 4},
+		"challenge rejected": {exitChallengeRejected, 5},
+		"challenge expired":  {exitChallengeExpired, 6},
+		"challenge used":     {exitChallengeUsed, 7},
+		"chain mismatch":     {exitChainMismatch, 8},
+		"unsupported":        {exitUnsupported, 9},
+	}
+	for name, c := range codes {
+		if c[0] != c[1] {
+			t.Errorf("%s exits with %d, documented as %d", name, c[0], c[1])
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// Listening socket enumeration through the kernel's inet_diag netlink
+// interface (what ss uses internally), so results don't depend on the
+// output format of whichever iproute2 version is installed.
+
+const (
+	netlinkInetDiag  = 4  // NETLINK_INET_DIAG
+	sockDiagByFamily = 20 // SOCK_DIAG_BY_FAMILY
+	tcpListen        = 10 // TCP_LISTEN state
+
+	nlmsgHdrLen     = 16
+	inetDiagReqLen  = 56
+	inetDiagMsgLen  = 72
+	inetDiagSockLen = 48
+)
+
+// ListenSocket is a TCP socket in LISTEN state
+type ListenSocket struct {
+	Addr  net.IP
+	Port  int
+	UID   uint32
+	Inode uint32
+}
+
+// netlinkListeners returns all listening TCP sockets (IPv4 and IPv6)
+func netlinkListeners() ([]ListenSocket, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, netlinkInetDiag)
+	if err != nil {
+		return nil, fmt.Errorf("netlink socket: %w", err)
+	}
+	defer syscall.Close(fd)
+
+	if err := syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return nil, fmt.Errorf("netlink bind: %w", err)
+	}
+
+	var sockets []ListenSocket
+	for _, family := range []uint8{syscall.AF_INET, syscall.AF_INET6} {
+		found, err := netlinkDump(fd, family)
+		if err != nil {
+			return nil, err
+		}
+		sockets = append(sockets, found...)
+	}
+	return sockets, nil
+}
+
+func netlinkDump(fd int, family uint8) ([]ListenSocket, error) {
+	// nlmsghdr followed by inet_diag_req_v2
+	req := make([]byte, nlmsgHdrLen+inetDiagReqLen)
+	binary.NativeEndian.PutUint32(req[0:4], uint32(len(req)))
+	binary.NativeEndian.PutUint16(req[4:6], sockDiagByFamily)
+	binary.NativeEndian.PutUint16(req[6:8], syscall.NLM_F_REQUEST|syscall.NLM_F_DUMP)
+	body := req[nlmsgHdrLen:]
+	body[0] = family
+	body[1] = syscall.IPPROTO_TCP
+	binary.NativeEndian.PutUint32(body[4:8], 1<<tcpListen)
+
+	if err := syscall.Sendto(fd, req, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return nil, fmt.Errorf("netlink send: %w", err)
+	}
+
+	var sockets []ListenSocket
+	buf := make([]byte, 32*1024)
+	for {
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			return nil, fmt.Errorf("netlink receive: %w", err)
+		}
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return nil, fmt.Errorf("netlink parse: %w", err)
+		}
+		for _, msg := range msgs {
+			switch msg.Header.Type {
+			case syscall.NLMSG_DONE:
+				return sockets, nil
+			case syscall.NLMSG_ERROR:
+				return nil, fmt.Errorf("netlink: kernel returned an error")
+			}
+			if s, ok := parseInetDiagMsg(msg.Data); ok {
+				sockets = append(sockets, s)
+			}
+		}
+	}
+}
+
+// parseInetDiagMsg decodes an inet_diag_msg. Ports and addresses are in
+// network byte order; uid and inode in host order.
+func parseInetDiagMsg(data []byte) (ListenSocket, bool) {
+	if len(data) < inetDiagMsgLen {
+		return ListenSocket{}, false
+	}
+
+	family := data[0]
+	id := data[4 : 4+inetDiagSockLen]
+	rest := data[4+inetDiagSockLen:]
+
+	s := ListenSocket{
+		Port:  int(binary.BigEndian.Uint16(id[0:2])),
+		UID:   binary.NativeEndian.Uint32(rest[12:16]),
+		Inode: binary.NativeEndian.Uint32(rest[16:20]),
+	}
+	if family == syscall.AF_INET {
+		s.Addr = net.IP(append([]byte(nil), id[4:8]...))
+	} else {
+		s.Addr = net.IP(append([]byte(nil), id[4:20]...))
+	}
+	return s, true
+}
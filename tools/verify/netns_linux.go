@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// daemonNetNamespacePID returns the PID of the running daemon when it lives
+// in a different network namespace than this tool (e.g. a container that
+// shares the host PID namespace but has isolated networking)
+func daemonNetNamespacePID(ctx context.Context, daemon string) (int, bool) {
+	if daemon == "" {
+		return 0, false
+	}
+
+	pids, err := daemonPIDs(ctx, daemon)
+	if err != nil {
+		return 0, false
+	}
+
+	self, err := os.Readlink("/proc/self/ns/net")
+	if err != nil {
+		return 0, false
+	}
+
+	for _, pid := range pids {
+		ns, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/net", pid))
+		if err == nil && ns != self {
+			return pid, true
+		}
+	}
+
+	return 0, false
+}
+
+// daemonPIDs lists the processes running daemon, from /proc where it is
+// complete and from pidof or pgrep otherwise
+func daemonPIDs(ctx context.Context, daemon string) ([]int, error) {
+	if table, ok := nativeProcessTable(); ok {
+		return daemonCandidates(table, daemon, 0), nil
+	}
+	output, err := commandOutput(exec.CommandContext(ctx, "pidof", daemon))
+	if err != nil {
+		output, err = commandOutput(exec.CommandContext(ctx, "pgrep", "-x", daemon))
+		if err != nil {
+			return nil, err
+		}
+	}
+	var pids []int
+	for _, field := range strings.Fields(string(output)) {
+		if pid, err := strconv.Atoi(field); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+// checkPortInNamespace checks for a listening socket inside the network
+// namespace of pid. /proc/<pid>/net reflects that process's namespace, so
+// no privileges are needed; nsenter is tried when it isn't readable.
+func checkPortInNamespace(ctx context.Context, pid int, port int) (bool, string) {
+	readable := false
+	for _, file := range []string{"tcp", "tcp6"} {
+		data, err := os.ReadFile(fmt.Sprintf("/proc/%d/net/%s", pid, file))
+		if err != nil {
+			continue
+		}
+		readable = true
+		if procNetListening(string(data), port) {
+			return true, "netns:/proc"
+		}
+	}
+	if readable {
+		return false, ""
+	}
+
+	output, err := commandOutput(exec.CommandContext(ctx, "nsenter", "-t", strconv.Itoa(pid), "-n", "ss", "-lnt"))
+	if err != nil {
+		return false, ""
+	}
+	portStr := fmt.Sprintf(":%d", port)
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.Contains(line, portStr) && strings.Contains(line, "LISTEN") {
+			return true, "netns:nsenter"
+		}
+	}
+
+	return false, ""
+}
+
+// namespaceListenAddrs reads the addresses listening on port inside the
+// network namespace of pid
+func namespaceListenAddrs(pid int, port int) ([]string, bool) {
+	return procListenAddrs(fmt.Sprintf("/proc/%d/net", pid), port)
+}
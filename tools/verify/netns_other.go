@@ -0,0 +1,18 @@
+//go:build !linux
+
+package main
+
+import "context"
+
+// Network namespaces are Linux-only
+func daemonNetNamespacePID(ctx context.Context, daemon string) (int, bool) {
+	return 0, false
+}
+
+func checkPortInNamespace(ctx context.Context, pid int, port int) (bool, string) {
+	return false, ""
+}
+
+func namespaceListenAddrs(pid int, port int) ([]string, bool) {
+	return nil, false
+}
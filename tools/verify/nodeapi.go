@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+)
+
+// apiKeyEnv holds the API key for node management commands, so the key
+// doesn't have to show up in the process list
+const apiKeyEnv = "VERIFY_API_KEY"
+
+var nodeIDPattern = regexp.MustCompile(`^[0-9a-fA-F-]{36}$`)
+
+// nodeAPIResponse is the envelope shared by the node management endpoints
+type nodeAPIResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+	Code    string `json:"code,omitempty"`
+}
+
+// apiKeyFlag registers --api-key on fs, defaulting to $VERIFY_API_KEY
+func apiKeyFlag(fs *flag.FlagSet) *string {
+	return fs.String("api-key", os.Getenv(apiKeyEnv), "API key with the write:nodes scope (default: $"+apiKeyEnv+")")
+}
+
+// requireAPIKey exits with instructions when no API key was given
+func requireAPIKey(key string) {
+	if key != "" {
+		return
+	}
+	fmt.Println("❌ This command needs an API key with the write:nodes scope.")
+	fmt.Printf("   Create one at %s/settings/api-keys and export it as %s.\n", ApiUrl, apiKeyEnv)
+	os.Exit(1)
+}
+
+// nodeAPIRequest sends body as JSON to path, authenticated with apiKey,
+// and decodes the response into out (which may be nil). Unsuccessful
+// responses are returned as *APIError.
+func nodeAPIRequest(method, path, apiKey string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequest(method, ApiUrl+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect to API: %w", explainTLSError(err))
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var envelope nodeAPIResponse
+		if err := json.Unmarshal(respBody, &envelope); err != nil || envelope.Error == "" {
+			envelope.Error = resp.Status
+		}
+		return &APIError{
+			Status:  resp.StatusCode,
+			Code:    envelope.Code,
+			Message: envelope.Error,
+		}
+	}
+
+	if out != nil {
+		if _, err := decodeResponse(respBody, out); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return nil
+}
+
+// fatalNodeAPIError explains a failed node management request and exits
+func fatalNodeAPIError(action string, err error) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.Status {
+		case http.StatusUnauthorized:
+			log.Fatalf("❌ Failed to %s: the API key is invalid, expired or lacks the write:nodes scope", action)
+		case http.StatusForbidden:
+			log.Fatalf("❌ Failed to %s: %s", action, apiErr.Message)
+		}
+	}
+	log.Fatalf("❌ Failed to %s: %v", action, err)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNodeAPIRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			if r.Header.Get("Authorization") != "Bearer dingo_sk_test" {
+				t.Errorf("Authorization header %q", r.Header.Get("Authorization"))
+			}
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			json.NewEncoder(w).Encode(map[string]any{"success": true, "echo": body["value"]})
+		case "/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]any{"success": false, "error": "not yours", "code": "NOT_NODE_OWNER"})
+		default:
+			http.Error(w, "<html>gateway</html>", http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	saved := ApiUrl
+	ApiUrl = srv.URL
+	defer func() { ApiUrl = saved }()
+
+	var out struct {
+		nodeAPIResponse
+		Echo string `json:"echo"`
+	}
+	if err := nodeAPIRequest(http.MethodPost, "/ok", "dingo_sk_test", map[string]string{"value": "hi"}, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !out.Success || out.Echo != "hi" {
+		t.Errorf("decoded %+v", out)
+	}
+
+	tests := []struct {
+		path    string
+		status  int
+		code    string
+		message string
+	}{
+		{"/forbidden", http.StatusForbidden, "NOT_NODE_OWNER", "not yours"},
+		{"/down", http.StatusBadGateway, "", "502 Bad Gateway"},
+	}
+	for _, tt := range tests {
+		err := nodeAPIRequest(http.MethodPost, tt.path, "dingo_sk_test", nil, nil)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("%s: error %v, want *APIError", tt.path, err)
+		}
+		if apiErr.Status != tt.status || apiErr.Code != tt.code || apiErr.Message != tt.message {
+			t.Errorf("%s: got %+v", tt.path, apiErr)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Admin-issued badge tokens: nmb_<kind>_<expiry unix seconds>_<24 alnum>
+var badgeTokenPattern = regexp.MustCompile(`^nmb_(sponsor|community)_(\d{10})_[A-Za-z0-9]{24}$`)
+
+// BadgeToken is what the binary can read from a badge token before
+// submitting it; the backend still checks it against the issued tokens
+type BadgeToken struct {
+	Kind      string
+	ExpiresAt time.Time
+}
+
+// Badge is a badge as attached to a node
+type Badge struct {
+	Kind      string    `json:"kind"`
+	Label     string    `json:"label,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// parseBadgeToken validates the token's format and that it has not
+// expired at now
+func parseBadgeToken(token string, now time.Time) (BadgeToken, error) {
+	m := badgeTokenPattern.FindStringSubmatch(token)
+	if m == nil {
+		return BadgeToken{}, fmt.Errorf("not a badge token (expected nmb_<kind>_<expiry>_<code>)")
+	}
+	expiry, err := strconv.ParseInt(m[2], 10, 64)
+	if err != nil {
+		return BadgeToken{}, fmt.Errorf("invalid expiry: %w", err)
+	}
+	parsed := BadgeToken{Kind: m[1], ExpiresAt: time.Unix(expiry, 0)}
+	if !now.Before(parsed.ExpiresAt) {
+		return parsed, fmt.Errorf("token expired on %s; ask the map admins for a new one", parsed.ExpiresAt.UTC().Format("2006-01-02"))
+	}
+	return parsed, nil
+}
+
+// runAttachBadge attaches an admin-issued sponsorship or community badge
+// token to a verified node
+func runAttachBadge(args []string) {
+	fs := flag.NewFlagSet("attach-badge", flag.ExitOnError)
+	apiKey := apiKeyFlag(fs)
+	fs.Usage = commandUsage("attach-badge")
+	fs.Parse(args)
+
+	if fs.NArg() < 2 || !nodeIDPattern.MatchString(fs.Arg(0)) {
+		fs.Usage()
+		os.Exit(1)
+	}
+	nodeID, token := fs.Arg(0), fs.Arg(1)
+
+	parsed, err := parseBadgeToken(token, time.Now())
+	if err != nil {
+		fmt.Printf("❌ Invalid badge token: %v\n", err)
+		os.Exit(1)
+	}
+	requireAPIKey(*apiKey)
+
+	var resp struct {
+		nodeAPIResponse
+		Badge Badge `json:"badge"`
+	}
+	err = nodeAPIRequest(http.MethodPost, "/api/nodes/"+nodeID+"/badges", *apiKey,
+		map[string]string{"token": token}, &resp)
+	if err != nil {
+		fatalNodeAPIError("attach badge", err)
+	}
+
+	fmt.Printf("✅ %s badge attached to node %s\n", parsed.Kind, nodeID)
+	if resp.Badge.Label != "" {
+		fmt.Printf("   Label: %s\n", resp.Badge.Label)
+	}
+	fmt.Printf("   Shown on the map until %s\n", parsed.ExpiresAt.UTC().Format("2006-01-02"))
+}
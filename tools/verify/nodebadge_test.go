@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBadgeToken(t *testing.T) {
+	now := time.Unix(1750000000, 0)
+	code := strings.Repeat("aZ09", 6)
+
+	tests := []struct {
+		name    string
+		token   string
+		kind    string
+		wantErr string
+	}{
+		{"sponsor", "nmb_sponsor_1760000000_" + code, "sponsor", ""},
+		{"community", "nmb_community_1760000000_" + code, "community", ""},
+		{"expired", "nmb_sponsor_1740000000_" + code, "sponsor", "expired"},
+		{"expires now", "nmb_sponsor_1750000000_" + code, "sponsor", "expired"},
+		{"unknown kind", "nmb_gold_1760000000_" + code, "", "not a badge token"},
+		{"short code", "nmb_sponsor_1760000000_abc", "", "not a badge token"},
+		{"symbols in code", "nmb_sponsor_1760000000_" + code[:23] + "-", "", "not a badge token"},
+		{"api key", "dingo_sk_" + code + code, "", "not a badge token"},
+		{"empty", "", "", "not a badge token"},
+	}
+
+	for _, tt := range tests {
+		parsed, err := parseBadgeToken(tt.token, now)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.wantErr)
+		}
+		if parsed.Kind != tt.kind {
+			t.Errorf("%s: kind %q, want %q", tt.name, parsed.Kind, tt.kind)
+		}
+	}
+}
+
+// The binary and the backend must agree on the token format
+func TestBadgeTokenPatternMatchesSchema(t *testing.T) {
+	schema, err := os.ReadFile("../../apps/web/src/lib/validations.ts")
+	if err != nil {
+		t.Skip("backend sources not available")
+	}
+	if !strings.Contains(string(schema), "/"+badgeTokenPattern.String()+"/") {
+		t.Errorf("attachBadgeSchema does not use %s", badgeTokenPattern)
+	}
+}
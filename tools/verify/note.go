@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Matches the backend's nodeNoteSchema
+const maxNoteLength = 280
+
+// validateNote trims an operator note and checks it is a single line of
+// at most maxNoteLength characters without control characters or angle
+// brackets (notes are shown on the map)
+func validateNote(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", fmt.Errorf("note is empty")
+	}
+	if n := utf8.RuneCountInString(s); n > maxNoteLength {
+		return "", fmt.Errorf("note is %d characters, the limit is %d", n, maxNoteLength)
+	}
+	for _, r := range s {
+		switch {
+		case r == '\n' || r == '\r':
+			return "", fmt.Errorf("note must be a single line")
+		case unicode.IsControl(r) || r == utf8.RuneError:
+			return "", fmt.Errorf("note contains a control or invalid character (%U)", r)
+		case r == '<' || r == '>':
+			return "", fmt.Errorf("note must not contain < or >")
+		}
+	}
+	return s, nil
+}
+
+// runNote attaches a free-text operator note to a node, e.g. to announce
+// maintenance. Admins see all notes; --public also shows it on the map.
+func runNote(args []string) {
+	fs := flag.NewFlagSet("note", flag.ExitOnError)
+	public := fs.Bool("public", false, "Also show the note on the node's map page")
+	apiKey := apiKeyFlag(fs)
+	fs.Usage = commandUsage("note")
+	fs.Parse(args)
+
+	if fs.NArg() < 2 || !nodeIDPattern.MatchString(fs.Arg(0)) {
+		fs.Usage()
+		os.Exit(1)
+	}
+	nodeID := fs.Arg(0)
+
+	note, err := validateNote(strings.Join(fs.Args()[1:], " "))
+	if err != nil {
+		fmt.Printf("❌ Invalid note: %v\n", err)
+		os.Exit(1)
+	}
+	requireAPIKey(*apiKey)
+
+	reqBody := map[string]any{"note": note, "public": *public}
+	if err := nodeAPIRequest(http.MethodPost, "/api/nodes/"+nodeID+"/notes", *apiKey, reqBody, nil); err != nil {
+		fatalNodeAPIError("save note", err)
+	}
+
+	if *public {
+		fmt.Printf("✅ Note added; it shows on the map page of node %s\n", nodeID)
+	} else {
+		fmt.Println("✅ Note added; only the map admins can see it")
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateNote(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr string
+	}{
+		{"  migrating disks tonight ", "migrating disks tonight", ""},
+		{"Wartung: Festplattentausch 🛠", "Wartung: Festplattentausch 🛠", ""},
+		{strings.Repeat("é", maxNoteLength), strings.Repeat("é", maxNoteLength), ""},
+		{strings.Repeat("é", maxNoteLength+1), "", "limit is 280"},
+		{" \t ", "", "empty"},
+		{"line one\nline two", "", "single line"},
+		{"bell\a", "", "control"},
+		{"esc \x1b[31mred", "", "control"},
+		{"bad \xff byte", "", "invalid"},
+		{"<script>alert(1)</script>", "", "< or >"},
+	}
+
+	for _, tt := range tests {
+		got, err := validateNote(tt.in)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateNote(%.20q): error %v, want %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("validateNote(%.20q) = %.20q, %v", tt.in, got, err)
+		}
+	}
+}
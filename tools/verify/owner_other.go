@@ -0,0 +1,27 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// copyOwner gives dst the owner and group of src. Changing the owner needs
+// root, so it is only attempted when they differ.
+func copyOwner(dst, src string) error {
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	dstInfo, err := os.Stat(dst)
+	if err != nil {
+		return err
+	}
+	want, ok := srcInfo.Sys().(*syscall.Stat_t)
+	have, ok2 := dstInfo.Sys().(*syscall.Stat_t)
+	if !ok || !ok2 || (want.Uid == have.Uid && want.Gid == have.Gid) {
+		return nil
+	}
+	return os.Chown(dst, int(want.Uid), int(want.Gid))
+}
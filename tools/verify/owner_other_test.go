@@ -0,0 +1,33 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestAddUserAgentCommentOwner(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("changing file owners needs root")
+	}
+
+	dir := t.TempDir()
+	if err := os.Chown(dir, 4242, 4242); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "dingocoin.conf")
+	if _, err := addUserAgentComment(path, "nm-abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := info.Sys().(*syscall.Stat_t); st.Uid != 4242 || st.Gid != 4242 {
+		t.Errorf("new config owned by %d:%d, want the data directory's 4242:4242", st.Uid, st.Gid)
+	}
+}
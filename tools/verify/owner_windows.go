@@ -0,0 +1,6 @@
+package main
+
+// Files inherit the directory's ACL on Windows; there is no owner to copy
+func copyOwner(dst, src string) error {
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// PackageInfo names the snap or Flatpak the daemon was installed from.
+// Packaged daemons may run under another name than the plain binary, so the
+// package's app name identifies them instead.
+type PackageInfo struct {
+	Format string `json:"format"`
+	Name   string `json:"name"`
+	App    string `json:"app,omitempty"`
+}
+
+// Snap services run in snap.<snap>.<app>.service, snap apps in
+// snap.<snap>.<app>-<uuid>.scope (older snapd: snap.<snap>.<app>.<uuid>.scope);
+// Flatpak apps in app-flatpak-<app-id>-<n>.scope
+var (
+	snapCgroup    = regexp.MustCompile(`/snap\.([a-z0-9-]+)\.([a-zA-Z0-9-]+?)(?:\.service|[-.][0-9a-f-]{8,}\.scope)(?:/|$)`)
+	flatpakCgroup = regexp.MustCompile(`/app-flatpak-([A-Za-z0-9_.-]+)-[0-9]+\.scope(?:/|$)`)
+)
+
+// parsePackageCgroup finds the snap or Flatpak in /proc/<pid>/cgroup content
+func parsePackageCgroup(cgroup string) (PackageInfo, bool) {
+	for _, line := range strings.Split(cgroup, "\n") {
+		// hierarchy-ID:controllers:path
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if m := snapCgroup.FindStringSubmatch(parts[2]); m != nil {
+			return PackageInfo{Format: "snap", Name: m[1], App: m[2]}, true
+		}
+		if m := flatpakCgroup.FindStringSubmatch(parts[2]); m != nil {
+			return PackageInfo{Format: "flatpak", Name: m[1]}, true
+		}
+	}
+	return PackageInfo{}, false
+}
+
+// appName is the name the package runs the program under: the snap's app,
+// or the last part of the Flatpak app ID in lower case
+// (org.dingocoin.Dingocoind -> dingocoind)
+func (p PackageInfo) appName() string {
+	if p.Format == "snap" {
+		return p.App
+	}
+	return strings.ToLower(p.Name[strings.LastIndexByte(p.Name, '.')+1:])
+}
+
+func (p PackageInfo) String() string {
+	if p.App != "" {
+		return fmt.Sprintf("%s %s (app %s)", p.Format, p.Name, p.App)
+	}
+	return p.Format + " " + p.Name
+}
+
+// processPackage returns the snap or Flatpak pid runs in, where /proc shows
+// it
+func processPackage(pid int) (PackageInfo, bool) {
+	cgroup, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return PackageInfo{}, false
+	}
+	return parsePackageCgroup(string(cgroup))
+}
+
+// packagedDaemon reports whether pid is a packaged daemon: the top process
+// of a snap or Flatpak whose app name is one of the daemon names. Helpers
+// the app starts share its cgroup, so only the process whose parent
+// (beyond sandbox plumbing like bwrap) is outside the package counts.
+func packagedDaemon(table map[int]psEntry, pid int) (PackageInfo, bool) {
+	pkg, ok := processPackage(pid)
+	if !ok || !isDaemonName(pkg.appName()) {
+		return PackageInfo{}, false
+	}
+	ppid := table[pid].ppid
+	for ppid > 1 {
+		parent, ok := table[ppid]
+		if !ok || !sandboxParents[parent.name()] {
+			break
+		}
+		ppid = parent.ppid
+	}
+	if parentPkg, ok := processPackage(ppid); ok && parentPkg == pkg {
+		return PackageInfo{}, false
+	}
+	return pkg, true
+}
+
+// printPackage names the snap or Flatpak the daemon runs from
+func printPackage(evidence *ProcessEvidence) {
+	if evidence == nil || evidence.Package == nil {
+		return
+	}
+	fmt.Print(tr("  ℹ️  Installed as %s\n", evidence.Package))
+}
@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestParsePackageCgroup(t *testing.T) {
+	tests := []struct {
+		name   string
+		cgroup string
+		want   PackageInfo
+		found  bool
+	}{
+		{"snap service", "0::/system.slice/snap.dingocoin.dingocoind.service\n", PackageInfo{"snap", "dingocoin", "dingocoind"}, true},
+		{"snap app", "0::/user.slice/user-1000.slice/user@1000.service/app.slice/snap.dingocoin.dingocoind-2b8f7e1c-4d3a-4b6e-9f0a-1c2d3e4f5a6b.scope\n", PackageInfo{"snap", "dingocoin", "dingocoind"}, true},
+		{"snap app, older snapd", "1:name=systemd:/user.slice/user-1000.slice/session-3.scope/snap.dingocoin.dingocoind.1c3c3b3e-7b1d-4a59-9a1f-6d2e8c4b5a7f.scope\n", PackageInfo{"snap", "dingocoin", "dingocoind"}, true},
+		{"snap app with a dash", "0::/system.slice/snap.dingocoin-core.node-daemon.service\n", PackageInfo{"snap", "dingocoin-core", "node-daemon"}, true},
+		{"flatpak", "0::/user.slice/user-1000.slice/user@1000.service/app.slice/app-flatpak-org.dingocoin.Dingocoind-48213.scope\n", PackageInfo{"flatpak", "org.dingocoin.Dingocoind", ""}, true},
+		{"plain service", "0::/system.slice/dingocoind.service\n", PackageInfo{}, false},
+		{"snapd itself", "0::/system.slice/snapd.service\n", PackageInfo{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parsePackageCgroup(tt.cgroup)
+		if ok != tt.found || got != tt.want {
+			t.Errorf("%s: got %+v, %v", tt.name, got, ok)
+		}
+	}
+}
+
+func TestPackageAppName(t *testing.T) {
+	tests := []struct {
+		pkg  PackageInfo
+		want string
+	}{
+		{PackageInfo{"snap", "dingocoin", "dingocoind"}, "dingocoind"},
+		{PackageInfo{"flatpak", "org.dingocoin.Dingocoind", ""}, "dingocoind"},
+		{PackageInfo{"flatpak", "Dingocoind", ""}, "dingocoind"},
+	}
+	for _, tt := range tests {
+		if got := tt.pkg.appName(); got != tt.want {
+			t.Errorf("%v.appName() = %q, want %q", tt.pkg, got, tt.want)
+		}
+	}
+}
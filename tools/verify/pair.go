@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// One poll every 5 seconds stays within the server's pairing poll limit
+const pairPollInterval = 5 * time.Second
+
+var errPairingExpired = errors.New("the pairing code expired before it was entered")
+
+// PairStart is the answer to POST /api/verify-node/pair: a short code for
+// the operator to enter on the website and a secret token to poll with
+type PairStart struct {
+	nodeAPIResponse
+	Code      string    `json:"code"`
+	PollToken string    `json:"pollToken"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// PairPoll is the answer to a pairing poll; Challenge is set once the code
+// has been entered
+type PairPoll struct {
+	nodeAPIResponse
+	Status    string `json:"status"`
+	Challenge string `json:"challenge,omitempty"`
+}
+
+func startPairing() (*PairStart, error) {
+	var start PairStart
+	if err := nodeAPIRequest(http.MethodPost, "/api/verify-node/pair", "", nil, &start); err != nil {
+		return nil, err
+	}
+	if start.Code == "" || start.PollToken == "" {
+		return nil, errors.New("the server sent no pairing code")
+	}
+	return &start, nil
+}
+
+// waitForPairing polls until the code is entered and returns the challenge.
+// Network errors are retried until the deadline; an expired or unknown
+// pairing ends the wait.
+func waitForPairing(pollToken string, interval time.Duration, deadline time.Time) (string, error) {
+	for time.Now().Before(deadline) {
+		time.Sleep(interval)
+
+		var poll PairPoll
+		err := nodeAPIRequest(http.MethodGet, "/api/verify-node/pair/"+pollToken, "", nil, &poll)
+		var apiErr *APIError
+		switch {
+		case errors.As(err, &apiErr) && apiErr.Status == http.StatusGone:
+			return "", errPairingExpired
+		case errors.As(err, &apiErr):
+			return "", err
+		case err != nil:
+			debugLog.Info("pairing poll failed, retrying", "err", err)
+			continue
+		}
+		if poll.Challenge == "" {
+			continue
+		}
+		addRedaction(poll.Challenge)
+		if !isValidChallenge(poll.Challenge) {
+			return "", errors.New("the server sent an invalid challenge")
+		}
+		return poll.Challenge, nil
+	}
+	return "", errPairingExpired
+}
+
+// pairChallenge gets the challenge by pairing with the website instead of
+// having the operator paste it: it shows a short code, and the challenge
+// arrives once the code is entered in the verification dialog.
+func pairChallenge() (string, error) {
+	start, err := startPairing()
+	if err != nil {
+		return "", err
+	}
+	addRedaction(start.PollToken)
+
+	url := start.URL
+	if url == "" {
+		url = ApiUrl + "/my-nodes"
+	}
+	fmt.Print(tr("Open %s, start verifying your node with the binary method,\n", url))
+	fmt.Println(tr("and enter this code in the verification dialog:"))
+	fmt.Println()
+	fmt.Printf("    %s\n", start.Code)
+	fmt.Println()
+	fmt.Print(tr("Waiting for the code (valid until %s, Ctrl-C to cancel)...\n", start.ExpiresAt.Local().Format("15:04")))
+
+	challenge, err := waitForPairing(start.PollToken, pairPollInterval, start.ExpiresAt)
+	if err != nil {
+		return "", err
+	}
+	fmt.Println(tr("✅ Paired with the website, continuing with its challenge."))
+	return challenge, nil
+}
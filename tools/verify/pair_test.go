@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitForPairing(t *testing.T) {
+	const token = "0123456789abcdef0123456789abcdef"
+	const challenge = "abc123xyz456def789ghi0"
+
+	tests := []struct {
+		name    string
+		respond func(w http.ResponseWriter, poll int32)
+		want    string
+		wantErr error
+	}{
+		{
+			name: "challenge after waiting",
+			respond: func(w http.ResponseWriter, poll int32) {
+				if poll < 3 {
+					w.WriteHeader(http.StatusAccepted)
+					json.NewEncoder(w).Encode(map[string]any{"success": true, "status": "waiting"})
+					return
+				}
+				json.NewEncoder(w).Encode(map[string]any{"success": true, "status": "paired", "challenge": challenge})
+			},
+			want: challenge,
+		},
+		{
+			name: "expired",
+			respond: func(w http.ResponseWriter, poll int32) {
+				w.WriteHeader(http.StatusGone)
+				json.NewEncoder(w).Encode(map[string]any{"success": false, "code": "PAIRING_EXPIRED"})
+			},
+			wantErr: errPairingExpired,
+		},
+		{
+			name: "invalid challenge",
+			respond: func(w http.ResponseWriter, poll int32) {
+				json.NewEncoder(w).Encode(map[string]any{"success": true, "status": "paired", "challenge": "short; rm -rf"})
+			},
+			wantErr: errors.New("the server sent an invalid challenge"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/verify-node/pair/"+token {
+					t.Errorf("polled %s", r.URL.Path)
+				}
+				tt.respond(w, polls.Add(1))
+			}))
+			defer srv.Close()
+
+			saved := ApiUrl
+			ApiUrl = srv.URL
+			defer func() { ApiUrl = saved }()
+
+			got, err := waitForPairing(token, time.Millisecond, time.Now().Add(5*time.Second))
+			switch {
+			case tt.wantErr != nil && (err == nil || err.Error() != tt.wantErr.Error()):
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("challenge = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitForPairingDeadline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]any{"success": true, "status": "waiting"})
+	}))
+	defer srv.Close()
+
+	saved := ApiUrl
+	ApiUrl = srv.URL
+	defer func() { ApiUrl = saved }()
+
+	_, err := waitForPairing("0123456789abcdef0123456789abcdef", time.Millisecond, time.Now().Add(20*time.Millisecond))
+	if !errors.Is(err, errPairingExpired) {
+		t.Errorf("error = %v, want %v", err, errPairingExpired)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Payload encryption seals the confirm body to the map's X25519 key on top
+// of TLS, for hosts whose provider terminates TLS at a proxy. Each payload
+// uses a fresh ephemeral key; the AES-256-GCM key is SHA-256 over a label,
+// the shared secret and both public keys. lib/payload-encryption.ts opens it.
+const payloadAlg = "x25519-aes256gcm-v1"
+
+const payloadKDFLabel = "atlasp2p-payload-v1"
+
+// PayloadEncryption is the init response's offer to accept sealed payloads
+type PayloadEncryption struct {
+	Alg       string `json:"alg"`
+	PublicKey string `json:"publicKey"`
+}
+
+// SealedPayload replaces the plain confirm body when encryption is on
+type SealedPayload struct {
+	Alg        string `json:"alg"`
+	EPK        string `json:"epk"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// sealTo is the map's payload key once --encrypt-payload has accepted the
+// init response's offer; confirm bodies are sealed while it is set
+var sealTo *ecdh.PublicKey
+
+// acceptPayloadOffer checks the offer against the build-time PayloadKey.
+// Without a pinned key the offered one is used, but a proxy that can read
+// the traffic could have replaced it, so pinned reports whether it was
+// checked.
+func acceptPayloadOffer(offer *PayloadEncryption, pinnedKey string) (key *ecdh.PublicKey, pinned bool, err error) {
+	if offer == nil {
+		return nil, false, errors.New("the map does not accept encrypted payloads")
+	}
+	if offer.Alg != payloadAlg {
+		return nil, false, fmt.Errorf("the map offers %q, this build only supports %s", offer.Alg, payloadAlg)
+	}
+	if pinnedKey != "" && offer.PublicKey != pinnedKey {
+		return nil, false, errors.New("the map offered a different key than the one built into this tool; something between you and the map may be intercepting the connection")
+	}
+	raw, err := base64.StdEncoding.DecodeString(offer.PublicKey)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid payload key: %w", err)
+	}
+	key, err = ecdh.X25519().NewPublicKey(raw)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid payload key: %w", err)
+	}
+	return key, pinnedKey != "", nil
+}
+
+// payloadKey derives the AES key from the X25519 shared secret
+func payloadKey(shared, epk, recipient []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte(payloadKDFLabel))
+	h.Write(shared)
+	h.Write(epk)
+	h.Write(recipient)
+	return h.Sum(nil)
+}
+
+// sealPayload encrypts plaintext to the recipient's key
+func sealPayload(plaintext []byte, recipient *ecdh.PublicKey) (*SealedPayload, error) {
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return nil, err
+	}
+	epk := ephemeral.PublicKey().Bytes()
+
+	block, err := aes.NewCipher(payloadKey(shared, epk, recipient.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return &SealedPayload{
+		Alg:        payloadAlg,
+		EPK:        base64.StdEncoding.EncodeToString(epk),
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, plaintext, nil)),
+	}, nil
+}
+
+// sealedBody wraps body for the wire when sealTo is set
+func sealedBody(body []byte) ([]byte, error) {
+	if sealTo == nil {
+		return body, nil
+	}
+	sealed, err := sealPayload(body, sealTo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt payload: %w", err)
+	}
+	return json.Marshal(struct {
+		Sealed *SealedPayload `json:"sealed"`
+	}{sealed})
+}
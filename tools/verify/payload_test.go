@@ -0,0 +1,104 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+// openPayload is the backend's side of sealPayload
+func openPayload(t *testing.T, sealed *SealedPayload, key *ecdh.PrivateKey) []byte {
+	t.Helper()
+	epkRaw, _ := base64.StdEncoding.DecodeString(sealed.EPK)
+	nonce, _ := base64.StdEncoding.DecodeString(sealed.Nonce)
+	ciphertext, _ := base64.StdEncoding.DecodeString(sealed.Ciphertext)
+
+	epk, err := ecdh.X25519().NewPublicKey(epkRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shared, err := key.ECDH(epk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := aes.NewCipher(payloadKey(shared, epkRaw, key.PublicKey().Bytes()))
+	gcm, _ := cipher.NewGCM(block)
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	return plaintext
+}
+
+func TestSealPayload(t *testing.T) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := []byte(`{"challenge":"abcdefghij0123456789"}`)
+
+	first, err := sealPayload(body, key.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := sealPayload(body, key.PublicKey())
+	if first.EPK == second.EPK || first.Ciphertext == second.Ciphertext {
+		t.Error("two seals of the same body share an ephemeral key or ciphertext")
+	}
+	if got := openPayload(t, first, key); string(got) != string(body) {
+		t.Errorf("opened %s, want %s", got, body)
+	}
+
+	sealTo = key.PublicKey()
+	defer func() { sealTo = nil }()
+	wire, err := sealedBody(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope struct {
+		Sealed *SealedPayload `json:"sealed"`
+	}
+	if err := json.Unmarshal(wire, &envelope); err != nil || envelope.Sealed == nil || envelope.Sealed.Alg != payloadAlg {
+		t.Fatalf("sealedBody = %s", wire)
+	}
+	if got := openPayload(t, envelope.Sealed, key); string(got) != string(body) {
+		t.Errorf("opened %s, want %s", got, body)
+	}
+}
+
+func TestAcceptPayloadOffer(t *testing.T) {
+	key, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	offered := base64.StdEncoding.EncodeToString(key.PublicKey().Bytes())
+	other, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	pinnedOther := base64.StdEncoding.EncodeToString(other.PublicKey().Bytes())
+
+	tests := []struct {
+		name       string
+		offer      *PayloadEncryption
+		pinned     string
+		wantPinned bool
+		wantErr    bool
+	}{
+		{"pinned match", &PayloadEncryption{payloadAlg, offered}, offered, true, false},
+		{"unpinned", &PayloadEncryption{payloadAlg, offered}, "", false, false},
+		{"key swapped", &PayloadEncryption{payloadAlg, offered}, pinnedOther, false, true},
+		{"no offer", nil, offered, false, true},
+		{"unknown alg", &PayloadEncryption{"rsa-oaep", offered}, "", false, true},
+		{"short key", &PayloadEncryption{payloadAlg, "AAAA"}, "", false, true},
+	}
+
+	for _, tt := range tests {
+		got, pinned, err := acceptPayloadOffer(tt.offer, tt.pinned)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (pinned != tt.wantPinned || !got.Equal(key.PublicKey())) {
+			t.Errorf("%s: pinned = %v, key match %v", tt.name, pinned, got.Equal(key.PublicKey()))
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atlasp2p/verify/internal/flags"
+)
+
+// Signed lists older than this are refused, so a stale copy cannot be
+// replayed to hide newly flagged ranges
+const maxBlocklistAge = 7 * 24 * time.Hour
+
+// Blocklist is the map's community blocklist: ranges the crawler never
+// reports. Signature is an Ed25519 signature over blocklistMessage.
+type Blocklist struct {
+	Entries   []string `json:"entries"`
+	IssuedAt  string   `json:"issuedAt"`
+	Signature string   `json:"signature,omitempty"`
+}
+
+// PeerInfo is the subset of getpeerinfo the peer and tip checks use
+type PeerInfo struct {
+	Addr         string `json:"addr"`
+	Inbound      bool   `json:"inbound"`
+	SyncedBlocks int64  `json:"synced_blocks"`
+}
+
+// FlaggedPeer is a connected peer inside a blocklist range
+type FlaggedPeer struct {
+	Peer  PeerInfo
+	Range netip.Prefix
+}
+
+// blocklistMessage is the text the backend signs: the issue time and one
+// entry per line
+func blocklistMessage(list Blocklist) string {
+	return strings.Join(append([]string{list.IssuedAt}, list.Entries...), "\n")
+}
+
+var errBlocklistUnsigned = errors.New("the API sent an unsigned blocklist")
+
+// verifyBlocklist checks the list's signature against the base64 Ed25519
+// public key and its age against now
+func verifyBlocklist(list Blocklist, publicKey string, now time.Time) error {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("build-time BlocklistKey is not a base64 Ed25519 public key")
+	}
+	if list.Signature == "" {
+		return errBlocklistUnsigned
+	}
+	sig, err := base64.StdEncoding.DecodeString(list.Signature)
+	if err != nil || !ed25519.Verify(key, []byte(blocklistMessage(list)), sig) {
+		return fmt.Errorf("the blocklist signature does not match")
+	}
+	issued, err := time.Parse(time.RFC3339, list.IssuedAt)
+	if err != nil {
+		return fmt.Errorf("the blocklist has an invalid issue time %q", list.IssuedAt)
+	}
+	if age := now.Sub(issued); age > maxBlocklistAge {
+		return fmt.Errorf("the blocklist was issued %s ago", age.Round(time.Hour))
+	}
+	return nil
+}
+
+// parseBlocklist turns entries into prefixes; single addresses become host
+// prefixes. Entries that are neither are returned separately.
+func parseBlocklist(entries []string) (prefixes []netip.Prefix, invalid []string) {
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(entry); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+		} else {
+			invalid = append(invalid, entry)
+		}
+	}
+	return prefixes, invalid
+}
+
+// flagPeers returns the peers inside a blocklist range and how many peers
+// have an IP address at all (Tor and I2P peers can't be matched)
+func flagPeers(peers []PeerInfo, prefixes []netip.Prefix) (flagged []FlaggedPeer, ipPeers int) {
+	for _, peer := range peers {
+		addrPort, err := netip.ParseAddrPort(peer.Addr)
+		if err != nil {
+			continue
+		}
+		ipPeers++
+		addr := addrPort.Addr().Unmap()
+		for _, prefix := range prefixes {
+			if prefix.Contains(addr) {
+				flagged = append(flagged, FlaggedPeer{Peer: peer, Range: prefix})
+				break
+			}
+		}
+	}
+	return flagged, ipPeers
+}
+
+// cliName derives the daemon's RPC client from its name, e.g.
+// dingocoind -> dingocoin-cli
+func cliName() string {
+	daemon := primaryDaemon()
+	return strings.TrimSuffix(daemon, "d") + "-cli"
+}
+
+// runPeers compares the node's connected peers against the map's community
+// blocklist and warns when a large share of them is flagged
+func runPeers(args []string) {
+	fs := flag.NewFlagSet("peers", flag.ExitOnError)
+	maxShare := fs.Float64("max-flagged", 25, "Warn when more than this percentage of peers is flagged")
+	setban := fs.Bool("setban", false, "Print setban commands for the flagged ranges")
+	banTime := flags.Duration(24 * time.Hour)
+	fs.Var(&banTime, "ban-time", "Ban duration for --setban (e.g. 12h, 7d)")
+	fs.StringVar(&dataDir, "datadir", defaultDataDir(), "Daemon data directory (config, .cookie)")
+	fs.Var(&rpcAddr, "rpc-addr", "Daemon RPC host:port (default: 127.0.0.1 and rpcport from the config)")
+	fs.Usage = commandUsage("peers")
+	fs.Parse(args)
+
+	var list Blocklist
+	if err := nodeAPIRequest(http.MethodGet, "/api/blocklist", "", nil, &list); err != nil {
+		fmt.Printf("❌ Failed to fetch the blocklist: %v\n", err)
+		os.Exit(1)
+	}
+	if BlocklistKey == "" {
+		fmt.Println("⚠️  This build has no BlocklistKey; the blocklist signature is not checked")
+	} else if err := verifyBlocklist(list, BlocklistKey, time.Now()); err != nil {
+		fmt.Printf("❌ Refusing the blocklist: %v\n", err)
+		os.Exit(1)
+	}
+
+	prefixes, invalid := parseBlocklist(list.Entries)
+	for _, entry := range invalid {
+		fmt.Printf("⚠️  Skipping blocklist entry %q: not an IP or CIDR range\n", entry)
+	}
+
+	var peers []PeerInfo
+	if err := rpcCall("getpeerinfo", &peers); err != nil {
+		fmt.Printf("❌ Failed to list peers over RPC: %v\n", err)
+		os.Exit(1)
+	}
+
+	flagged, ipPeers := flagPeers(peers, prefixes)
+	share := 0.0
+	if ipPeers > 0 {
+		share = 100 * float64(len(flagged)) / float64(ipPeers)
+	}
+	fmt.Printf("Peers: %d connected, %d on the community blocklist (%.0f%%)\n", len(peers), len(flagged), share)
+	for _, f := range flagged {
+		direction := "outbound"
+		if f.Peer.Inbound {
+			direction = "inbound"
+		}
+		fmt.Printf("  ⚠️  %s (%s) is in %s\n", f.Peer.Addr, direction, f.Range)
+	}
+	if len(flagged) == 0 {
+		fmt.Println("✅ None of your peers is flagged")
+		return
+	}
+	if share > *maxShare {
+		fmt.Println()
+		fmt.Printf("⚠️  More than %.0f%% of your peers are flagged. Flagged nodes are often\n", *maxShare)
+		fmt.Println("   sybils that crowd out honest peers; consider banning them.")
+	}
+
+	if *setban {
+		ranges := make(map[string]bool)
+		for _, f := range flagged {
+			ranges[f.Range.String()] = true
+		}
+		sorted := make([]string, 0, len(ranges))
+		for r := range ranges {
+			sorted = append(sorted, r)
+		}
+		sort.Strings(sorted)
+
+		fmt.Println()
+		fmt.Println("Ban the flagged ranges with:")
+		seconds := int64(time.Duration(banTime) / time.Second)
+		for _, r := range sorted {
+			fmt.Printf("  %s setban %s add %d\n", cliName(), r, seconds)
+		}
+	}
+}
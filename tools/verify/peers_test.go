@@ -0,0 +1,94 @@
+package main
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+)
+
+// Signed by the backend's buildBlocklist with a throwaway key
+const (
+	testBlocklistKey       = "SCcfUfgMbgBfqejByp9xHeKb6zkCBc0KUFcVJdCmvm4="
+	testBlocklistSignature = "VY5EVqQ7p/J7witjwywICNiufzceFDIJSk98Rv81KiiSNY/+peECz8iUBKxE0Tx7Gsd4ACUuB60X69yscNwwAg=="
+)
+
+func TestVerifyBlocklist(t *testing.T) {
+	signed := Blocklist{
+		Entries:   []string{"203.0.113.0/24", "198.51.100.7"},
+		IssuedAt:  "2026-10-15T12:00:00.000Z",
+		Signature: testBlocklistSignature,
+	}
+	issued := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tampered := signed
+	tampered.Entries = []string{"203.0.113.0/24"}
+	unsigned := signed
+	unsigned.Signature = ""
+
+	tests := []struct {
+		name    string
+		list    Blocklist
+		key     string
+		now     time.Time
+		wantErr bool
+	}{
+		{"valid", signed, testBlocklistKey, issued.Add(time.Hour), false},
+		{"entry removed", tampered, testBlocklistKey, issued, true},
+		{"unsigned", unsigned, testBlocklistKey, issued, true},
+		{"too old", signed, testBlocklistKey, issued.Add(8 * 24 * time.Hour), true},
+		{"wrong key", signed, "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=", issued, true},
+		{"malformed key", signed, "not-a-key", issued, true},
+	}
+
+	for _, tt := range tests {
+		err := verifyBlocklist(tt.list, tt.key, tt.now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestParseBlocklist(t *testing.T) {
+	prefixes, invalid := parseBlocklist([]string{"203.0.113.7/24", " 198.51.100.7 ", "2001:db8::/32", "sybil.example", ""})
+
+	want := []string{"203.0.113.0/24", "198.51.100.7/32", "2001:db8::/32"}
+	if len(prefixes) != len(want) {
+		t.Fatalf("prefixes = %v, want %v", prefixes, want)
+	}
+	for i, prefix := range prefixes {
+		if prefix.String() != want[i] {
+			t.Errorf("prefix %d = %s, want %s", i, prefix, want[i])
+		}
+	}
+	if len(invalid) != 2 || invalid[0] != "sybil.example" {
+		t.Errorf("invalid = %q, want [sybil.example \"\"]", invalid)
+	}
+}
+
+func TestFlagPeers(t *testing.T) {
+	prefixes := []netip.Prefix{
+		netip.MustParsePrefix("203.0.113.0/24"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	}
+	peers := []PeerInfo{
+		{Addr: "203.0.113.9:33117"},
+		{Addr: "[::ffff:203.0.113.10]:33117", Inbound: true},
+		{Addr: "[2001:db8::5]:33117"},
+		{Addr: "198.51.100.7:33117"},
+		{Addr: "abcdefghijklmnop.onion:33117"},
+	}
+
+	flagged, ipPeers := flagPeers(peers, prefixes)
+	if ipPeers != 4 {
+		t.Errorf("ipPeers = %d, want 4", ipPeers)
+	}
+	want := []string{"203.0.113.9:33117", "[::ffff:203.0.113.10]:33117", "[2001:db8::5]:33117"}
+	if len(flagged) != len(want) {
+		t.Fatalf("flagged %d peers, want %d: %+v", len(flagged), len(want), flagged)
+	}
+	for i, f := range flagged {
+		if f.Peer.Addr != want[i] {
+			t.Errorf("flagged[%d] = %s, want %s", i, f.Peer.Addr, want[i])
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A metric has regressed when it is this many times worse than its
+// baseline, and past a floor that keeps noise on idle hosts quiet
+const (
+	perfRegressionFactor = 3
+	perfMinRPCLatencyMs  = 50
+)
+
+// debug.log catch-up: UpdateTip lines at most perfBurstGap apart form a
+// burst of blocks validated back to back, as after a restart. Bursts with
+// fewer than perfMinBurstBlocks blocks say nothing about throughput.
+const (
+	perfBurstGap       = 2 * time.Second
+	perfMinBurstBlocks = 20
+	perfLogTail        = 4 << 20
+	perfRPCSamples     = 5
+)
+
+// PerfMetrics are the node performance figures compared across runs.
+// A zero value was not measurable.
+type PerfMetrics struct {
+	RecordedAt time.Time `json:"recordedAt"`
+	// Median round trip of a trivial RPC call
+	RPCLatencyMs float64 `json:"rpcLatencyMs,omitempty"`
+	// Blocks per second during the largest catch-up burst in debug.log
+	BlocksPerSecond float64 `json:"blocksPerSecond,omitempty"`
+}
+
+// PerfStatus is this run's metrics against the node's baseline
+type PerfStatus struct {
+	Current     PerfMetrics `json:"current"`
+	Baseline    PerfMetrics `json:"baseline"`
+	Regressions []string    `json:"regressions,omitempty"`
+}
+
+// perfBaselinePath holds the baselines of every data directory this user
+// verified from, keyed by the directory
+func perfBaselinePath() string {
+	dir := stateDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "baseline.json")
+}
+
+// measureRPCLatency times a few getblockcount calls and returns the median
+func measureRPCLatency() (float64, error) {
+	samples := make([]float64, 0, perfRPCSamples)
+	for i := 0; i < perfRPCSamples; i++ {
+		start := time.Now()
+		if err := rpcCall("getblockcount", nil); err != nil {
+			return 0, err
+		}
+		samples = append(samples, float64(time.Since(start).Microseconds())/1000)
+	}
+	sort.Float64s(samples)
+	return samples[len(samples)/2], nil
+}
+
+// validationRate finds the largest burst of UpdateTip lines in a debug.log
+// and returns its blocks per second. Log timestamps have whole seconds, so
+// a burst within one second counts as lasting one.
+func validationRate(r io.Reader) (float64, bool) {
+	var best float64
+	var start, last time.Time
+	blocks := 0
+	flush := func() {
+		if blocks < perfMinBurstBlocks {
+			return
+		}
+		seconds := max(last.Sub(start).Seconds(), 1)
+		best = max(best, float64(blocks)/seconds)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		stamp, rest, _ := strings.Cut(scanner.Text(), " ")
+		if !strings.HasPrefix(rest, "UpdateTip:") {
+			continue
+		}
+		logged, err := time.Parse(time.RFC3339Nano, stamp)
+		if err != nil {
+			continue
+		}
+		if blocks > 0 && logged.Sub(last) <= perfBurstGap && !logged.Before(last) {
+			blocks++
+			last = logged
+			continue
+		}
+		flush()
+		start, last, blocks = logged, logged, 1
+	}
+	flush()
+	return best, best > 0
+}
+
+// logValidationRate reads the end of the daemon's debug.log
+func logValidationRate(path string) (float64, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.Size() > perfLogTail {
+		f.Seek(-perfLogTail, io.SeekEnd)
+	}
+	return validationRate(f)
+}
+
+// comparePerf lists the metrics that regressed against the baseline
+func comparePerf(current, baseline PerfMetrics) []string {
+	var regressions []string
+	if baseline.RPCLatencyMs > 0 && current.RPCLatencyMs >= perfMinRPCLatencyMs &&
+		current.RPCLatencyMs > perfRegressionFactor*baseline.RPCLatencyMs {
+		regressions = append(regressions, fmt.Sprintf("RPC latency %.0fms, %.0fx the %.1fms baseline",
+			current.RPCLatencyMs, current.RPCLatencyMs/baseline.RPCLatencyMs, baseline.RPCLatencyMs))
+	}
+	if baseline.BlocksPerSecond > 0 && current.BlocksPerSecond > 0 &&
+		current.BlocksPerSecond*perfRegressionFactor < baseline.BlocksPerSecond {
+		regressions = append(regressions, fmt.Sprintf("block validation %.1f blocks/s, down from %.1f",
+			current.BlocksPerSecond, baseline.BlocksPerSecond))
+	}
+	return regressions
+}
+
+// mergeBaseline fills the metrics the baseline lacks from the current run.
+// Recorded metrics are kept, so the baseline stays the node's healthy state
+// rather than drifting along with a slow decline.
+func mergeBaseline(baseline, current PerfMetrics) (PerfMetrics, bool) {
+	changed := false
+	if baseline.RPCLatencyMs == 0 && current.RPCLatencyMs > 0 {
+		baseline.RPCLatencyMs = current.RPCLatencyMs
+		changed = true
+	}
+	if baseline.BlocksPerSecond == 0 && current.BlocksPerSecond > 0 {
+		baseline.BlocksPerSecond = current.BlocksPerSecond
+		changed = true
+	}
+	if changed && baseline.RecordedAt.IsZero() {
+		baseline.RecordedAt = current.RecordedAt
+	}
+	return baseline, changed
+}
+
+func loadBaselines(path string) (map[string]PerfMetrics, error) {
+	baselines := make(map[string]PerfMetrics)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return baselines, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &baselines); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return baselines, nil
+}
+
+func saveBaselines(path string, baselines map[string]PerfMetrics) error {
+	data, err := json.MarshalIndent(baselines, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// checkPerformance measures this run and compares it with the data
+// directory's baseline, recording the baseline on the first run (or after
+// --reset-baseline). withRPC is false when RPC is unavailable.
+func checkPerformance(logPath string, withRPC, reset bool) (*PerfStatus, error) {
+	current := PerfMetrics{RecordedAt: time.Now().UTC()}
+	if withRPC {
+		if latency, err := measureRPCLatency(); err == nil {
+			current.RPCLatencyMs = latency
+		}
+	}
+	if rate, ok := logValidationRate(logPath); ok {
+		current.BlocksPerSecond = rate
+	}
+	if current.RPCLatencyMs == 0 && current.BlocksPerSecond == 0 {
+		return nil, errors.New("no RPC and no catch-up in debug.log to measure")
+	}
+
+	path := perfBaselinePath()
+	if path == "" {
+		return nil, errors.New("no writable directory for the baseline, see --state-dir")
+	}
+	baselines, err := loadBaselines(path)
+	if err != nil {
+		return nil, err
+	}
+	key := filepath.Clean(dataDir)
+	baseline := baselines[key]
+	if reset {
+		baseline = PerfMetrics{}
+	}
+
+	status := &PerfStatus{Current: current, Regressions: comparePerf(current, baseline)}
+	if merged, changed := mergeBaseline(baseline, current); changed || reset {
+		baselines[key] = merged
+		if err := saveBaselines(path, baselines); err != nil {
+			return nil, err
+		}
+		baseline = merged
+	}
+	status.Baseline = baseline
+	return status, nil
+}
+
+func printPerformance(status *PerfStatus) {
+	if len(status.Regressions) == 0 {
+		var parts []string
+		if status.Current.RPCLatencyMs > 0 {
+			parts = append(parts, fmt.Sprintf("RPC %.1fms", status.Current.RPCLatencyMs))
+		}
+		if status.Current.BlocksPerSecond > 0 {
+			parts = append(parts, fmt.Sprintf("validation %.1f blocks/s", status.Current.BlocksPerSecond))
+		}
+		fmt.Printf("  ✅ Performance: %s (baseline from %s)\n", strings.Join(parts, ", "), status.Baseline.RecordedAt.Format("2006-01-02"))
+		return
+	}
+	fmt.Printf("  ⚠️  Node slower than its baseline from %s:\n", status.Baseline.RecordedAt.Format("2006-01-02"))
+	for _, r := range status.Regressions {
+		fmt.Printf("     - %s\n", r)
+	}
+	fmt.Println("     A failing disk or CPU steal on the host can cause this; the node may fall behind.")
+	fmt.Println("     After a hardware change, record a new baseline with --reset-baseline.")
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// updateTipLog writes n UpdateTip lines, perSecond per second, from start
+func updateTipLog(b *strings.Builder, start time.Time, n, perSecond int) {
+	for i := 0; i < n; i++ {
+		stamp := start.Add(time.Duration(i/perSecond) * time.Second).Format(time.RFC3339)
+		fmt.Fprintf(b, "%s UpdateTip: new best=%064x height=%d\n", stamp, i, 100+i)
+	}
+}
+
+func TestValidationRate(t *testing.T) {
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	var catchUp strings.Builder
+	catchUp.WriteString("2026-10-01T11:59:00Z Bound to [::]:33117\n")
+	updateTipLog(&catchUp, start, 100, 10)
+	// Synced afterwards: one block a minute, never a burst
+	updateTipLog(&catchUp, start.Add(time.Hour), 3, 1)
+
+	var synced strings.Builder
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(&synced, "%s UpdateTip: new best=%064x\n", start.Add(time.Duration(i)*time.Minute).Format(time.RFC3339), i)
+	}
+
+	var short strings.Builder
+	updateTipLog(&short, start, perfMinBurstBlocks-1, 10)
+
+	tests := []struct {
+		name   string
+		log    string
+		want   float64
+		wantOK bool
+	}{
+		// 100 blocks logged over 9 whole seconds
+		{"catch-up burst", catchUp.String(), 100.0 / 9, true},
+		{"synced node", synced.String(), 0, false},
+		{"burst too short", short.String(), 0, false},
+		{"no UpdateTip lines", "2026-10-01T12:00:00Z Bound to [::]:33117\n", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := validationRate(strings.NewReader(tt.log))
+		if ok != tt.wantOK || math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("%s: validationRate = %.2f, %v; want %.2f, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestComparePerf(t *testing.T) {
+	baseline := PerfMetrics{RPCLatencyMs: 10, BlocksPerSecond: 90}
+	tests := []struct {
+		name    string
+		current PerfMetrics
+		want    int
+	}{
+		{"healthy", PerfMetrics{RPCLatencyMs: 12, BlocksPerSecond: 80}, 0},
+		{"slow RPC", PerfMetrics{RPCLatencyMs: 120}, 1},
+		{"slow validation", PerfMetrics{RPCLatencyMs: 11, BlocksPerSecond: 20}, 1},
+		{"both", PerfMetrics{RPCLatencyMs: 500, BlocksPerSecond: 5}, 2},
+		// 4x the baseline but below the noise floor
+		{"fast host jitter", PerfMetrics{RPCLatencyMs: 40}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := comparePerf(tt.current, baseline); len(got) != tt.want {
+			t.Errorf("%s: regressions %q, want %d", tt.name, got, tt.want)
+		}
+	}
+	if got := comparePerf(PerfMetrics{RPCLatencyMs: 500}, PerfMetrics{}); len(got) != 0 {
+		t.Errorf("regressions without a baseline: %q", got)
+	}
+}
+
+func TestCheckPerformanceBaseline(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("relies on XDG_CONFIG_HOME")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	savedDir := dataDir
+	t.Cleanup(func() { dataDir = savedDir })
+	dataDir = t.TempDir()
+
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	writeLog := func(perSecond int) string {
+		var b strings.Builder
+		updateTipLog(&b, start, 100, perSecond)
+		path := filepath.Join(dataDir, "debug.log")
+		if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	first, err := checkPerformance(writeLog(50), false, false)
+	if err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if first.Baseline.BlocksPerSecond != first.Current.BlocksPerSecond || len(first.Regressions) != 0 {
+		t.Errorf("first run should record the baseline: %+v", first)
+	}
+
+	slow, err := checkPerformance(writeLog(2), false, false)
+	if err != nil {
+		t.Fatalf("slow run: %v", err)
+	}
+	if len(slow.Regressions) != 1 || slow.Baseline.BlocksPerSecond != first.Baseline.BlocksPerSecond {
+		t.Errorf("slow run: %+v, want one regression against the first baseline", slow)
+	}
+
+	reset, err := checkPerformance(writeLog(2), false, true)
+	if err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if len(reset.Regressions) != 0 || reset.Baseline.BlocksPerSecond != reset.Current.BlocksPerSecond {
+		t.Errorf("--reset-baseline: %+v", reset)
+	}
+
+	if _, err := checkPerformance(filepath.Join(dataDir, "missing.log"), false, false); err == nil {
+		t.Error("nothing measurable accepted, want error")
+	}
+}
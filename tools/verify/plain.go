@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Plain output is for minimal VPS terminals and log aggregators that show
+// emoji and box drawing as garbage. The tool runs itself as a child and
+// filters everything the child writes, so messages of check scripts and
+// the daemon log are covered as well.
+
+type plainMode int
+
+const (
+	plainOff plainMode = iota
+	// --no-color or NO_COLOR: no ANSI colors, emoji or box drawing
+	plainNoColor
+	// --ascii: like plainNoColor, and nothing but ASCII
+	plainASCII
+)
+
+// plainChildEnv marks the filtered child. Its value lists the descriptors
+// (1, 2) that are terminals in the parent, which the child sees as pipes.
+const plainChildEnv = "DINGO_VERIFY_PLAIN_TTYS"
+
+// splitPlainFlags removes --no-color and --ascii from args. Like --config
+// they apply to every command, so they are handled before subcommands.
+// NO_COLOR selects plainNoColor when it is set and not empty.
+func splitPlainFlags(args []string) (mode plainMode, rest []string) {
+	if os.Getenv("NO_COLOR") != "" {
+		mode = plainNoColor
+	}
+	for i, arg := range args {
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		switch strings.TrimLeft(arg, "-") {
+		case "no-color":
+			mode = max(mode, plainNoColor)
+			continue
+		case "ascii":
+			mode = plainASCII
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return mode, rest
+}
+
+// isPlainChild reports whether this is the filtered child of runPlain
+func isPlainChild() bool {
+	_, ok := os.LookupEnv(plainChildEnv)
+	return ok
+}
+
+// plainTerminal reports whether fd was a terminal in the parent of a
+// filtered child
+func plainTerminal(fd uintptr) bool {
+	for _, s := range strings.Split(os.Getenv(plainChildEnv), ",") {
+		if s == strconv.Itoa(int(fd)) {
+			return true
+		}
+	}
+	return false
+}
+
+// runPlain runs the tool again with the same arguments and filters its
+// output. Returns the child's exit code.
+func runPlain(mode plainMode) int {
+	self, err := os.Executable()
+	if err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return 1
+	}
+	var ttys []string
+	for _, f := range []*os.File{os.Stdout, os.Stderr} {
+		if isTerminal(f) {
+			ttys = append(ttys, strconv.Itoa(int(f.Fd())))
+		}
+	}
+
+	stdout := &plainWriter{w: os.Stdout, ascii: mode == plainASCII}
+	stderr := &plainWriter{w: os.Stderr, ascii: mode == plainASCII}
+	cmd := exec.Command(self, os.Args[1:]...)
+	cmd.Env = append(os.Environ(), plainChildEnv+"="+strings.Join(ttys, ","))
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, stdout, stderr
+
+	// Ctrl-C reaches the child too; wait for it to finish its output
+	signal.Ignore(os.Interrupt)
+	err = cmd.Run()
+	stdout.Flush()
+	stderr.Flush()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			return exitErr.ExitCode()
+		}
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// ansiEscape matches CSI sequences (colors, cursor movement) and the other
+// two-byte escapes
+var ansiEscape = regexp.MustCompile(`\x1b(\[[0-?]*[ -/]*[@-~]|[@-Z\\-_])`)
+
+// plainGlyphs are the symbols the tool prints and their plain spelling.
+// Box drawing keeps its width so the banner stays aligned.
+var plainGlyphs = strings.NewReplacer(
+	"✅", "[OK]",
+	"❌", "[FAIL]",
+	"⚠", "[WARN]",
+	"ℹ", "[INFO]",
+	"⏳", "[WAIT]",
+	"🛠", "[FIX]",
+	"❓", "[?]",
+	"\uFE0F", "",
+	"╔", "+", "╗", "+", "╚", "+", "╝", "+",
+	"┌", "+", "┐", "+", "└", "+", "┘", "+",
+	"═", "=", "─", "-", "║", "|", "│", "|",
+)
+
+// asciiGlyphs spell the remaining common symbols in ASCII
+var asciiGlyphs = strings.NewReplacer(
+	"→", "->",
+	"←", "<-",
+	"…", "...",
+	"•", "*",
+	"–", "-",
+	"—", "-",
+	"“", `"`, "”", `"`, "‘", "'", "’", "'",
+)
+
+// plainText removes colors, emoji and box drawing from s, and with ascii
+// everything that isn't ASCII
+func plainText(s string, ascii bool) string {
+	s = ansiEscape.ReplaceAllString(s, "")
+	s = plainGlyphs.Replace(s)
+	if ascii {
+		s = asciiGlyphs.Replace(s)
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case ascii && r > unicode.MaxASCII:
+			return '?'
+		case unicode.Is(unicode.So, r):
+			// Other emoji and pictographs
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// plainWriter filters what is written to w with plainText. A rune or
+// escape sequence split across writes is held back until it is complete.
+type plainWriter struct {
+	w     io.Writer
+	ascii bool
+	carry []byte
+}
+
+func (p *plainWriter) Write(b []byte) (int, error) {
+	data := append(p.carry, b...)
+	cut := completePrefix(data)
+	p.carry = append([]byte(nil), data[cut:]...)
+	if _, err := io.WriteString(p.w, plainText(string(data[:cut]), p.ascii)); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Flush writes what is held back, once no more output follows
+func (p *plainWriter) Flush() {
+	io.WriteString(p.w, plainText(string(p.carry), p.ascii))
+	p.carry = nil
+}
+
+// completePrefix returns the length of data without a trailing incomplete
+// rune or escape sequence
+func completePrefix(data []byte) int {
+	cut := len(data)
+	// Escape sequences are short; a long tail is not one
+	if i := bytes.LastIndexByte(data, 0x1b); i >= 0 && cut-i < 32 {
+		if loc := ansiEscape.FindIndex(data[i:]); loc == nil || loc[0] != 0 {
+			cut = i
+		}
+	}
+	for i := cut - 1; i >= 0 && i >= cut-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:cut]) {
+				cut = i
+			}
+			break
+		}
+	}
+	return cut
+}
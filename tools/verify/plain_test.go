@@ -0,0 +1,69 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		in    string
+		ascii bool
+		want  string
+	}{
+		{"  ✅ Port 33117 is listening", false, "  [OK] Port 33117 is listening"},
+		{"⚠️  Ignoring field", false, "[WARN]  Ignoring field"},
+		{"╔══╗\n║ x║\n╚══╝", false, "+==+\n| x|\n+==+"},
+		{"\x1b[1;31merror\x1b[0m done", false, "error done"},
+		{"🚀 started", false, " started"},
+		{"verificación → 验证", false, "verificación → 验证"},
+		{"verificación → 验证…", true, "verificaci?n -> ??..."},
+		{"❌ “quoted”", true, `[FAIL] "quoted"`},
+	}
+	for _, tt := range tests {
+		if got := plainText(tt.in, tt.ascii); got != tt.want {
+			t.Errorf("plainText(%q, %v) = %q, want %q", tt.in, tt.ascii, got, tt.want)
+		}
+	}
+}
+
+func TestPlainWriterSplitWrites(t *testing.T) {
+	// Runes and escape sequences split across writes come out whole
+	in := "\x1b[32m✅\x1b[0m Verification submitted ═ ⚠️ done\n"
+	for size := 1; size <= 4; size++ {
+		var out strings.Builder
+		w := &plainWriter{w: &out}
+		for chunk := []byte(in); len(chunk) > 0; {
+			n := min(size, len(chunk))
+			w.Write(chunk[:n])
+			chunk = chunk[n:]
+		}
+		w.Flush()
+		if want := "[OK] Verification submitted = [WARN] done\n"; out.String() != want {
+			t.Errorf("chunks of %d: %q, want %q", size, out.String(), want)
+		}
+	}
+}
+
+func TestSplitPlainFlags(t *testing.T) {
+	tests := []struct {
+		args     []string
+		noColor  string
+		wantMode plainMode
+		wantRest []string
+	}{
+		{[]string{"verify", "abc"}, "", plainOff, []string{"verify", "abc"}},
+		{[]string{"verify", "abc"}, "1", plainNoColor, []string{"verify", "abc"}},
+		{[]string{"--no-color", "verify"}, "", plainNoColor, []string{"verify"}},
+		{[]string{"verify", "-ascii", "--no-color"}, "", plainASCII, []string{"verify"}},
+		{[]string{"verify", "--", "--ascii"}, "", plainOff, []string{"verify", "--", "--ascii"}},
+	}
+	for _, tt := range tests {
+		t.Setenv("NO_COLOR", tt.noColor)
+		mode, rest := splitPlainFlags(tt.args)
+		if mode != tt.wantMode || !reflect.DeepEqual(rest, tt.wantRest) {
+			t.Errorf("splitPlainFlags(%q), NO_COLOR=%q = %v, %q", tt.args, tt.noColor, mode, rest)
+		}
+	}
+}
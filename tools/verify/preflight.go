@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PreflightCheck is one local check of verify preflight. Status is "pass",
+// "warn" for something the verification survives but the map may not
+// like, or "fail".
+type PreflightCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Exit code when the check fails
+	code int
+}
+
+func (c PreflightCheck) glyph() string {
+	switch c.Status {
+	case "pass":
+		return "✅"
+	case "warn":
+		return "⚠️ "
+	}
+	return "❌"
+}
+
+// preflightExitCode is the code of the first failed check, 0 if none failed
+func preflightExitCode(checks []PreflightCheck) int {
+	for _, c := range checks {
+		if c.Status == "fail" {
+			return c.code
+		}
+	}
+	return 0
+}
+
+// externalIPs are the host's addresses other hosts could connect to:
+// everything but loopback and link-local
+func externalIPs(addrs []net.Addr) []net.IP {
+	var ips []net.IP
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		ips = append(ips, ipNet.IP)
+	}
+	return ips
+}
+
+// firstAccepting returns the first of ips whose port accepts a connection,
+// or "" when none does
+func firstAccepting(ctx context.Context, ips []net.IP, port int) string {
+	dialer := &net.Dialer{Timeout: 2 * time.Second}
+	for _, ip := range ips {
+		addr := net.JoinHostPort(ip.String(), strconv.Itoa(port))
+		if conn, err := dialer.DialContext(ctx, "tcp", addr); err == nil {
+			conn.Close()
+			return addr
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return ""
+}
+
+// inboundPeers counts the peers that connected to the node
+func inboundPeers(peers []PeerInfo) int {
+	n := 0
+	for _, peer := range peers {
+		if peer.Inbound {
+			n++
+		}
+	}
+	return n
+}
+
+// runPreflight runs every local check a verification relies on, and what
+// the daemon knows about its reachability, without contacting the map.
+// Operators use it before asking the website for a challenge.
+func runPreflight(args []string) {
+	fs := flag.NewFlagSet("preflight", flag.ExitOnError)
+	port := defaultPort
+	fs.Var(&port, "port", "Node P2P port to check")
+	fs.StringVar(&dataDir, "datadir", defaultDataDir(), "Daemon data directory (config, .cookie)")
+	fs.Var(&rpcAddr, "rpc-addr", "Daemon RPC host:port (default: 127.0.0.1 and rpcport from the config)")
+	fs.Var(&checkTimeout, "check-timeout", "Time limit for each of the local checks")
+	jsonOutput := fs.Bool("json", false, "Print the checks as JSON on stdout; everything else goes to stderr")
+	fs.Usage = commandUsage("preflight")
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	p := int(port)
+	jsonOut := os.Stdout
+	if *jsonOutput {
+		os.Stdout = os.Stderr
+	}
+
+	fmt.Println("Checking the node without contacting the map...")
+	var checks []PreflightCheck
+
+	ctx, cancel := checkContext()
+	processFound, processMethod, daemonName, _ := checkProcess(ctx, p)
+	warnCheckTimeout(ctx, "process")
+	cancel()
+	process := PreflightCheck{Name: "Daemon process", Status: "pass", Detail: fmt.Sprintf("%s (method: %s)", daemonName, processMethod)}
+	if !processFound {
+		process = PreflightCheck{Name: "Daemon process", Status: "fail", Detail: "none of " + DaemonNames + " is running", code: exitDaemonNotFound}
+	}
+	checks = append(checks, process)
+
+	ctx, cancel = checkContext()
+	portListening, portMethod := checkNodePort(ctx, daemonName, p)
+	warnCheckTimeout(ctx, "port")
+	cancel()
+	listen := PreflightCheck{Name: fmt.Sprintf("Port %d", p), Status: "pass", Detail: "listening (method: " + portMethod + ")"}
+	if !portListening {
+		listen = PreflightCheck{Name: fmt.Sprintf("Port %d", p), Status: "fail", Detail: "not listening", code: exitPortNotListening}
+	}
+	checks = append(checks, listen)
+
+	// A daemon bound to 127.0.0.1 passes the port check, but the map's
+	// crawler can't reach it
+	if portListening {
+		beyond := PreflightCheck{Name: "Bound beyond localhost", Status: "warn", Detail: "no other address of this host accepts connections on the port (bind=127.0.0.1?)"}
+		ctx, cancel = checkContext()
+		bound, _ := listenAddrs(ctx, daemonName, p)
+		cancel()
+		if localOnly(bound) {
+			beyond.Detail = fmt.Sprintf("bound to %s only; check bind= in %s", strings.Join(bound, ", "), daemonConfPath())
+		} else if addrs, err := net.InterfaceAddrs(); err != nil {
+			beyond.Detail = err.Error()
+		} else if ips := externalIPs(addrs); len(ips) == 0 {
+			beyond.Detail = "this host has no address besides loopback"
+		} else {
+			ctx, cancel = checkContext()
+			if addr := firstAccepting(ctx, ips, p); addr != "" {
+				beyond.Status, beyond.Detail = "pass", "accepts connections on "+addr
+			}
+			cancel()
+		}
+		checks = append(checks, beyond)
+	}
+
+	// RPC is optional for the verification, so its absence only warns; the
+	// wrong chain fails like it does there
+	rpc := PreflightCheck{Name: "RPC", Status: "warn"}
+	var chainInfo BlockchainInfo
+	if err := rpcCall("getblockchaininfo", &chainInfo); err != nil {
+		rpc.Detail = fmt.Sprintf("%v; the chain and reachability checks are skipped", err)
+		checks = append(checks, rpc)
+	} else if chainInfo.Chain != expectedChain {
+		rpc.Status, rpc.code = "fail", exitChainMismatch
+		rpc.Detail = fmt.Sprintf("the daemon is on the %q chain, the map tracks %q nodes", chainInfo.Chain, expectedChain)
+		checks = append(checks, rpc)
+	} else {
+		rpc.Status, rpc.Detail = "pass", fmt.Sprintf("%s chain, block %d", chainInfo.Chain, chainInfo.Blocks)
+		checks = append(checks, rpc)
+
+		tip := PreflightCheck{Name: "Chain tip", Status: "pass"}
+		if status, err := checkTip(chainInfo); err != nil {
+			tip.Status, tip.Detail = "warn", err.Error()
+		} else {
+			age := (time.Duration(status.TipAgeSeconds) * time.Second).Round(time.Minute)
+			tip.Detail = fmt.Sprintf("%s old", age)
+			if status.Stale != "" {
+				tip.Status, tip.Detail = "warn", fmt.Sprintf("%s old (%s)", age, status.Stale)
+			}
+		}
+		checks = append(checks, tip)
+
+		// Only the map can probe the node from outside; inbound peers show
+		// that others on the internet already reach it
+		reach := PreflightCheck{Name: "Reachable from outside", Status: "warn"}
+		var peers []PeerInfo
+		if err := rpcCall("getpeerinfo", &peers); err != nil {
+			reach.Detail = err.Error()
+		} else if n := inboundPeers(peers); n == 0 {
+			reach.Detail = fmt.Sprintf("no inbound peers among %d; check port forwarding and the firewall (new nodes can take a while)", len(peers))
+		} else {
+			reach.Status, reach.Detail = "pass", fmt.Sprintf("%d inbound peers", n)
+		}
+		checks = append(checks, reach)
+	}
+
+	code := preflightExitCode(checks)
+	if *jsonOutput {
+		enc := json.NewEncoder(jsonOut)
+		enc.SetIndent("", "  ")
+		enc.Encode(struct {
+			Checks []PreflightCheck `json:"checks"`
+			Passed bool             `json:"passed"`
+		}{checks, code == 0})
+		os.Exit(code)
+	}
+
+	for _, c := range checks {
+		fmt.Printf("  %s %s: %s\n", c.glyph(), c.Name, c.Detail)
+	}
+	fmt.Println()
+	if code != 0 {
+		fmt.Println("❌ A verification would fail; fix the checks marked ❌ first.")
+		os.Exit(code)
+	}
+	fmt.Println("✅ The node should pass. Request a challenge on the website and run verify.")
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestPreflightExitCode(t *testing.T) {
+	checks := []PreflightCheck{
+		{Name: "RPC", Status: "warn"},
+		{Name: "Port 33117", Status: "fail", code: exitPortNotListening},
+		{Name: "RPC", Status: "fail", code: exitChainMismatch},
+	}
+	if code := preflightExitCode(checks[:1]); code != 0 {
+		t.Errorf("warnings only: exit %d", code)
+	}
+	if code := preflightExitCode(checks); code != exitPortNotListening {
+		t.Errorf("exit %d, want the first failure's %d", code, exitPortNotListening)
+	}
+}
+
+func TestExternalIPs(t *testing.T) {
+	var addrs []net.Addr
+	for _, cidr := range []string{"127.0.0.1/8", "::1/128", "fe80::1/64", "192.168.1.5/24", "2001:db8::5/64"} {
+		ip, ipNet, _ := net.ParseCIDR(cidr)
+		ipNet.IP = ip
+		addrs = append(addrs, ipNet)
+	}
+	got := externalIPs(addrs)
+	if len(got) != 2 || got[0].String() != "192.168.1.5" || got[1].String() != "2001:db8::5" {
+		t.Errorf("externalIPs = %v", got)
+	}
+}
+
+func TestFirstAccepting(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	// The listener is bound to 127.0.0.1 only
+	ips := []net.IP{net.ParseIP("127.0.0.2"), net.ParseIP("127.0.0.1")}
+	if got := firstAccepting(context.Background(), ips, port); got != ln.Addr().String() {
+		t.Errorf("firstAccepting = %q, want %s", got, ln.Addr())
+	}
+	if got := firstAccepting(context.Background(), ips[:1], port); got != "" {
+		t.Errorf("firstAccepting without a listener = %q", got)
+	}
+}
@@ -0,0 +1,30 @@
+//go:build freebsd || openbsd || netbsd || dragonfly
+
+package main
+
+import "os/exec"
+
+// The BSDs don't mount /proc by default, so the process table comes from
+// ps. There -e adds the environment instead of selecting every process, so
+// ps -ax is used; comm names the executable even when argv[0] is renamed.
+func checkProcessNative(daemon string) (bool, string) {
+	return false, ""
+}
+
+func nativeProcessEvidence(daemon string) (*ProcessEvidence, bool) {
+	return nil, true
+}
+
+func nativeProcessTable() (map[int]psEntry, bool) {
+	ctx, cancel := checkContext()
+	defer cancel()
+	output, err := commandOutput(exec.CommandContext(ctx, "ps", "-axo", "pid=,ppid=,comm=,args="))
+	if err != nil {
+		return nil, false
+	}
+	return parseCommProcessTable(string(output)), true
+}
+
+func nativeStartTime(pid int) (string, bool) {
+	return "", false
+}
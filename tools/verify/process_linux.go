@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Process lookups that walk /proc instead of spawning ps, pidof or pgrep
+
+// procTable reads the parent, name and command line of every process under
+// root (normally /proc). Kernel threads, which have no command line, are
+// left out.
+func procTable(root string) (map[int]psEntry, error) {
+	dirs, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	table := make(map[int]psEntry)
+	for _, dir := range dirs {
+		pid, err := strconv.Atoi(dir.Name())
+		if err != nil {
+			continue
+		}
+		// Processes can exit between listing and reading
+		cmdline, err := os.ReadFile(filepath.Join(root, dir.Name(), "cmdline"))
+		if err != nil || len(cmdline) == 0 {
+			continue
+		}
+		stat, err := os.ReadFile(filepath.Join(root, dir.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+		comm, fields, ok := parseProcStat(string(stat))
+		if !ok {
+			continue
+		}
+		ppid, _ := strconv.Atoi(fields[1])
+		args := strings.TrimRight(strings.ReplaceAll(string(cmdline), "\x00", " "), " ")
+		table[pid] = psEntry{ppid: ppid, args: args, comm: comm}
+	}
+	return table, nil
+}
+
+// parseProcStat splits /proc/<pid>/stat into the command name and the
+// fields after it, starting with the state. The name is in parentheses and
+// may itself contain spaces and parentheses.
+func parseProcStat(stat string) (comm string, fields []string, ok bool) {
+	open, end := strings.IndexByte(stat, '('), strings.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return "", nil, false
+	}
+	fields = strings.Fields(stat[end+1:])
+	if len(fields) < 20 {
+		return "", nil, false
+	}
+	return stat[open+1 : end], fields, true
+}
+
+// nativeProcessTable is the /proc process table. It only counts when init
+// is in it: with hidepid, /proc shows just this user's processes and ps
+// would see more.
+func nativeProcessTable() (map[int]psEntry, bool) {
+	table, err := procTable("/proc")
+	if err != nil {
+		return nil, false
+	}
+	if _, ok := table[1]; !ok {
+		return nil, false
+	}
+	return table, true
+}
+
+// checkProcessNative looks for the daemon in /proc. Like the Windows
+// snapshot, a complete table is authoritative.
+func checkProcessNative(daemon string) (bool, string) {
+	table, ok := nativeProcessTable()
+	if !ok {
+		return false, ""
+	}
+	for _, entry := range table {
+		if entry.isDaemon(daemon) {
+			return true, "proc"
+		}
+	}
+	return false, "proc"
+}
+
+// nativeProcessEvidence is only reached when neither /proc nor ps lists
+// processes; there is nothing left to describe the daemon with
+func nativeProcessEvidence(daemon string) (*ProcessEvidence, bool) {
+	return nil, true
+}
+
+// Linux counts process start times in USER_HZ ticks, 100 on every
+// architecture Go supports
+const userHZ = 100
+
+// nativeStartTime returns when pid started, in ps lstart format, from its
+// stat file and the boot time
+func nativeStartTime(pid int) (string, bool) {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return "", false
+	}
+	_, fields, ok := parseProcStat(string(stat))
+	if !ok {
+		return "", false
+	}
+	ticks, err := strconv.ParseInt(fields[19], 10, 64)
+	if err != nil {
+		return "", false
+	}
+	boot, err := bootTime("/proc/stat")
+	if err != nil {
+		return "", false
+	}
+	started := boot.Add(time.Duration(ticks) * time.Second / userHZ)
+	return started.Local().Format(time.ANSIC), true
+}
+
+// bootTime reads btime from /proc/stat
+func bootTime(path string) (time.Time, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(line, "btime "); ok {
+			secs, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return time.Unix(secs, 0), nil
+		}
+	}
+	return time.Time{}, errors.New("no btime in " + path)
+}
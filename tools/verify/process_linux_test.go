@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeProc adds a process to a fake /proc
+func writeProc(t *testing.T, root, pid, stat, cmdline string) {
+	t.Helper()
+	dir := filepath.Join(root, pid)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0o644)
+	os.WriteFile(filepath.Join(dir, "cmdline"), []byte(cmdline), 0o644)
+}
+
+func TestProcTable(t *testing.T) {
+	const rest = " 0 0 0 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 1234 0 0\n"
+	root := t.TempDir()
+	writeProc(t, root, "1", "1 (systemd) S 0"+rest, "/sbin/init\x00splash\x00")
+	writeProc(t, root, "2", "2 (kthreadd) S 0"+rest, "")
+	writeProc(t, root, "42", "42 (dingocoind) S 1"+rest, "/usr/bin/dingocoind\x00-daemon\x00")
+	writeProc(t, root, "43", "43 (grep) S 1"+rest, "grep\x00dingocoind\x00")
+	writeProc(t, root, "44", "44 (dingocoind) S 1"+rest, "/tmp/miner\x00")
+	writeProc(t, root, "45", "45 (a (b) c) S 1"+rest, "x\x00")
+	os.MkdirAll(filepath.Join(root, "self"), 0o755)
+
+	table, err := procTable(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := table[2]; ok {
+		t.Error("kernel thread listed")
+	}
+	if got := table[42]; got.ppid != 1 || got.args != "/usr/bin/dingocoind -daemon" || got.comm != "dingocoind" {
+		t.Errorf("daemon entry = %+v", got)
+	}
+	if got := table[45].comm; got != "a (b) c" {
+		t.Errorf("comm with parentheses = %q", got)
+	}
+
+	// Only the process that is the daemon matches, not a grep for it or a
+	// program that renamed its comm
+	if got := daemonCandidates(table, "dingocoind", 0); len(got) != 1 || got[0] != 42 {
+		t.Errorf("daemonCandidates = %v, want [42]", got)
+	}
+
+	// comm is cut to 15 characters
+	long := psEntry{args: "/opt/bin/verylongcoin-daemon", comm: "verylongcoin-da"}
+	if !long.isDaemon("verylongcoin-daemon") {
+		t.Error("truncated comm rejected")
+	}
+}
+
+func TestBootTime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stat")
+	os.WriteFile(path, []byte("cpu  1 2 3\nintr 5\nbtime 1760500000\nprocesses 99\n"), 0o644)
+	got, err := bootTime(path)
+	if err != nil || !got.Equal(time.Unix(1760500000, 0)) {
+		t.Errorf("bootTime = %v, %v", got, err)
+	}
+
+	os.WriteFile(path, []byte("cpu  1 2 3\n"), 0o644)
+	if _, err := bootTime(path); err == nil {
+		t.Error("missing btime accepted")
+	}
+}
+
+func TestNativeStartTimeSelf(t *testing.T) {
+	started, ok := nativeStartTime(os.Getpid())
+	if !ok {
+		t.Skip("/proc not readable")
+	}
+	e := &ProcessEvidence{StartTime: started}
+	if age := time.Since(e.started()); age < 0 || age > time.Hour {
+		t.Errorf("this test started %s ago (%q)", age, started)
+	}
+}
+
+func TestSocketOwner(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+	self := os.Getpid()
+	ctx := context.Background()
+
+	owner := socketOwner(ctx, port, "dingocoind", &ProcessEvidence{PID: self})
+	if owner == nil {
+		t.Skip("socket owners are not visible here")
+	}
+	if owner.PID != self || !owner.IsDaemon {
+		t.Errorf("own socket: %+v", owner)
+	}
+	if owner := socketOwner(ctx, port, "dingocoind", &ProcessEvidence{PID: self + 1}); owner == nil || owner.IsDaemon {
+		t.Errorf("other daemon PID: %+v", owner)
+	}
+	if owner := socketOwner(ctx, port, "dingocoind", nil); owner == nil || owner.IsDaemon {
+		t.Errorf("no evidence, other program: %+v", owner)
+	}
+}
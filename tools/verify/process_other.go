@@ -0,0 +1,21 @@
+//go:build !windows && !linux && !freebsd && !openbsd && !netbsd && !dragonfly
+
+package main
+
+// Native process lookups exist for Windows and Linux; elsewhere ps, pidof
+// and pgrep are used (and ps -ax on the BSDs)
+func checkProcessNative(daemon string) (bool, string) {
+	return false, ""
+}
+
+func nativeProcessEvidence(daemon string) (*ProcessEvidence, bool) {
+	return nil, true
+}
+
+func nativeProcessTable() (map[int]psEntry, bool) {
+	return nil, false
+}
+
+func nativeStartTime(pid int) (string, bool) {
+	return "", false
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Process lookups through a Toolhelp snapshot instead of spawning tasklist
+
+// Access right that suffices for the image path and times of processes of
+// other users
+const processQueryLimitedInformation = 0x1000
+
+// processEntry is one process from the snapshot, with ".exe" kept in Name
+type processEntry struct {
+	PID       int
+	ParentPID int
+	Name      string
+}
+
+func processSnapshot() ([]processEntry, error) {
+	snap, err := syscall.CreateToolhelp32Snapshot(syscall.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.CloseHandle(snap)
+
+	var entry syscall.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	if err := syscall.Process32First(snap, &entry); err != nil {
+		return nil, err
+	}
+
+	var processes []processEntry
+	for {
+		processes = append(processes, processEntry{
+			PID:       int(entry.ProcessID),
+			ParentPID: int(entry.ParentProcessID),
+			Name:      syscall.UTF16ToString(entry.ExeFile[:]),
+		})
+		if err := syscall.Process32Next(snap, &entry); err != nil {
+			break
+		}
+	}
+	return processes, nil
+}
+
+// isProcessNamed matches an image name like "dingocoind.exe" against a
+// daemon name, case-insensitively and with or without the extension
+func isProcessNamed(image, daemon string) bool {
+	return strings.EqualFold(strings.TrimSuffix(strings.ToLower(image), ".exe"), strings.TrimSuffix(strings.ToLower(daemon), ".exe"))
+}
+
+// checkProcessNative looks for the daemon in a process snapshot. Like
+// checkPortNative, a readable snapshot is authoritative.
+func checkProcessNative(daemon string) (bool, string) {
+	processes, err := processSnapshot()
+	if err != nil {
+		return false, ""
+	}
+	for _, p := range processes {
+		if isProcessNamed(p.Name, daemon) {
+			return true, "toolhelp"
+		}
+	}
+	return false, "toolhelp"
+}
+
+// nativeProcessEvidence describes the daemon process from the snapshot
+func nativeProcessEvidence(daemon string) (*ProcessEvidence, bool) {
+	processes, err := processSnapshot()
+	if err != nil {
+		return nil, true
+	}
+
+	names := make(map[int]string, len(processes))
+	for _, p := range processes {
+		names[p.PID] = p.Name
+	}
+	for _, p := range processes {
+		if !isProcessNamed(p.Name, daemon) {
+			continue
+		}
+		evidence := &ProcessEvidence{PID: p.PID, ParentPID: p.ParentPID, ParentName: names[p.ParentPID]}
+		if exe, err := processImagePath(p.PID); err == nil {
+			evidence.Exe = exe
+		}
+		if started, ok := nativeStartTime(p.PID); ok {
+			evidence.StartTime = started
+		}
+		return evidence, true
+	}
+	return nil, false
+}
+
+// The snapshot has no command lines, so validation uses
+// nativeProcessEvidence instead of a process table
+func nativeProcessTable() (map[int]psEntry, bool) {
+	return nil, false
+}
+
+// nativeStartTime returns when pid started, in ps lstart format, from its
+// creation time
+func nativeStartTime(pid int) (string, bool) {
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return "", false
+	}
+	defer syscall.CloseHandle(h)
+
+	var created, exited, kernel, user syscall.Filetime
+	if err := syscall.GetProcessTimes(h, &created, &exited, &kernel, &user); err != nil {
+		return "", false
+	}
+	return time.Unix(0, created.Nanoseconds()).Local().Format(time.ANSIC), true
+}
+
+// processName returns the image name of pid
+func processName(pid int) (string, bool) {
+	processes, err := processSnapshot()
+	if err != nil {
+		return "", false
+	}
+	for _, p := range processes {
+		if p.PID == pid {
+			return strings.TrimSuffix(p.Name, ".exe"), true
+		}
+	}
+	return "", false
+}
+
+// processImagePath returns the full executable path of pid
+func processImagePath(pid int) (string, error) {
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return "", err
+	}
+	defer syscall.CloseHandle(h)
+
+	kernel32 := syscall.NewLazyDLL("kernel32.dll")
+	query := kernel32.NewProc("QueryFullProcessImageNameW")
+	buf := make([]uint16, syscall.MAX_PATH)
+	size := uint32(len(buf))
+	ret, _, err := query.Call(uintptr(h), 0, uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size)))
+	if ret == 0 {
+		return "", err
+	}
+	return syscall.UTF16ToString(buf[:size]), nil
+}
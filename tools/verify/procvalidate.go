@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProcessEvidence describes the daemon process that passed validation, so
+// admins can see more than "a line in ps matched"
+type ProcessEvidence struct {
+	PID        int    `json:"pid"`
+	Exe        string `json:"exe,omitempty"`
+	ParentPID  int    `json:"parentPid,omitempty"`
+	ParentName string `json:"parentName,omitempty"`
+	StartTime  string `json:"startTime,omitempty"`
+	// systemd service whose main process this is
+	Unit string `json:"unit,omitempty"`
+	// snap or Flatpak the daemon runs from
+	Package *PackageInfo `json:"package,omitempty"`
+}
+
+// started parses StartTime (ps lstart, local time). It is zero when unknown.
+func (e *ProcessEvidence) started() time.Time {
+	if e == nil || e.StartTime == "" {
+		return time.Time{}
+	}
+	t, err := time.ParseInLocation(time.ANSIC, e.StartTime, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// Container and sandbox plumbing that sits between a daemon and whatever
+// actually started it. They are skipped when reporting the parent.
+var sandboxParents = map[string]bool{
+	"pause": true, "conmon": true, "containerd-shim": true, "containerd-shim-runc-v2": true,
+	"docker-init": true, "tini": true, "catatonit": true, "dumb-init": true,
+	"bwrap": true, "firejail": true,
+}
+
+// psEntry is one row of the process table
+type psEntry struct {
+	ppid int
+	args string
+	// Kernel command name, only known when the table comes from /proc or
+	// the BSDs' ps
+	comm string
+}
+
+// name returns the base name of the program in argv[0]
+func (e psEntry) name() string {
+	fields := strings.Fields(e.args)
+	if len(fields) == 0 {
+		return ""
+	}
+	return filepath.Base(fields[0])
+}
+
+// The kernel truncates comm to at least this many characters: 15 on
+// Linux, more on the BSDs
+const maxCommLen = 15
+
+// isDaemon reports whether the process runs daemon itself: argv[0] names it
+// and so does comm where known, which a renamed argv[0] doesn't change
+func (e psEntry) isDaemon(daemon string) bool {
+	if e.name() != daemon {
+		return false
+	}
+	return e.comm == "" || (strings.HasPrefix(daemon, e.comm) && len(e.comm) >= min(len(daemon), maxCommLen))
+}
+
+// processTable lists every process with its parent and full command line,
+// from /proc where it is complete and from ps otherwise. args is used
+// rather than comm, which Linux truncates to 15 characters.
+func processTable(ctx context.Context) (map[int]psEntry, error) {
+	if table, ok := nativeProcessTable(); ok {
+		return table, nil
+	}
+	output, err := commandOutput(exec.CommandContext(ctx, "ps", "-eo", "pid=,ppid=,args="))
+	if err != nil {
+		return nil, err
+	}
+	return parseProcessTable(string(output)), nil
+}
+
+// parseProcessTable reads ps -eo pid=,ppid=,args= output
+func parseProcessTable(output string) map[int]psEntry {
+	table := make(map[int]psEntry)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		ppid, _ := strconv.Atoi(fields[1])
+		table[pid] = psEntry{ppid: ppid, args: strings.Join(fields[2:], " ")}
+	}
+	return table
+}
+
+// parseCommProcessTable reads ps -axo pid=,ppid=,comm=,args= output (the
+// BSDs)
+func parseCommProcessTable(output string) map[int]psEntry {
+	table := make(map[int]psEntry)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		ppid, _ := strconv.Atoi(fields[1])
+		table[pid] = psEntry{ppid: ppid, comm: fields[2], args: strings.Join(fields[3:], " ")}
+	}
+	return table
+}
+
+// processExe returns the executable behind pid where the OS exposes it
+func processExe(pid int) (string, bool) {
+	exe, err := os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSuffix(exe, " (deleted)"), true
+}
+
+// daemonCandidates returns the PIDs whose argv[0] is daemon, in ascending
+// order so repeated runs report the same process. preferred (the owner of
+// the node's listening socket, or 0) goes first when it is a candidate.
+func daemonCandidates(table map[int]psEntry, daemon string, preferred int) []int {
+	var pids []int
+	for pid, entry := range table {
+		if entry.isDaemon(daemon) {
+			pids = append(pids, pid)
+		}
+	}
+	sort.Slice(pids, func(i, j int) bool {
+		if (pids[i] == preferred) != (pids[j] == preferred) {
+			return pids[i] == preferred
+		}
+		return pids[i] < pids[j]
+	})
+	return pids
+}
+
+// validateDaemonProcess looks for a process that really is daemon and
+// collects its executable, parent and start time. A candidate must run as
+// daemon in argv[0] and, where /proc is available, its executable must be
+// named daemon or match DaemonNames too, so a renamed argv[0] does not pass. When several
+// daemons run, the one listening on port is reported. ok is false when the
+// process table is readable but no such process exists, i.e. an earlier
+// substring match was only a shell, grep or editor mentioning the name.
+func validateDaemonProcess(ctx context.Context, daemon string, port int) (evidence *ProcessEvidence, ok bool) {
+	table, err := processTable(ctx)
+	if err != nil {
+		// No usable ps (e.g. Windows): fall back to the OS process list
+		return nativeProcessEvidence(daemon)
+	}
+
+	owner, _, _ := listeningSocketOwner(port)
+	for _, pid := range daemonCandidates(table, daemon, owner) {
+		entry := table[pid]
+		evidence := &ProcessEvidence{PID: pid}
+		if exe, ok := processExe(pid); ok {
+			if base := filepath.Base(exe); base != daemon && !isDaemonName(base) {
+				continue
+			}
+			evidence.Exe = exe
+		} else if strings.HasPrefix(entry.args, "/") {
+			evidence.Exe = strings.Fields(entry.args)[0]
+		}
+
+		// Report the first ancestor that is not container plumbing
+		ppid := entry.ppid
+		for ppid > 1 {
+			parent, ok := table[ppid]
+			if !ok || !sandboxParents[parent.name()] {
+				break
+			}
+			ppid = parent.ppid
+		}
+		if parent, ok := table[ppid]; ok {
+			evidence.ParentPID = ppid
+			evidence.ParentName = parent.name()
+		}
+
+		if pkg, ok := processPackage(pid); ok {
+			evidence.Package = &pkg
+		}
+
+		if started, ok := nativeStartTime(pid); ok {
+			evidence.StartTime = started
+		} else if out, err := commandOutput(exec.CommandContext(ctx, "ps", "-o", "lstart=", "-p", strconv.Itoa(pid))); err == nil {
+			evidence.StartTime = strings.TrimSpace(string(out))
+		}
+
+		return evidence, true
+	}
+
+	return nil, false
+}
+
+// SocketOwner is the process holding the node's listening socket
+type SocketOwner struct {
+	PID  int    `json:"pid"`
+	Name string `json:"name,omitempty"`
+	// The owner is the daemon the process check found
+	IsDaemon bool `json:"isDaemon"`
+}
+
+// Programs that listen on a published port on a container's behalf
+var portForwarders = map[string]bool{
+	"docker-proxy": true, "rootlessport": true, "slirp4netns": true, "pasta": true,
+}
+
+// socketOwner links the socket listening on port to the daemon found by the
+// process check. It is nil when the owner can't be seen (other users'
+// sockets need root, and only Linux, Windows and the BSDs with sockstat
+// expose owners) or says nothing about the daemon: one in its own network
+// namespace or behind a port forwarder doesn't hold the socket checked
+// here.
+func socketOwner(ctx context.Context, port int, daemon string, evidence *ProcessEvidence) *SocketOwner {
+	pid, name, ok := listeningSocketOwner(port)
+	if !ok || portForwarders[name] {
+		return nil
+	}
+	if _, separate := daemonNetNamespacePID(ctx, daemon); separate {
+		return nil
+	}
+	owner := &SocketOwner{PID: pid, Name: name}
+	if evidence != nil {
+		owner.IsDaemon = pid == evidence.PID
+	} else {
+		// name is comm on Linux, which the kernel truncates
+		owner.IsDaemon = daemon != "" && name == daemon[:min(len(daemon), maxCommLen)]
+	}
+	return owner
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDaemonCandidates(t *testing.T) {
+	table := map[int]psEntry{
+		900: {ppid: 1, args: "/usr/bin/dingocoind -daemon"},
+		120: {ppid: 1, args: "dingocoind -datadir=/srv/a"},
+		450: {ppid: 1, args: "/opt/dingocoind -datadir=/srv/b"},
+		300: {ppid: 1, args: "grep dingocoind"},
+		310: {ppid: 1, args: "vim /etc/dingocoind.conf"},
+		700: {ppid: 1, args: "/usr/bin/dingocoin-cli getinfo"},
+	}
+
+	tests := []struct {
+		name      string
+		preferred int
+		want      []int
+	}{
+		{"ascending", 0, []int{120, 450, 900}},
+		{"listener first", 900, []int{900, 120, 450}},
+		{"listener not a candidate", 300, []int{120, 450, 900}},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 5; i++ {
+			if got := daemonCandidates(table, "dingocoind", tt.preferred); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s: daemonCandidates = %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+// FreeBSD keeps 19 characters of comm, so a long daemon name is cut later
+// than on Linux
+func TestCommProcessTable(t *testing.T) {
+	const output = `    1     0 init                /sbin/init
+  612     1 sshd                /usr/sbin/sshd
+ 1187     1 dingocoind          /usr/local/bin/dingocoind -daemon
+ 1290   612 sh                  sh -c tail -f /var/db/dingocoin/debug.log | grep dingocoind
+ 1301     1 miner               dingocoind -datadir=/tmp
+ 1320     1 verylongcoin-node-d /usr/local/bin/verylongcoin-node-daemon
+`
+	table := parseCommProcessTable(output)
+	if got := table[1187]; got.ppid != 1 || got.comm != "dingocoind" || got.args != "/usr/local/bin/dingocoind -daemon" {
+		t.Errorf("daemon entry = %+v", got)
+	}
+
+	// The process renamed in argv[0] isn't the daemon
+	if got := daemonCandidates(table, "dingocoind", 0); !reflect.DeepEqual(got, []int{1187}) {
+		t.Errorf("daemonCandidates = %v, want [1187]", got)
+	}
+	if got := daemonCandidates(table, "verylongcoin-node-daemon", 0); !reflect.DeepEqual(got, []int{1320}) {
+		t.Errorf("truncated comm: daemonCandidates = %v, want [1320]", got)
+	}
+}
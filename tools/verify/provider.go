@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Coarse hosting fingerprint for the map's decentralization statistics.
+// Only a provider label and environment class are reported, never instance
+// IDs, regions or other metadata. Disabled with --no-provider.
+
+// Metadata endpoints are link-local and answer within milliseconds when
+// present; anything slower is treated as absent
+const metadataTimeout = 1500 * time.Millisecond
+
+// DMI vendor/product substrings that identify a provider without touching
+// the network
+var dmiProviders = []struct {
+	match    string
+	provider string
+}{
+	{"amazon ec2", "aws"},
+	{"google", "gcp"},
+	{"hetzner", "hetzner"},
+	{"digitalocean", "digitalocean"},
+	{"ovh", "ovh"},
+	{"linode", "linode"},
+	{"akamai", "linode"},
+	{"vultr", "vultr"},
+	{"scaleway", "scaleway"},
+	{"alibaba cloud", "alibaba"},
+	{"oraclecloud", "oracle"},
+}
+
+// DMI product names of common hypervisors
+var dmiHypervisors = []string{"kvm", "qemu", "vmware", "virtualbox", "xen", "virtual machine", "bochs", "openstack"}
+
+// metadataProbes identify providers by their instance metadata service, for
+// hosts where DMI is unreadable or generic (e.g. Azure reports Microsoft).
+// Several services share 169.254.169.254 and OpenStack also mimics the EC2
+// paths, so earlier entries win when more than one answers.
+var metadataProbes = []struct {
+	provider string
+	url      string
+	header   [2]string
+}{
+	{"azure", "http://169.254.169.254/metadata/instance?api-version=2021-02-01", [2]string{"Metadata", "true"}},
+	{"gcp", "http://metadata.google.internal/computeMetadata/v1/", [2]string{"Metadata-Flavor", "Google"}},
+	{"hetzner", "http://169.254.169.254/hetzner/v1/metadata", [2]string{}},
+	{"openstack", "http://169.254.169.254/openstack/latest/meta_data.json", [2]string{}},
+	{"aws", "http://169.254.169.254/latest/meta-data/", [2]string{}},
+}
+
+// detectEnvironment returns the coarse provider label ("aws", "hetzner",
+// "other", ...) and environment class ("container", "vm", "bare-metal")
+func detectEnvironment() (provider, environment string) {
+	vendor := strings.ToLower(readDMI("sys_vendor") + " " + readDMI("product_name") + " " + readDMI("bios_vendor"))
+
+	environment = "unknown"
+	switch {
+	case inContainer():
+		environment = "container"
+	case strings.TrimSpace(vendor) == "":
+		// No DMI (non-Linux or restricted /sys): leave unknown
+	case containsAny(vendor, dmiHypervisors):
+		environment = "vm"
+	default:
+		environment = "bare-metal"
+	}
+
+	for _, p := range dmiProviders {
+		if strings.Contains(vendor, p.match) {
+			return p.provider, environment
+		}
+	}
+
+	if provider := probeMetadata(); provider != "" {
+		if environment == "bare-metal" || environment == "unknown" {
+			environment = "vm"
+		}
+		return provider, environment
+	}
+	return "other", environment
+}
+
+func readDMI(name string) string {
+	data, err := os.ReadFile("/sys/class/dmi/id/" + name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func inContainer() bool {
+	for _, path := range []string{"/.dockerenv", "/run/.containerenv"} {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return true
+	}
+	cgroup, err := os.ReadFile("/proc/1/cgroup")
+	return err == nil && containsAny(string(cgroup), []string{"docker", "kubepods", "containerd", "lxc"})
+}
+
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// probeMetadata queries all metadata endpoints at once and returns the
+// highest-priority provider that answered
+func probeMetadata() string {
+	ctx, cancel := context.WithTimeout(context.Background(), metadataTimeout)
+	defer cancel()
+
+	// Direct connections only: a proxy would answer for the wrong host
+	client := &http.Client{Transport: &http.Transport{Proxy: nil}}
+	answered := make([]bool, len(metadataProbes))
+	done := make(chan struct{}, len(metadataProbes))
+
+	for i, probe := range metadataProbes {
+		go func(i int, url string, header [2]string) {
+			defer func() { done <- struct{}{} }()
+			req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+			if err != nil {
+				return
+			}
+			if header[0] != "" {
+				req.Header.Set(header[0], header[1])
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return
+			}
+			resp.Body.Close()
+			// IMDSv2-only EC2 instances refuse tokenless reads with 401
+			answered[i] = resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusUnauthorized
+		}(i, probe.url, probe.header)
+	}
+	for range metadataProbes {
+		<-done
+	}
+
+	for i, probe := range metadataProbes {
+		if answered[i] {
+			return probe.provider
+		}
+	}
+	return ""
+}
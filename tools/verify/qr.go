@@ -0,0 +1,438 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A QR code encoder for the node page link shown after verifying: byte
+// mode, error correction level M, versions 1 to 10 (up to 213 bytes),
+// which is plenty for a URL.
+
+// qrCode is a QR symbol without quiet zone, true for dark modules
+type qrCode [][]bool
+
+// qrVersion describes a version at level M
+type qrVersion struct {
+	ecPerBlock int
+	// Data codewords of each block
+	blocks []int
+	// Row/column centers of the alignment patterns
+	align []int
+}
+
+var qrVersions = []qrVersion{
+	{10, []int{16}, nil},
+	{16, []int{28}, []int{6, 18}},
+	{26, []int{44}, []int{6, 22}},
+	{18, []int{32, 32}, []int{6, 26}},
+	{24, []int{43, 43}, []int{6, 30}},
+	{16, []int{27, 27, 27, 27}, []int{6, 34}},
+	{18, []int{31, 31, 31, 31}, []int{6, 22, 38}},
+	{22, []int{38, 38, 39, 39}, []int{6, 24, 42}},
+	{22, []int{36, 36, 36, 37, 37}, []int{6, 26, 46}},
+	{26, []int{43, 43, 43, 43, 44}, []int{6, 28, 50}},
+}
+
+// qrEncode encodes data in the smallest version that holds it
+func qrEncode(data []byte) (qrCode, error) {
+	for i, v := range qrVersions {
+		version := i + 1
+		countBits := 8
+		if version >= 10 {
+			countBits = 16
+		}
+		capacity := 0
+		for _, n := range v.blocks {
+			capacity += n
+		}
+		if 4+countBits+8*len(data) > 8*capacity {
+			continue
+		}
+		return qrDraw(version, qrCodewords(data, countBits, capacity, v)), nil
+	}
+	return nil, fmt.Errorf("%d bytes don't fit in a QR code", len(data))
+}
+
+// qrCodewords builds the data codewords, adds error correction per block
+// and interleaves the blocks
+func qrCodewords(data []byte, countBits, capacity int, v qrVersion) []byte {
+	var bits []bool
+	put := func(value, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bits = append(bits, value>>i&1 == 1)
+		}
+	}
+	put(0b0100, 4)
+	put(len(data), countBits)
+	for _, b := range data {
+		put(int(b), 8)
+	}
+	put(0, min(4, 8*capacity-len(bits)))
+	put(0, (8-len(bits)%8)%8)
+
+	codewords := make([]byte, 0, capacity)
+	for i := 0; i < len(bits); i += 8 {
+		var b byte
+		for _, bit := range bits[i : i+8] {
+			b <<= 1
+			if bit {
+				b |= 1
+			}
+		}
+		codewords = append(codewords, b)
+	}
+	for pad := byte(0xEC); len(codewords) < capacity; pad ^= 0xEC ^ 0x11 {
+		codewords = append(codewords, pad)
+	}
+
+	var dataBlocks, ecBlocks [][]byte
+	for _, n := range v.blocks {
+		block := codewords[:n]
+		codewords = codewords[n:]
+		dataBlocks = append(dataBlocks, block)
+		ecBlocks = append(ecBlocks, rsRemainder(block, v.ecPerBlock))
+	}
+	var out []byte
+	for i := 0; i < v.blocks[len(v.blocks)-1]; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				out = append(out, block[i])
+			}
+		}
+	}
+	for i := 0; i < v.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			out = append(out, block[i])
+		}
+	}
+	return out
+}
+
+// Arithmetic in GF(256) modulo x^8+x^4+x^3+x^2+1
+var gfExp, gfLog = func() (exp [512]byte, log [256]byte) {
+	x := 1
+	for i := 0; i < 255; i++ {
+		exp[i] = byte(x)
+		log[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11D
+		}
+	}
+	for i := 255; i < 512; i++ {
+		exp[i] = exp[i-255]
+	}
+	return exp, log
+}()
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+// rsRemainder returns the n Reed-Solomon error correction codewords of data
+func rsRemainder(data []byte, n int) []byte {
+	// Generator polynomial (x - a^0)...(x - a^(n-1)), highest term first
+	gen := []byte{1}
+	for i := 0; i < n; i++ {
+		next := make([]byte, len(gen)+1)
+		for j, c := range gen {
+			next[j] ^= c
+			next[j+1] ^= gfMul(c, gfExp[i])
+		}
+		gen = next
+	}
+
+	rem := make([]byte, n)
+	for _, d := range data {
+		factor := d ^ rem[0]
+		copy(rem, rem[1:])
+		rem[n-1] = 0
+		for i := range rem {
+			rem[i] ^= gfMul(gen[i+1], factor)
+		}
+	}
+	return rem
+}
+
+// qrMatrix is a symbol being drawn; function modules are reserved so data
+// and masks skip them
+type qrMatrix struct {
+	size     int
+	dark     [][]bool
+	reserved [][]bool
+}
+
+func (m *qrMatrix) set(x, y int, dark bool) {
+	m.dark[y][x] = dark
+	m.reserved[y][x] = true
+}
+
+// qrDraw lays out the codewords with the mask of the lowest penalty
+func qrDraw(version int, codewords []byte) qrCode {
+	size := 17 + 4*version
+	m := &qrMatrix{size: size, dark: qrGrid(size), reserved: qrGrid(size)}
+
+	for _, corner := range [][2]int{{0, 0}, {size - 7, 0}, {0, size - 7}} {
+		m.finder(corner[0], corner[1])
+	}
+	for i := 8; i < size-8; i++ {
+		m.set(i, 6, i%2 == 0)
+		m.set(6, i, i%2 == 0)
+	}
+	align := qrVersions[version-1].align
+	for _, y := range align {
+		for _, x := range align {
+			if (x == 6 && y == 6) || (x == 6 && y == align[len(align)-1]) || (y == 6 && x == align[len(align)-1]) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					m.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+	// Reserve the format areas before placing data
+	m.format(0)
+	if version >= 7 {
+		m.version(version)
+	}
+	m.place(codewords)
+
+	var best qrCode
+	bestPenalty := -1
+	for mask := 0; mask < 8; mask++ {
+		candidate := &qrMatrix{size: size, dark: qrGrid(size), reserved: m.reserved}
+		for y := range m.dark {
+			copy(candidate.dark[y], m.dark[y])
+		}
+		candidate.applyMask(mask)
+		candidate.format(mask)
+		if p := qrPenalty(candidate.dark); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = candidate.dark, p
+		}
+	}
+	return best
+}
+
+func qrGrid(size int) [][]bool {
+	grid := make([][]bool, size)
+	for i := range grid {
+		grid[i] = make([]bool, size)
+	}
+	return grid
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// finder draws a finder pattern with its light separator at x, y
+func (m *qrMatrix) finder(x, y int) {
+	for dy := -1; dy <= 7; dy++ {
+		for dx := -1; dx <= 7; dx++ {
+			px, py := x+dx, y+dy
+			if px < 0 || py < 0 || px >= m.size || py >= m.size {
+				continue
+			}
+			d := max(abs(dx-3), abs(dy-3))
+			m.set(px, py, d != 2 && d != 4)
+		}
+	}
+}
+
+// format draws the format information (level M, mask) in both copies
+func (m *qrMatrix) format(mask int) {
+	data := 0b00<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		m.set(8, i, bit(i))
+	}
+	m.set(8, 7, bit(6))
+	m.set(8, 8, bit(7))
+	m.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		m.set(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		m.set(m.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.set(8, m.size-15+i, bit(i))
+	}
+	m.set(8, m.size-8, true)
+}
+
+// version draws the version information of versions 7 and up
+func (m *qrMatrix) version(version int) {
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	bits := version<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := bits>>i&1 == 1
+		a, b := m.size-11+i%3, i/3
+		m.set(a, b, dark)
+		m.set(b, a, dark)
+	}
+}
+
+// place fills the data modules in the zigzag order, right to left in
+// column pairs, skipping the vertical timing pattern
+func (m *qrMatrix) place(codewords []byte) {
+	i := 0
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < m.size; vert++ {
+			y := vert
+			if upward {
+				y = m.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if m.reserved[y][x] {
+					continue
+				}
+				if i < 8*len(codewords) {
+					m.dark[y][x] = codewords[i/8]>>(7-i%8)&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (m *qrMatrix) applyMask(mask int) {
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if m.reserved[y][x] {
+				continue
+			}
+			var flip bool
+			switch mask {
+			case 0:
+				flip = (x+y)%2 == 0
+			case 1:
+				flip = y%2 == 0
+			case 2:
+				flip = x%3 == 0
+			case 3:
+				flip = (x+y)%3 == 0
+			case 4:
+				flip = (x/3+y/2)%2 == 0
+			case 5:
+				flip = x*y%2+x*y%3 == 0
+			case 6:
+				flip = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				flip = ((x+y)%2+x*y%3)%2 == 0
+			}
+			m.dark[y][x] = m.dark[y][x] != flip
+		}
+	}
+}
+
+// qrPenalty scores a masked symbol by the four rules of the standard:
+// long runs, 2x2 blocks, finder-like patterns and dark/light imbalance
+func qrPenalty(dark [][]bool) int {
+	size := len(dark)
+	at := func(x, y int, transpose bool) bool {
+		if transpose {
+			return dark[x][y]
+		}
+		return dark[y][x]
+	}
+
+	penalty := 0
+	for _, transpose := range []bool{false, true} {
+		for y := 0; y < size; y++ {
+			run := 1
+			for x := 1; x <= size; x++ {
+				if x < size && at(x, y, transpose) == at(x-1, y, transpose) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					penalty += 3 + run - 5
+				}
+				run = 1
+			}
+			for x := 0; x+11 <= size; x++ {
+				var line strings.Builder
+				for k := 0; k < 11; k++ {
+					if at(x+k, y, transpose) {
+						line.WriteByte('1')
+					} else {
+						line.WriteByte('0')
+					}
+				}
+				if s := line.String(); s == "10111010000" || s == "00001011101" {
+					penalty += 40
+				}
+			}
+		}
+	}
+
+	darkCount := 0
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if dark[y][x] {
+				darkCount++
+			}
+			if x+1 < size && y+1 < size && dark[y][x] == dark[y][x+1] && dark[y][x] == dark[y+1][x] && dark[y][x] == dark[y+1][x+1] {
+				penalty += 3
+			}
+		}
+	}
+	percent := darkCount * 100 / (size * size)
+	return penalty + abs(percent-50)/5*10
+}
+
+// renderQR prints the code with half blocks, two modules per line, for a
+// terminal with a dark background: light modules are drawn, dark ones
+// left blank. margin is the quiet zone in modules.
+func renderQR(w io.Writer, code qrCode, margin int) {
+	size := len(code) + 2*margin
+	light := func(x, y int) bool {
+		x, y = x-margin, y-margin
+		if x < 0 || y < 0 || x >= len(code) || y >= len(code) {
+			return true
+		}
+		return !code[y][x]
+	}
+	for y := 0; y < size; y += 2 {
+		var line strings.Builder
+		for x := 0; x < size; x++ {
+			top, bottom := light(x, y), y+1 < size && light(x, y+1)
+			switch {
+			case top && bottom:
+				line.WriteString("█")
+			case top:
+				line.WriteString("▀")
+			case bottom:
+				line.WriteString("▄")
+			default:
+				line.WriteByte(' ')
+			}
+		}
+		fmt.Fprintln(w, line.String())
+	}
+}
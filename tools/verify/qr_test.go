@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// HELLO WORLD at 1-M, from the standard's worked example
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, 10); !bytes.Equal(got, want) {
+		t.Errorf("rsRemainder = %v, want %v", got, want)
+	}
+}
+
+func TestQREncode(t *testing.T) {
+	// Same symbol as a reference encoder draws for "dingo"
+	want := []string{
+		"#######..#....#######",
+		"#.....#.....#.#.....#",
+		"#.###.#.#..#..#.###.#",
+		"#.###.#.#.....#.###.#",
+		"#.###.#.###.#.#.###.#",
+		"#.....#.####..#.....#",
+		"#######.#.#.#.#######",
+		"........#............",
+		"#.#####..###..#####..",
+		"##..#..##.#####.....#",
+		"...#.####.#.#.##.###.",
+		".##....#.######..##..",
+		"..#####...#.#..#...#.",
+		"........#.#.#..#.#..#",
+		"#######..#.#.#..#..#.",
+		"#.....#.#......#####.",
+		"#.###.#.####.#..#..#.",
+		"#.###.#.#.######.##..",
+		"#.###.#.###.#.##.#...",
+		"#.....#....####.###..",
+		"#######.#...#...#..#.",
+	}
+	code, err := qrEncode([]byte("dingo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for y, row := range code {
+		var line strings.Builder
+		for _, dark := range row {
+			line.WriteByte(map[bool]byte{true: '#', false: '.'}[dark])
+		}
+		if line.String() != want[y] {
+			t.Errorf("row %2d = %s\n     want %s", y, line.String(), want[y])
+		}
+	}
+}
+
+func TestQRVersion(t *testing.T) {
+	tests := []struct {
+		length  int
+		version int
+	}{
+		{14, 1},
+		{15, 2},
+		{84, 5},
+		{85, 6},
+		{180, 9},
+		{213, 10},
+		{214, 0},
+	}
+	for _, tt := range tests {
+		code, err := qrEncode(bytes.Repeat([]byte("a"), tt.length))
+		if tt.version == 0 {
+			if err == nil {
+				t.Errorf("%d bytes: no error", tt.length)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d bytes: %v", tt.length, err)
+		}
+		if size := 17 + 4*tt.version; len(code) != size {
+			t.Errorf("%d bytes: size %d, want version %d (%d)", tt.length, len(code), tt.version, size)
+		}
+	}
+}
+
+func TestRenderQR(t *testing.T) {
+	code := qrCode{{true, false}, {false, true}}
+	var out strings.Builder
+	renderQR(&out, code, 1)
+	want := "█▀██\n██▄█\n"
+	if out.String() != want {
+		t.Errorf("render =\n%s\nwant\n%s", out.String(), want)
+	}
+}
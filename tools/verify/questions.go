@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/atlasp2p/verify/internal/flags"
+)
+
+// Limits enforced by the conversation endpoint
+const (
+	maxAnswerLength      = 2000
+	minQuestionsInterval = 30 * time.Second
+)
+
+// VerificationMessage is an admin question or an operator answer on a
+// verification awaiting approval
+type VerificationMessage struct {
+	ID      string `json:"id"`
+	Author  string `json:"author"`
+	Body    string `json:"body"`
+	ReplyTo string `json:"replyTo"`
+}
+
+type ConversationRequest struct {
+	Challenge string   `json:"challenge"`
+	Answers   []Answer `json:"answers,omitempty"`
+}
+
+type Answer struct {
+	QuestionID string `json:"questionId"`
+	Body       string `json:"body"`
+}
+
+type ConversationResponse struct {
+	nodeAPIResponse
+	Status   string                `json:"status"`
+	Messages []VerificationMessage `json:"messages"`
+}
+
+// openQuestions returns the admin questions without an answer, in order
+func openQuestions(messages []VerificationMessage) []VerificationMessage {
+	answered := make(map[string]bool)
+	for _, m := range messages {
+		if m.ReplyTo != "" {
+			answered[m.ReplyTo] = true
+		}
+	}
+
+	var open []VerificationMessage
+	for _, m := range messages {
+		if m.Author == "admin" && !answered[m.ID] {
+			open = append(open, m)
+		}
+	}
+	return open
+}
+
+// validateAnswer trims an answer and checks it fits the backend's limit
+func validateAnswer(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", fmt.Errorf("answer is empty")
+	}
+	if n := utf8.RuneCountInString(s); n > maxAnswerLength {
+		return "", fmt.Errorf("answer is %d characters, the limit is %d", n, maxAnswerLength)
+	}
+	return s, nil
+}
+
+// askQuestions prompts for an answer to each question. An empty line skips
+// a question; it stays open for a later run.
+func askQuestions(in *bufio.Reader, out io.Writer, questions []VerificationMessage) []Answer {
+	var answers []Answer
+	for _, q := range questions {
+		fmt.Fprintf(out, "\n❓ %s\n", q.Body)
+		for {
+			fmt.Fprint(out, "   Answer (empty to skip): ")
+			line, err := in.ReadString('\n')
+			if strings.TrimSpace(line) == "" {
+				break
+			}
+			answer, verr := validateAnswer(line)
+			if verr == nil {
+				answers = append(answers, Answer{QuestionID: q.ID, Body: answer})
+				break
+			}
+			fmt.Fprintf(out, "   ⚠️  %v\n", verr)
+			if err != nil {
+				break
+			}
+		}
+	}
+	return answers
+}
+
+func postConversation(reqBody ConversationRequest) (*ConversationResponse, error) {
+	var resp ConversationResponse
+	if err := nodeAPIRequest(http.MethodPost, "/api/verify-node/conversation", "", reqBody, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// runQuestions polls a verification awaiting approval for admin follow-up
+// questions and answers them interactively
+func runQuestions(args []string) {
+	fs := flag.NewFlagSet("questions", flag.ExitOnError)
+	wait := flags.Duration(30 * time.Minute)
+	fs.Var(&wait, "wait", "Keep polling this long for new questions")
+	once := fs.Bool("once", false, "Check for questions once instead of polling")
+	interval := flags.Duration(time.Minute)
+	fs.Var(&interval, "interval", "Time between polls (at least 30s)")
+	fs.Usage = commandUsage("questions")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 || !isValidChallenge(fs.Arg(0)) {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if time.Duration(interval) < minQuestionsInterval {
+		log.Fatalf("❌ Invalid --interval %s: must be at least %s", interval.String(), minQuestionsInterval)
+	}
+	challenge := fs.Arg(0)
+	addRedaction(challenge)
+	interactive := isTerminal(os.Stdin)
+	in := bufio.NewReader(os.Stdin)
+	deadline := time.Now().Add(time.Duration(wait))
+	shown := make(map[string]bool)
+
+	fmt.Println("Checking for questions from the map admins...")
+	for {
+		resp, err := postConversation(ConversationRequest{Challenge: challenge})
+		if err != nil {
+			log.Fatalf("❌ Failed to fetch questions: %v", err)
+		}
+		if resp.Status != "pending_approval" {
+			fmt.Printf("ℹ️  The verification is no longer awaiting review (status: %s).\n", resp.Status)
+			return
+		}
+
+		var fresh []VerificationMessage
+		for _, q := range openQuestions(resp.Messages) {
+			if !shown[q.ID] {
+				shown[q.ID] = true
+				fresh = append(fresh, q)
+			}
+		}
+
+		if len(fresh) > 0 && !interactive {
+			for _, q := range fresh {
+				fmt.Printf("\n❓ %s\n", q.Body)
+			}
+			fmt.Println()
+			fmt.Printf("   Run %s questions %s in a terminal to answer.\n", os.Args[0], challenge)
+		} else if len(fresh) > 0 {
+			answers := askQuestions(in, os.Stdout, fresh)
+			if len(answers) > 0 {
+				if _, err := postConversation(ConversationRequest{Challenge: challenge, Answers: answers}); err != nil {
+					log.Fatalf("❌ Failed to send answers: %v", err)
+				}
+				fmt.Printf("✅ Sent %d answer(s) to the admins.\n", len(answers))
+			}
+		}
+
+		if *once || !time.Now().Add(time.Duration(interval)).Before(deadline) {
+			break
+		}
+		time.Sleep(time.Duration(interval))
+	}
+
+	if len(shown) == 0 {
+		fmt.Println("No questions so far. The admins may still ask some during review.")
+	}
+}
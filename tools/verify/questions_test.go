@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOpenQuestions(t *testing.T) {
+	messages := []VerificationMessage{
+		{ID: "q1", Author: "admin", Body: "Which provider is this?"},
+		{ID: "a1", Author: "operator", Body: "Hetzner", ReplyTo: "q1"},
+		{ID: "q2", Author: "admin", Body: "Please re-run with --follow-daemon-log"},
+		{ID: "q3", Author: "admin", Body: "Is the node behind NAT?"},
+	}
+
+	var ids []string
+	for _, q := range openQuestions(messages) {
+		ids = append(ids, q.ID)
+	}
+	if want := []string{"q2", "q3"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("open questions %v, want %v", ids, want)
+	}
+	if open := openQuestions(nil); len(open) != 0 {
+		t.Errorf("open questions of no messages: %v", open)
+	}
+}
+
+func TestValidateAnswer(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"  Hetzner, FSN1\n", "Hetzner, FSN1", false},
+		{"\t\n", "", true},
+		{strings.Repeat("ä", maxAnswerLength), strings.Repeat("ä", maxAnswerLength), false},
+		{strings.Repeat("ä", maxAnswerLength+1), "", true},
+	}
+	for _, tt := range tests {
+		got, err := validateAnswer(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("validateAnswer(%.20q) = %.20q, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestAskQuestions(t *testing.T) {
+	questions := []VerificationMessage{
+		{ID: "q1", Author: "admin", Body: "Which provider is this?"},
+		{ID: "q2", Author: "admin", Body: "Anything else?"},
+		{ID: "q3", Author: "admin", Body: "Last one"},
+	}
+	// Too long, then a valid answer; skip q2; q3 answered without a final newline
+	input := strings.Repeat("x", maxAnswerLength+1) + "\nHetzner\n\n  home server "
+
+	answers := askQuestions(bufio.NewReader(strings.NewReader(input)), io.Discard, questions)
+	want := []Answer{
+		{QuestionID: "q1", Body: "Hetzner"},
+		{QuestionID: "q3", Body: "home server"},
+	}
+	if !reflect.DeepEqual(answers, want) {
+		t.Errorf("answers %+v, want %+v", answers, want)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// ReachabilityProbe is the backend's request to connect to this host on an
+// auxiliary port. The backend sends Nonce and expects the challenge-bound
+// reply from reachabilityReply, proving inbound connectivity even when the
+// P2P port itself is saturated.
+type ReachabilityProbe struct {
+	Port    int    `json:"port"`
+	Nonce   string `json:"nonce"`
+	Timeout int    `json:"timeout,omitempty"` // seconds
+}
+
+// ReachabilityResult records whether the probe arrived, for the confirm payload
+type ReachabilityResult struct {
+	Port       int    `json:"port"`
+	Probed     bool   `json:"probed"`
+	RemoteAddr string `json:"remoteAddr,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Used when the backend does not say how long to wait
+const defaultReachabilityTimeout = 30 * time.Second
+
+// serveReachabilityProbe listens on the requested port until a connection
+// presents the nonce or the timeout expires, then reports the result
+func serveReachabilityProbe(challenge string, probe ReachabilityProbe) <-chan *ReachabilityResult {
+	done := make(chan *ReachabilityResult, 1)
+	result := &ReachabilityResult{Port: probe.Port}
+
+	timeout := defaultReachabilityTimeout
+	if probe.Timeout > 0 {
+		timeout = time.Duration(probe.Timeout) * time.Second
+	}
+
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", probe.Port))
+	if err != nil {
+		result.Error = err.Error()
+		done <- result
+		return done
+	}
+
+	go func() {
+		defer ln.Close()
+		deadline := time.Now().Add(timeout)
+		ln.(*net.TCPListener).SetDeadline(deadline)
+
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				result.Error = "no probe received before timeout"
+				done <- result
+				return
+			}
+
+			conn.SetDeadline(time.Now().Add(5 * time.Second))
+			line, _ := bufio.NewReader(conn).ReadString('\n')
+			if strings.TrimSpace(line) != probe.Nonce {
+				// Unrelated scanner or stale probe, keep waiting
+				conn.Close()
+				continue
+			}
+
+			fmt.Fprintf(conn, "%s\n", reachabilityReply(challenge, probe.Nonce))
+			conn.Close()
+
+			result.Probed = true
+			result.RemoteAddr = conn.RemoteAddr().String()
+			done <- result
+			return
+		}
+	}()
+
+	return done
+}
+
+// reachabilityReply binds the probe to this challenge so the backend knows
+// it reached the tool that holds it
+func reachabilityReply(challenge, nonce string) string {
+	sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Receipt is the map's record of a submitted verification, sent with a
+// successful confirm. It is signed with Ed25519 when the map has a receipt
+// key; the signed text is the fields up to SubmittedAt, one per line.
+type Receipt struct {
+	VerificationID string `json:"verificationId"`
+	NodeIP         string `json:"nodeIp"`
+	NodePort       int    `json:"nodePort"`
+	// SHA-256 of the challenge, hex
+	ChallengeHash string `json:"challengeHash"`
+	SubmittedAt   string `json:"submittedAt"`
+	Signature     string `json:"signature,omitempty"`
+}
+
+// receiptFile is a saved receipt with the outcome and the map it came from
+type receiptFile struct {
+	Receipt
+	Status string `json:"status"`
+	ApiUrl string `json:"apiUrl"`
+}
+
+// receiptsDir keeps one file per submitted verification, as proof for the
+// operator and something concrete for support when an approval stalls
+func receiptsDir() string {
+	dir := stateDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "receipts")
+}
+
+// saveReceipt writes r to dir, named by submission time and node, and
+// returns the file's path
+func saveReceipt(dir string, r Receipt, status string) (string, error) {
+	if dir == "" {
+		return "", errors.New("no writable directory for receipts, see --state-dir")
+	}
+	data, err := json.MarshalIndent(receiptFile{r, status, ApiUrl}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	submitted, err := time.Parse(time.RFC3339, r.SubmittedAt)
+	if err != nil {
+		submitted = time.Now()
+	}
+	stamp := submitted.UTC().Format("20060102T150405Z")
+	// IPv6 colons aren't allowed in Windows file names
+	name := fmt.Sprintf("%s-%s-%d.json", stamp, strings.ReplaceAll(r.NodeIP, ":", "_"), r.NodePort)
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name)
+	return path, writeFileAtomic(path, append(data, '\n'), 0o600, dir)
+}
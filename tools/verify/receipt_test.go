@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveReceipt(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "receipts")
+	r := Receipt{
+		VerificationID: "b3c1",
+		NodeIP:         "2a01:4f8::1",
+		NodePort:       33117,
+		ChallengeHash:  "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		SubmittedAt:    "2026-10-15T09:30:05.123Z",
+		Signature:      "c2lnbmF0dXJl",
+	}
+	path, err := saveReceipt(dir, r, "pending_approval")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "20261015T093005Z-2a01_4f8__1-33117.json"); path != want {
+		t.Errorf("saved to %s, want %s", path, want)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved receiptFile
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Receipt != r || saved.Status != "pending_approval" {
+		t.Errorf("saved %+v", saved)
+	}
+
+	if _, err := saveReceipt("", r, ""); err == nil {
+		t.Error("saved without a directory")
+	}
+}
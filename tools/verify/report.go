@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Result is the outcome of a verification run as rendered by
+// --report-template. Runs that stop early leave the later fields empty and
+// set Error.
+type Result struct {
+	Chain        string              `json:"chain"`
+	Version      string              `json:"version"`
+	Node         NodeAddress         `json:"node"`
+	ProcessCheck ProcessCheck        `json:"processCheck"`
+	PortCheck    PortCheck           `json:"portCheck"`
+	Ports        []PortCheck         `json:"ports,omitempty"`
+	SystemInfo   SystemInfo          `json:"systemInfo"`
+	Disk         *DiskHealth         `json:"disk,omitempty"`
+	Tip          *TipStatus          `json:"tip,omitempty"`
+	Performance  *PerfStatus         `json:"performance,omitempty"`
+	Escalation   []EscalationStep    `json:"escalation,omitempty"`
+	Reachability *ReachabilityResult `json:"reachability,omitempty"`
+	Timings      *StepTimings        `json:"timings,omitempty"`
+	Strict       *StrictEvidence     `json:"strict,omitempty"`
+	AddrRelay    *AddrRelayResult    `json:"addrRelay,omitempty"`
+	Support      *SupportReport      `json:"support,omitempty"`
+	Submitted    bool                `json:"submitted"`
+	DryRun       bool                `json:"dryRun,omitempty"`
+	Status       string              `json:"status,omitempty"`
+	Message      string              `json:"message,omitempty"`
+	NodeURL      string              `json:"nodeUrl,omitempty"`
+	ReceiptFile  string              `json:"receiptFile,omitempty"`
+	Error        string              `json:"error,omitempty"`
+	// Output of the --check-script scripts by script name
+	CustomChecks map[string]json.RawMessage `json:"customChecks,omitempty"`
+	// Confirm response fields from a newer backend, passed through as sent
+	Extra map[string]json.RawMessage `json:"extra,omitempty"`
+}
+
+func newResult() *Result {
+	return &Result{
+		Chain:   ChainName,
+		Version: Version,
+	}
+}
+
+// setChecks copies the local check results from the confirm payload
+func (r *Result) setChecks(reqBody ConfirmRequest) {
+	r.ProcessCheck = reqBody.ProcessCheck
+	r.PortCheck = reqBody.PortCheck
+	r.Ports = reqBody.Ports
+	r.SystemInfo = reqBody.SystemInfo
+	r.CustomChecks = reqBody.CustomChecks
+}
+
+// Helpers available to --report-template, e.g. {{check .PortCheck.Listening}}
+var reportFuncs = template.FuncMap{
+	"check": func(ok bool) string {
+		if ok {
+			return "✅"
+		}
+		return "❌"
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+func loadReportTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return template.New("report").Funcs(reportFuncs).Option("missingkey=error").Parse(string(data))
+}
+
+// writeReport renders the result with tmpl to output (stdout when empty).
+// Rendering problems are reported but never change the verification outcome.
+func writeReport(tmpl *template.Template, output string, result *Result) {
+	if tmpl == nil {
+		return
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, result); err != nil {
+		fmt.Printf("⚠️  Failed to render report: %v\n", err)
+		return
+	}
+
+	if output == "" {
+		fmt.Print(b.String())
+		return
+	}
+	err := os.WriteFile(output, []byte(b.String()), 0644)
+	if err != nil && isReadOnly(err) && stateDir() != "" {
+		// An immutable filesystem shouldn't lose the report
+		relocated := filepath.Join(stateDir(), filepath.Base(output))
+		if err = os.MkdirAll(stateDir(), 0o700); err == nil {
+			err = os.WriteFile(relocated, []byte(b.String()), 0644)
+		}
+		if err == nil {
+			fmt.Printf("ℹ️  %s is read-only, report written to %s\n", filepath.Dir(output), relocated)
+		}
+	}
+	if err != nil {
+		fmt.Printf("⚠️  Failed to write report: %v\n", err)
+	}
+}
+
+// writeJSONResult prints the result as a single JSON document (--json)
+func writeJSONResult(w io.Writer, result *Result) {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(result); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to write JSON result: %v\n", err)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteReportEvidence(t *testing.T) {
+	dir := t.TempDir()
+	tmplPath := filepath.Join(dir, "report.tmpl")
+	body := `{{range .Escalation}}{{.Method}}={{check .Passed}} {{end}}{{with .Reachability}}probe={{check .Probed}}{{end}}`
+	if err := os.WriteFile(tmplPath, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := loadReportTemplate(tmplPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := newResult()
+	result.Escalation = []EscalationStep{{Method: "rpc", Passed: true}, {Method: "dial", Passed: false}}
+	result.Reachability = &ReachabilityResult{Port: 33118, Probed: true}
+
+	output := filepath.Join(dir, "report.txt")
+	writeReport(tmpl, output, result)
+
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "rpc=✅ dial=❌ probe=✅"; string(got) != want {
+		t.Errorf("report = %q, want %q", got, want)
+	}
+}
+
+func TestWriteJSONResult(t *testing.T) {
+	result := newResult()
+	result.Node = NodeAddress{IP: "203.0.113.9", Port: 33117}
+	result.ProcessCheck = ProcessCheck{Found: true, Method: "ps", DaemonName: "dingocoind"}
+	result.PortCheck = PortCheck{Listening: true, Port: 33117, Method: "netlink"}
+	result.Submitted = true
+	result.Status = "pending_approval"
+
+	var out bytes.Buffer
+	writeJSONResult(&out, result)
+
+	var got map[string]any
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("output is not one JSON document: %v\n%s", err, out.String())
+	}
+	for _, key := range []string{"chain", "version", "node", "processCheck", "portCheck", "systemInfo", "submitted", "status"} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("JSON result lacks %q", key)
+		}
+	}
+	if _, ok := got["error"]; ok {
+		t.Errorf("JSON result has an error for a successful run: %v", got["error"])
+	}
+}
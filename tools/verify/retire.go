@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// The backend accepts shutdowns up to a year ahead
+const maxRetireNoticeDays = 365
+
+// parseRetireDate parses a YYYY-MM-DD shutdown date. The node retires at
+// 00:00 UTC that day, which must lie after now and within a year.
+func parseRetireDate(s string, now time.Time) (time.Time, error) {
+	at, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a YYYY-MM-DD date", s)
+	}
+	if !at.After(now) {
+		return time.Time{}, fmt.Errorf("%s is not in the future", s)
+	}
+	if at.Sub(now) > maxRetireNoticeDays*24*time.Hour {
+		return time.Time{}, fmt.Errorf("%s is more than %d days away", s, maxRetireNoticeDays)
+	}
+	return at, nil
+}
+
+// runRetire announces (or cancels) a node's planned shutdown, so the map
+// shows it as retiring and its uptime record ends at the shutdown
+func runRetire(args []string) {
+	fs := flag.NewFlagSet("retire", flag.ExitOnError)
+	at := fs.String("at", "", "Planned shutdown date, YYYY-MM-DD (00:00 UTC)")
+	cancel := fs.Bool("cancel", false, "Cancel a previously announced shutdown")
+	apiKey := apiKeyFlag(fs)
+	fs.Usage = commandUsage("retire")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 || !nodeIDPattern.MatchString(fs.Arg(0)) || (*at == "") == !*cancel {
+		fs.Usage()
+		os.Exit(1)
+	}
+	nodeID := fs.Arg(0)
+	path := "/api/nodes/" + nodeID + "/retire"
+
+	if *cancel {
+		requireAPIKey(*apiKey)
+		if err := nodeAPIRequest(http.MethodDelete, path, *apiKey, nil, nil); err != nil {
+			fatalNodeAPIError("cancel shutdown", err)
+		}
+		fmt.Printf("✅ Shutdown of node %s cancelled\n", nodeID)
+		return
+	}
+
+	date, err := parseRetireDate(*at, time.Now())
+	if err != nil {
+		fmt.Printf("❌ Invalid --at: %v\n", err)
+		os.Exit(1)
+	}
+	requireAPIKey(*apiKey)
+
+	if err := nodeAPIRequest(http.MethodPost, path, *apiKey, map[string]string{"at": *at}, nil); err != nil {
+		fatalNodeAPIError("schedule shutdown", err)
+	}
+	fmt.Printf("✅ Node %s will show as retiring until %s UTC\n", nodeID, date.Format("2006-01-02 15:04"))
+	fmt.Println("   Its uptime record ends then instead of counting missed crawls.")
+	fmt.Printf("   Changed your mind? Run: %s retire --cancel %s\n", os.Args[0], nodeID)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRetireDate(t *testing.T) {
+	now := time.Date(2025, 6, 15, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		in      string
+		want    string
+		wantErr string
+	}{
+		{"2025-07-01", "2025-07-01T00:00:00Z", ""},
+		{"2025-06-16", "2025-06-16T00:00:00Z", ""},
+		{"2026-06-15", "2026-06-15T00:00:00Z", ""},
+		{"2025-06-15", "", "not in the future"},
+		{"2024-12-31", "", "not in the future"},
+		{"2026-06-16", "", "more than 365 days"},
+		{"2025-02-30", "", "not a YYYY-MM-DD date"},
+		{"07/01/2025", "", "not a YYYY-MM-DD date"},
+		{"2025-07-01T12:00:00Z", "", "not a YYYY-MM-DD date"},
+	}
+
+	for _, tt := range tests {
+		got, err := parseRetireDate(tt.in, now)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.in, err)
+			continue
+		}
+		if got.Format(time.RFC3339) != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.in, got.Format(time.RFC3339), tt.want)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/atlasp2p/verify/internal/flags"
+)
+
+// Local JSON-RPC access to the daemon, used for checks that need more than
+// process and port heuristics. Credentials come from the daemon's config
+// (rpcuser/rpcpassword) or its .cookie file, like the daemon's own CLI.
+
+var rpcClient = &http.Client{Timeout: 10 * time.Second}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      string        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// BlockchainInfo is the subset of getblockchaininfo the checks use
+type BlockchainInfo struct {
+	Chain         string `json:"chain"`
+	Blocks        int64  `json:"blocks"`
+	BestBlockHash string `json:"bestblockhash"`
+}
+
+// NetworkInfo is the subset of getnetworkinfo the checks use
+type NetworkInfo struct {
+	Subversion    string `json:"subversion"`
+	Connections   int    `json:"connections"`
+	NetworkActive *bool  `json:"networkactive"`
+}
+
+// rpcAddr overrides the RPC host and port (--rpc-addr), e.g. for a daemon
+// in a container that publishes RPC on another address
+var rpcAddr flags.HostPort
+
+// rpcEndpoint resolves the RPC URL and credentials from --rpc-addr, the
+// daemon config, the .cookie file and the build-time RpcPort
+func rpcEndpoint() (url, user, password string, err error) {
+	conf, _ := readDaemonConf(daemonConfPath())
+
+	if rpcAddr.Host != "" {
+		url = "http://" + rpcAddr.String()
+	} else {
+		port := confRPCPort(conf)
+		if port == "" {
+			return "", "", "", fmt.Errorf("RPC port unknown (set rpcport in %s or use --rpc-addr)", daemonConfPath())
+		}
+		url = "http://" + net.JoinHostPort("127.0.0.1", port)
+	}
+
+	user, password, _, err = rpcCredentials(conf)
+	if err != nil {
+		return "", "", "", err
+	}
+	return url, user, password, nil
+}
+
+// confRPCPort is rpcport from the daemon config, else the build-time
+// RpcPort; "" when neither is set
+func confRPCPort(conf map[string]string) string {
+	if p := conf["rpcport"]; p != "" {
+		return p
+	}
+	return RpcPort
+}
+
+// localRPCPort is the RPC port to check for a listening socket on this
+// host, 0 when unknown or when --rpc-addr points elsewhere
+func localRPCPort() int {
+	if rpcAddr.Host != "" {
+		ip := net.ParseIP(rpcAddr.Host)
+		if rpcAddr.Host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			return 0
+		}
+		return int(rpcAddr.Port)
+	}
+	conf, _ := readDaemonConf(daemonConfPath())
+	var port flags.Port
+	if err := port.Set(confRPCPort(conf)); err != nil {
+		return 0
+	}
+	return int(port)
+}
+
+// rpcCredentials reads rpcuser/rpcpassword from the daemon config, or else
+// the .cookie file. source says which one was used: "config" or "cookie".
+func rpcCredentials(conf map[string]string) (user, password, source string, err error) {
+	if conf["rpcuser"] != "" && conf["rpcpassword"] != "" {
+		return conf["rpcuser"], conf["rpcpassword"], "config", nil
+	}
+
+	// Non-mainnet daemons write their cookie into a network subdirectory
+	var cookie []byte
+	for _, dir := range cookieDirs(confChain(conf)) {
+		if cookie, err = os.ReadFile(filepath.Join(dataDir, dir, ".cookie")); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return "", "", "", fmt.Errorf("no RPC credentials in config and no .cookie file in %s", dataDir)
+	}
+	user, password, _ = strings.Cut(strings.TrimSpace(string(cookie)), ":")
+	return user, password, "cookie", nil
+}
+
+// confChain reports the chain the daemon config selects, using the names
+// getblockchaininfo returns: "main", "test" or "regtest"
+func confChain(conf map[string]string) string {
+	switch {
+	case conf["regtest"] == "1":
+		return "regtest"
+	case conf["testnet"] == "1":
+		return "test"
+	}
+	return "main"
+}
+
+// cookieDirs lists the datadir subdirectories to search for the .cookie
+// file, starting with the one for chain
+func cookieDirs(chain string) []string {
+	switch chain {
+	case "test":
+		return []string{"testnet3", "", "regtest"}
+	case "regtest":
+		return []string{"regtest", "", "testnet3"}
+	}
+	return []string{"", "testnet3", "regtest"}
+}
+
+// rpcCall performs a single JSON-RPC call against the local daemon and
+// decodes the result into out
+func rpcCall(method string, out interface{}, params ...interface{}) error {
+	url, user, password, err := rpcEndpoint()
+	if err != nil {
+		return err
+	}
+
+	if params == nil {
+		params = []interface{}{}
+	}
+	jsonData, err := json.Marshal(rpcRequest{JSONRPC: "1.0", ID: "verify", Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(user, password)
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := rpcClient.Do(req)
+	if err != nil {
+		debugLog.Info("rpc call failed", "method", method, "err", err)
+		return fmt.Errorf("failed to connect to RPC: %w", err)
+	}
+	defer resp.Body.Close()
+	debugLog.Info("rpc call", "method", method, "status", resp.StatusCode, "duration", time.Since(start).Round(time.Millisecond))
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("RPC authentication failed")
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	debugLog.Debug("rpc response body", "method", method, "body", logExcerpt(body))
+
+	var rpcResp rpcResponse
+	if err := json.Unmarshal(body, &rpcResp); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("RPC error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(rpcResp.Result, out)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/atlasp2p/verify/internal/flags"
+)
+
+func TestLocalRPCPort(t *testing.T) {
+	savedDir, savedChain, savedAddr, savedPort := dataDir, ChainName, rpcAddr, RpcPort
+	t.Cleanup(func() { dataDir, ChainName, rpcAddr, RpcPort = savedDir, savedChain, savedAddr, savedPort })
+	dataDir, ChainName, RpcPort = t.TempDir(), "Dingocoin", ""
+
+	for addr, want := range map[string]int{
+		"127.0.0.1:34646": 34646,
+		"[::1]:34646":     34646,
+		"localhost:34646": 34646,
+		"10.0.0.2:34646":  0,
+		"rpc.lan:34646":   0,
+	} {
+		rpcAddr = flags.HostPort{}
+		if err := rpcAddr.Set(addr); err != nil {
+			t.Fatal(err)
+		}
+		if got := localRPCPort(); got != want {
+			t.Errorf("--rpc-addr %s: %d, want %d", addr, got, want)
+		}
+	}
+
+	rpcAddr = flags.HostPort{}
+	if got := localRPCPort(); got != 0 {
+		t.Errorf("nothing configured: %d", got)
+	}
+	RpcPort = "22555"
+	if got := localRPCPort(); got != 22555 {
+		t.Errorf("build-time RpcPort: %d", got)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, "dingocoin.conf"), []byte("rpcport=34646\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := localRPCPort(); got != 34646 {
+		t.Errorf("rpcport in the config: %d", got)
+	}
+}
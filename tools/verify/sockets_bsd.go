@@ -0,0 +1,25 @@
+//go:build freebsd || netbsd || dragonfly
+
+package main
+
+import "os/exec"
+
+// The socket tables aren't read directly here; sockstat (in checkPort) and
+// netstat list them, and sockstat also names each socket's owner
+func checkPortNative(port int) (bool, string) {
+	return false, ""
+}
+
+func listeningSocketOwner(port int) (int, string, bool) {
+	ctx, cancel := checkContext()
+	defer cancel()
+	output, err := commandOutput(exec.CommandContext(ctx, "sockstat", sockstatArgs...))
+	if err != nil {
+		return 0, "", false
+	}
+	return sockstatOwner(string(output), port)
+}
+
+func nativeListenAddrs(port int) ([]string, bool) {
+	return nil, false
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// checkPortNative looks for a listening socket without external tools:
+// inet_diag netlink first, /proc/net/tcp{,6} when netlink is unavailable
+func checkPortNative(port int) (bool, string) {
+	c := capabilities()
+	if c.Netlink {
+		if sockets, err := netlinkListeners(); err == nil {
+			for _, s := range sockets {
+				if s.Port == port {
+					return true, "netlink"
+				}
+			}
+			return false, ""
+		}
+	}
+
+	if c.ProcFS && len(procListenInodes(port)) > 0 {
+		return true, "proc"
+	}
+	return false, ""
+}
+
+// nativeListenAddrs returns the addresses of the sockets listening on
+// port, from netlink or /proc/net/tcp{,6}. ok is false when neither is
+// readable.
+func nativeListenAddrs(port int) (addrs []string, ok bool) {
+	c := capabilities()
+	if c.Netlink {
+		if sockets, err := netlinkListeners(); err == nil {
+			for _, s := range sockets {
+				if s.Port == port {
+					addrs = append(addrs, s.Addr.String())
+				}
+			}
+			return addrs, true
+		}
+	}
+	if c.ProcFS {
+		return procListenAddrs("/proc/net", port)
+	}
+	return nil, false
+}
+
+// procListenAddrs reads the addresses listening on port from the tcp and
+// tcp6 tables in dir (/proc/net, or /proc/<pid>/net for another namespace)
+func procListenAddrs(dir string, port int) (addrs []string, ok bool) {
+	for _, file := range []string{"tcp", "tcp6"} {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			continue
+		}
+		ok = true
+		addrs = append(addrs, procNetListenAddrs(string(data), port)...)
+	}
+	return addrs, ok
+}
+
+// listenInodes returns the inodes of sockets listening on port
+func listenInodes(port int) []string {
+	if !capabilities().Netlink {
+		return procListenInodes(port)
+	}
+	sockets, err := netlinkListeners()
+	if err != nil {
+		return procListenInodes(port)
+	}
+
+	var inodes []string
+	for _, s := range sockets {
+		if s.Port == port {
+			inodes = append(inodes, strconv.FormatUint(uint64(s.Inode), 10))
+		}
+	}
+	return inodes
+}
+
+func procListenInodes(port int) []string {
+	var inodes []string
+	for _, file := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		inodes = append(inodes, procNetListenInodes(string(data), port)...)
+	}
+	return inodes
+}
+
+// listeningSocketOwner finds the process owning the listening TCP socket on
+// port by matching socket inodes against /proc/*/fd. Sockets of other
+// users' processes are only visible when running as root.
+func listeningSocketOwner(port int) (int, string, bool) {
+	inodes := make(map[string]bool)
+	for _, inode := range listenInodes(port) {
+		inodes["socket:["+inode+"]"] = true
+	}
+	if len(inodes) == 0 {
+		return 0, "", false
+	}
+
+	fds, _ := filepath.Glob("/proc/[0-9]*/fd/*")
+	for _, fd := range fds {
+		target, err := os.Readlink(fd)
+		if err != nil || !inodes[target] {
+			continue
+		}
+		pid, err := strconv.Atoi(strings.Split(fd, "/")[2])
+		if err != nil {
+			continue
+		}
+		comm, _ := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+		return pid, strings.TrimSpace(string(comm)), true
+	}
+
+	return 0, "", false
+}
+
+// procNetListening reports whether a /proc/net/tcp{,6} table has a socket
+// in LISTEN state (0A) on port
+func procNetListening(table string, port int) bool {
+	return len(procNetListenInodes(table, port)) > 0
+}
+
+// procNetListenInodes returns the inodes of LISTEN sockets on port
+func procNetListenInodes(table string, port int) []string {
+	var inodes []string
+	for _, fields := range procNetListenRows(table, port) {
+		inodes = append(inodes, fields[9])
+	}
+	return inodes
+}
+
+// procNetListenAddrs returns the local addresses of LISTEN sockets on port
+func procNetListenAddrs(table string, port int) []string {
+	var addrs []string
+	for _, fields := range procNetListenRows(table, port) {
+		host, _, _ := strings.Cut(fields[1], ":")
+		if ip := procNetIP(host); ip != nil {
+			addrs = append(addrs, ip.String())
+		}
+	}
+	return addrs
+}
+
+// procNetListenRows returns the fields of the LISTEN (0A) rows on port
+func procNetListenRows(table string, port int) [][]string {
+	var rows [][]string
+	for _, line := range strings.Split(table, "\n")[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 10 || fields[3] != "0A" {
+			continue
+		}
+		idx := strings.LastIndex(fields[1], ":")
+		if idx < 0 {
+			continue
+		}
+		p, err := strconv.ParseInt(fields[1][idx+1:], 16, 32)
+		if err == nil && int(p) == port {
+			rows = append(rows, fields)
+		}
+	}
+	return rows
+}
+
+// procNetIP decodes an address of /proc/net/tcp{,6}: the network-order
+// address as 32-bit words, each printed in hex in host byte order
+func procNetIP(hexAddr string) net.IP {
+	raw, err := hex.DecodeString(hexAddr)
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return nil
+	}
+	ip := make(net.IP, len(raw))
+	for i := 0; i < len(raw); i += 4 {
+		word := binary.NativeEndian.Uint32(raw[i : i+4])
+		binary.BigEndian.PutUint32(ip[i:i+4], word)
+	}
+	return ip
+}
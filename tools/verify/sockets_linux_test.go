@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestProcNetIP(t *testing.T) {
+	// As printed on little-endian hosts
+	tests := map[string]string{
+		"0100007F":                         "127.0.0.1",
+		"00000000":                         "0.0.0.0",
+		"00000000000000000000000001000000": "::1",
+		"B80D0120000000000000000004000000": "2001:db8::4",
+	}
+	for in, want := range tests {
+		if got := procNetIP(in); got.String() != want {
+			t.Errorf("procNetIP(%s) = %v, want %s", in, got, want)
+		}
+	}
+	if ip := procNetIP("0100"); ip != nil {
+		t.Errorf("short address decoded as %v", ip)
+	}
+}
+
+func TestProcListenAddrs(t *testing.T) {
+	const header = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+	tcp := header +
+		"   0: 0100007F:8756 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 24516 1 0000000000000000 100 0 0 10 0\n"
+	tcp6 := header +
+		"   0: 00000000000000000000000000000000:815D 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 24517 1 0000000000000000 100 0 0 10 0\n"
+
+	dir := t.TempDir()
+	if _, ok := procListenAddrs(dir, 33117); ok {
+		t.Error("no tables: ok")
+	}
+	for name, table := range map[string]string{"tcp": tcp, "tcp6": tcp6} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(table), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		port int
+		want []string
+	}{
+		{33117, []string{"::"}},
+		{34646, []string{"127.0.0.1"}},
+		{22, nil},
+	}
+	for _, tt := range tests {
+		got, ok := procListenAddrs(dir, tt.port)
+		if !ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("port %d: %v, %v, want %v", tt.port, got, ok, tt.want)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !linux && !windows && !freebsd && !netbsd && !dragonfly
+
+package main
+
+// Native socket queries exist on Linux (netlink, /proc) and Windows (IP
+// Helper), and sockstat names socket owners on FreeBSD, NetBSD and DragonFly
+func checkPortNative(port int) (bool, string) {
+	return false, ""
+}
+
+func listeningSocketOwner(port int) (int, string, bool) {
+	return 0, "", false
+}
+
+func nativeListenAddrs(port int) ([]string, bool) {
+	return nil, false
+}
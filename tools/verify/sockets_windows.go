@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"syscall"
+	"unsafe"
+)
+
+// IP Helper's GetExtendedTcpTable lists listening sockets with their owning
+// PID, so no netstat process is spawned (EDR products on servers often flag
+// tools that shell out to netstat/tasklist)
+
+var (
+	iphlpapi                = syscall.NewLazyDLL("iphlpapi.dll")
+	procGetExtendedTcpTable = iphlpapi.NewProc("GetExtendedTcpTable")
+)
+
+const (
+	afInet6                  = 23
+	tcpTableOwnerPIDListener = 3
+	errInsufficientBuffer    = 122
+
+	// Row sizes of MIB_TCPROW_OWNER_PID and MIB_TCP6ROW_OWNER_PID
+	tcpRowSize  = 24
+	tcp6RowSize = 56
+)
+
+// tcpListener is a listening socket from the IP Helper tables
+type tcpListener struct {
+	Addr net.IP
+	Port int
+	PID  int
+}
+
+// tcpListeners reads the listening sockets of one address family
+func tcpListeners(family uint32) ([]tcpListener, error) {
+	if err := procGetExtendedTcpTable.Find(); err != nil {
+		return nil, err
+	}
+
+	var size uint32
+	var buf []byte
+	for i := 0; i < 3; i++ {
+		var ptr uintptr
+		if len(buf) > 0 {
+			ptr = uintptr(unsafe.Pointer(&buf[0]))
+		}
+		ret, _, _ := procGetExtendedTcpTable.Call(ptr, uintptr(unsafe.Pointer(&size)), 0,
+			uintptr(family), tcpTableOwnerPIDListener, 0)
+		if ret == 0 {
+			break
+		}
+		if ret != errInsufficientBuffer {
+			return nil, fmt.Errorf("GetExtendedTcpTable: %w", syscall.Errno(ret))
+		}
+		// The table may grow between calls
+		buf = make([]byte, size+1024)
+		size = uint32(len(buf))
+	}
+	if len(buf) < 4 {
+		return nil, nil
+	}
+
+	// MIB_TCPROW_OWNER_PID starts with the state, MIB_TCP6ROW_OWNER_PID
+	// with the address
+	rowSize, addrOffset, addrLen, portOffset, pidOffset := tcpRowSize, 4, net.IPv4len, 8, 20
+	if family == afInet6 {
+		rowSize, addrOffset, addrLen, portOffset, pidOffset = tcp6RowSize, 0, net.IPv6len, 20, 52
+	}
+
+	count := int(binary.LittleEndian.Uint32(buf))
+	listeners := make([]tcpListener, 0, count)
+	for i := 0; i < count; i++ {
+		row := 4 + i*rowSize
+		if row+rowSize > len(buf) {
+			break
+		}
+		listeners = append(listeners, tcpListener{
+			Addr: net.IP(append([]byte(nil), buf[row+addrOffset:row+addrOffset+addrLen]...)),
+			// The port is in network byte order in the low word
+			Port: int(buf[row+portOffset])<<8 | int(buf[row+portOffset+1]),
+			PID:  int(binary.LittleEndian.Uint32(buf[row+pidOffset:])),
+		})
+	}
+	return listeners, nil
+}
+
+// allTCPListeners combines the IPv4 and IPv6 tables. ok is false when
+// neither table could be read.
+func allTCPListeners() (listeners []tcpListener, ok bool) {
+	for _, family := range []uint32{syscall.AF_INET, afInet6} {
+		if l, err := tcpListeners(family); err == nil {
+			listeners = append(listeners, l...)
+			ok = true
+		}
+	}
+	return listeners, ok
+}
+
+// checkPortNative looks for a listening socket via IP Helper. A readable
+// table is authoritative, so the method is returned even when nothing
+// listens and checkPort skips the external tools.
+func checkPortNative(port int) (bool, string) {
+	listeners, ok := allTCPListeners()
+	if !ok {
+		return false, ""
+	}
+	for _, l := range listeners {
+		if l.Port == port {
+			return true, "iphlpapi"
+		}
+	}
+	return false, "iphlpapi"
+}
+
+// nativeListenAddrs returns the addresses of the sockets listening on port
+func nativeListenAddrs(port int) ([]string, bool) {
+	listeners, ok := allTCPListeners()
+	if !ok {
+		return nil, false
+	}
+	var addrs []string
+	for _, l := range listeners {
+		if l.Port == port {
+			addrs = append(addrs, l.Addr.String())
+		}
+	}
+	return addrs, true
+}
+
+// listeningSocketOwner reports which process owns the listening socket
+func listeningSocketOwner(port int) (int, string, bool) {
+	listeners, _ := allTCPListeners()
+	for _, l := range listeners {
+		if l.Port == port {
+			name, _ := processName(l.PID)
+			return l.PID, name, true
+		}
+	}
+	return 0, "", false
+}
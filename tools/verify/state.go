@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+)
+
+// State the tool keeps between runs (the performance baseline) lives in its
+// config directory. Appliance images often mount home or the root
+// filesystem read-only, so the state moves to --state-dir, or to
+// XDG_RUNTIME_DIR, instead of failing to save.
+
+// stateDirFlag is --state-dir, which always wins
+var stateDirFlag string
+
+var (
+	stateOnce      sync.Once
+	stateDirPath   string
+	stateRelocated bool
+)
+
+// stateDir returns the directory for state files, or "" when nothing is
+// writable. The answer is fixed for the run.
+func stateDir() string {
+	stateOnce.Do(func() {
+		stateDirPath, stateRelocated = pickStateDir(stateDirFlag, configDir(), os.Getenv("XDG_RUNTIME_DIR"))
+	})
+	return stateDirPath
+}
+
+// pickStateDir chooses between --state-dir, the config directory and the
+// runtime directory. relocated is set when the config directory was passed
+// over for not being writable.
+func pickStateDir(flagDir, confDir, runtimeDir string) (dir string, relocated bool) {
+	if flagDir != "" {
+		return flagDir, false
+	}
+	if confDir != "" && dirWritable(confDir) {
+		return confDir, false
+	}
+	if runtimeDir != "" {
+		if dir := filepath.Join(runtimeDir, configDirName); dirWritable(dir) {
+			return dir, confDir != ""
+		}
+	}
+	return "", false
+}
+
+// dirWritable reports whether files can be created in dir, or in the
+// nearest existing ancestor when dir doesn't exist yet. Nothing is left
+// behind; the probe file matches the temp files gc removes if it is.
+func dirWritable(dir string) bool {
+	for {
+		if info, err := os.Stat(dir); err == nil {
+			if !info.IsDir() {
+				return false
+			}
+			f, err := os.CreateTemp(dir, ".write-test.tmp-*")
+			if err != nil {
+				return false
+			}
+			f.Close()
+			os.Remove(f.Name())
+			return true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+// isReadOnly reports whether a write failed because the filesystem or
+// directory doesn't accept writes
+func isReadOnly(err error) bool {
+	return errors.Is(err, syscall.EROFS) || errors.Is(err, fs.ErrPermission)
+}
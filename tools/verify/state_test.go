@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPickStateDir(t *testing.T) {
+	tmp := t.TempDir()
+	// A path below a regular file can't be created, even by root
+	blocker := filepath.Join(tmp, "ro")
+	if err := os.WriteFile(blocker, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	readOnly := filepath.Join(blocker, "dingo-verify")
+	runtimeDir := filepath.Join(tmp, "run")
+
+	tests := []struct {
+		name          string
+		flagDir       string
+		confDir       string
+		runtimeDir    string
+		wantDir       string
+		wantRelocated bool
+	}{
+		{"writable config dir", "", filepath.Join(tmp, "config", "dingo-verify"), runtimeDir, filepath.Join(tmp, "config", "dingo-verify"), false},
+		{"read-only config dir", "", readOnly, runtimeDir, filepath.Join(runtimeDir, configDirName), true},
+		{"--state-dir wins", filepath.Join(tmp, "state"), readOnly, runtimeDir, filepath.Join(tmp, "state"), false},
+		{"nothing writable", "", readOnly, filepath.Join(blocker, "run"), "", false},
+	}
+	for _, tt := range tests {
+		dir, relocated := pickStateDir(tt.flagDir, tt.confDir, tt.runtimeDir)
+		if dir != tt.wantDir || relocated != tt.wantRelocated {
+			t.Errorf("%s: %q, relocated %v; want %q, %v", tt.name, dir, relocated, tt.wantDir, tt.wantRelocated)
+		}
+	}
+
+	// Probing leaves nothing behind
+	if entries, _ := os.ReadDir(tmp); len(entries) != 1 {
+		t.Errorf("probe left files: %v", entries)
+	}
+}
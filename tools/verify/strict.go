@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StrictEvidence is submitted with --strict: proofs that don't rest on
+// process names or port tables. The map marks the verification as
+// strict-verified when at least one of them holds up.
+type StrictEvidence struct {
+	// The daemon answered RPC with the credentials in its data directory
+	RPC *RPCProof `json:"rpc,omitempty"`
+	// signmessage over the challenge by a wallet of the daemon
+	Signature *SignatureProof `json:"signature,omitempty"`
+	// The map checks the handshake itself, from the userAgentCheck
+	Handshake bool `json:"handshake,omitempty"`
+}
+
+// RPCProof is what the daemon reported over authenticated RPC
+type RPCProof struct {
+	Subversion    string `json:"subversion"`
+	Chain         string `json:"chain"`
+	Blocks        int64  `json:"blocks"`
+	BestBlockHash string `json:"bestBlockHash"`
+}
+
+type SignatureProof struct {
+	Address   string `json:"address"`
+	Signature string `json:"signature"`
+}
+
+// StrictOutcome is the map's answer to the strict evidence
+type StrictOutcome struct {
+	Methods []string `json:"methods"`
+}
+
+// methods lists the evidence gathered, in the names the map uses
+func (e *StrictEvidence) methods() []string {
+	var methods []string
+	if e.RPC != nil {
+		methods = append(methods, "rpc")
+	}
+	if e.Signature != nil {
+		methods = append(methods, "signmessage")
+	}
+	if e.Handshake {
+		methods = append(methods, "handshake")
+	}
+	return methods
+}
+
+// gatherStrictEvidence collects the proofs --strict accepts. chainInfo is
+// the getblockchaininfo result, rpcErr its error. signAddress asks the
+// daemon's wallet to sign the challenge; handshake is set with --uacomment,
+// whose token the map reads in the P2P handshake. problems explains each
+// proof that could not be obtained.
+func gatherStrictEvidence(challenge string, chainInfo BlockchainInfo, rpcErr error, signAddress string, handshake bool) (evidence *StrictEvidence, problems []string) {
+	evidence = &StrictEvidence{Handshake: handshake}
+
+	if rpcErr != nil {
+		problems = append(problems, fmt.Sprintf("rpc: %v", rpcErr))
+	} else {
+		var netInfo NetworkInfo
+		if err := rpcCall("getnetworkinfo", &netInfo); err != nil {
+			problems = append(problems, fmt.Sprintf("rpc: %v", err))
+		} else {
+			evidence.RPC = &RPCProof{
+				Subversion:    netInfo.Subversion,
+				Chain:         chainInfo.Chain,
+				Blocks:        chainInfo.Blocks,
+				BestBlockHash: chainInfo.BestBlockHash,
+			}
+		}
+	}
+
+	if signAddress != "" {
+		var signature string
+		if err := rpcCall("signmessage", &signature, signAddress, challenge); err != nil {
+			problems = append(problems, fmt.Sprintf("signmessage: %v", err))
+		} else {
+			evidence.Signature = &SignatureProof{Address: signAddress, Signature: signature}
+		}
+	}
+	if !handshake {
+		problems = append(problems, "handshake: not requested (add --uacomment)")
+	}
+	return evidence, problems
+}
+
+func printStrictEvidence(evidence *StrictEvidence, problems []string) {
+	if methods := evidence.methods(); len(methods) > 0 {
+		fmt.Printf("  ✅ Strict evidence: %s\n", strings.Join(methods, ", "))
+	} else {
+		fmt.Println("  ❌ Strict mode found no cryptographic evidence")
+	}
+	for _, p := range problems {
+		fmt.Printf("     - %s\n", p)
+	}
+}
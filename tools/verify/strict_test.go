@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/atlasp2p/verify/internal/flags"
+)
+
+// fakeRPC serves getnetworkinfo and signmessage like a daemon whose wallet
+// holds only the address "DOwned"
+func fakeRPC(t *testing.T) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		switch {
+		case req.Method == "getnetworkinfo":
+			json.NewEncoder(w).Encode(map[string]any{"result": map[string]any{"subversion": "/Dingocoin:1.18.0/"}})
+		case req.Method == "signmessage" && req.Params[0] == "DOwned":
+			json.NewEncoder(w).Encode(map[string]any{"result": "H+sig=="})
+		default:
+			json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": -4, "message": "Private key not available"}})
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	savedDir, savedChain, savedAddr := dataDir, ChainName, rpcAddr
+	t.Cleanup(func() { dataDir, ChainName, rpcAddr = savedDir, savedChain, savedAddr })
+	dataDir, ChainName, rpcAddr = t.TempDir(), "Dingocoin", flags.HostPort{}
+	if err := rpcAddr.Set(strings.TrimPrefix(srv.URL, "http://")); err != nil {
+		t.Fatal(err)
+	}
+	conf := "rpcuser=u\nrpcpassword=p\n"
+	if err := os.WriteFile(filepath.Join(dataDir, "dingocoin.conf"), []byte(conf), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGatherStrictEvidence(t *testing.T) {
+	fakeRPC(t)
+	chainInfo := BlockchainInfo{Chain: "main", Blocks: 100, BestBlockHash: "00ab"}
+	const challenge = "abc123xyz456def789ghi0"
+
+	tests := []struct {
+		name         string
+		rpcErr       error
+		signAddress  string
+		handshake    bool
+		wantMethods  []string
+		wantProblems int
+	}{
+		{"rpc only", nil, "", false, []string{"rpc"}, 1},
+		{"rpc and signature", nil, "DOwned", false, []string{"rpc", "signmessage"}, 1},
+		{"foreign address", nil, "DOther", true, []string{"rpc", "handshake"}, 1},
+		{"no rpc", errors.New("RPC authentication failed"), "", true, []string{"handshake"}, 1},
+		{"nothing", errors.New("RPC authentication failed"), "", false, nil, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evidence, problems := gatherStrictEvidence(challenge, chainInfo, tt.rpcErr, tt.signAddress, tt.handshake)
+			if got := evidence.methods(); !reflect.DeepEqual(got, tt.wantMethods) {
+				t.Errorf("methods = %v, want %v", got, tt.wantMethods)
+			}
+			if len(problems) != tt.wantProblems {
+				t.Errorf("problems = %q, want %d", problems, tt.wantProblems)
+			}
+			if evidence.RPC != nil && (evidence.RPC.Subversion != "/Dingocoin:1.18.0/" || evidence.RPC.BestBlockHash != "00ab") {
+				t.Errorf("rpc proof %+v", evidence.RPC)
+			}
+			if evidence.Signature != nil && evidence.Signature.Signature != "H+sig==" {
+				t.Errorf("signature %+v", evidence.Signature)
+			}
+		})
+	}
+}
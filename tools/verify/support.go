@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+)
+
+// SupportReport says whether this platform can run the checks the map
+// requires. Without a process lookup the check would fail with a
+// misleading "daemon not found", so verify stops up front and says what is
+// missing instead. The port check can always fall back to dialing.
+type SupportReport struct {
+	OS             string         `json:"os"`
+	Arch           string         `json:"arch"`
+	Supported      bool           `json:"supported"`
+	ProcessMethods []string       `json:"processMethods"`
+	PortMethods    []string       `json:"portMethods"`
+	Issues         []SupportIssue `json:"issues,omitempty"`
+}
+
+// SupportIssue is a required check with no usable method, and what makes
+// one available
+type SupportIssue struct {
+	Check string `json:"check"` // "process"
+	Fix   string `json:"fix"`
+}
+
+// supportMatrix checks the probed capabilities of a goos/goarch host
+// against the required checks
+func supportMatrix(goos, goarch string, c Capabilities) SupportReport {
+	r := SupportReport{
+		OS:             goos,
+		Arch:           goarch,
+		ProcessMethods: processMethods(goos, c),
+		PortMethods:    portMethods(goos, c),
+	}
+	if len(r.ProcessMethods) == 0 {
+		r.Issues = append(r.Issues, SupportIssue{"process", processFix(goos)})
+	}
+	r.Supported = len(r.Issues) == 0
+	return r
+}
+
+// platformSupport is the support report of this host
+func platformSupport() SupportReport {
+	return supportMatrix(runtime.GOOS, runtime.GOARCH, capabilities())
+}
+
+func processFix(goos string) string {
+	switch goos {
+	case "linux":
+		return "allow reading /proc, or install procps (ps, pidof, pgrep) or BusyBox"
+	case "darwin", "freebsd", "openbsd", "netbsd", "dragonfly":
+		return "ps is part of the base system; add /bin to PATH"
+	}
+	return "put ps, pidof or pgrep in PATH"
+}
+
+// printSupportIssues explains why this platform can't verify
+func printSupportIssues(w io.Writer, r SupportReport, self string) {
+	fmt.Fprintf(w, "❌ This platform (%s/%s) can't run the verification checks:\n", r.OS, r.Arch)
+	for _, issue := range r.Issues {
+		fmt.Fprintf(w, "   No usable %s check: %s\n", issue.Check, issue.Fix)
+	}
+	fmt.Fprintf(w, "   Capability report: %s doctor --json\n", self)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSupportMatrix(t *testing.T) {
+	tests := []struct {
+		name       string
+		goos       string
+		caps       Capabilities
+		wantChecks []string
+		wantFix    string
+	}{
+		{"full Linux host", "linux", Capabilities{Netlink: true, Tools: map[string]bool{"ps": true}}, nil, ""},
+		{"distroless container", "linux", Capabilities{Tools: map[string]bool{}}, []string{"process"}, "procps"},
+		{"procfs without tools", "linux", Capabilities{ProcFS: true, Tools: map[string]bool{"pidof": true}}, nil, ""},
+		{"OpenBSD without netstat in PATH", "openbsd", Capabilities{Tools: map[string]bool{"ps": true}}, nil, ""},
+		{"OpenBSD without ps in PATH", "openbsd", Capabilities{Tools: map[string]bool{"netstat": true}}, []string{"process"}, "add /bin to PATH"},
+		{"Windows", "windows", Capabilities{Tools: map[string]bool{}}, nil, ""},
+		{"unknown platform", "plan9", Capabilities{Tools: map[string]bool{}}, []string{"process"}, "in PATH"},
+	}
+	for _, tt := range tests {
+		r := supportMatrix(tt.goos, "amd64", tt.caps)
+		var checks []string
+		for _, issue := range r.Issues {
+			checks = append(checks, issue.Check)
+		}
+		if !reflect.DeepEqual(checks, tt.wantChecks) || r.Supported != (len(tt.wantChecks) == 0) {
+			t.Errorf("%s: issues %v, supported %v; want %v", tt.name, checks, r.Supported, tt.wantChecks)
+		}
+		if tt.wantFix != "" && !strings.Contains(r.Issues[0].Fix, tt.wantFix) {
+			t.Errorf("%s: fix %q doesn't mention %q", tt.name, r.Issues[0].Fix, tt.wantFix)
+		}
+	}
+}
+
+func TestPrintSupportIssues(t *testing.T) {
+	var out strings.Builder
+	printSupportIssues(&out, supportMatrix("openbsd", "arm64", Capabilities{Tools: map[string]bool{}}), "dingocoin-verify")
+	for _, want := range []string{"openbsd/arm64", "No usable process check", "dingocoin-verify doctor --json"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// systemdUnit asks systemd whether daemon runs as <daemon>.service and
+// returns the unit and its main PID while it is active
+func systemdUnit(ctx context.Context, daemon string) (unit string, mainPID int, ok bool) {
+	unit = daemon + ".service"
+	output, err := commandOutput(exec.CommandContext(ctx, "systemctl", "show", "--property=ActiveState,MainPID", "--", unit))
+	if err != nil {
+		return "", 0, false
+	}
+	props := parseSystemdShow(string(output))
+	mainPID, _ = strconv.Atoi(props["MainPID"])
+	if props["ActiveState"] != "active" || mainPID <= 0 {
+		return "", 0, false
+	}
+	return unit, mainPID, true
+}
+
+// parseSystemdShow reads the Key=Value lines of systemctl show
+func parseSystemdShow(output string) map[string]string {
+	props := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+			props[key] = value
+		}
+	}
+	return props
+}
+
+// checkProcessSystemd finds the daemon through its service unit. The unit's
+// main PID must be the daemon itself, so a unit wrapping a shell script or
+// restarting in a loop doesn't count.
+func checkProcessSystemd(ctx context.Context, daemon string) (found bool, unit string, mainPID int) {
+	unit, mainPID, ok := systemdUnit(ctx, daemon)
+	if !ok {
+		return false, "", 0
+	}
+	table, err := processTable(ctx)
+	if err != nil {
+		return false, "", 0
+	}
+	if entry, ok := table[mainPID]; !ok || !entry.isDaemon(daemon) {
+		return false, "", 0
+	}
+	return true, unit, mainPID
+}
+
+// printSystemdUnit shows the unit managing the daemon and how long it has
+// been up
+func printSystemdUnit(evidence *ProcessEvidence) {
+	if evidence == nil || evidence.Unit == "" {
+		return
+	}
+	if started := evidence.started(); !started.IsZero() {
+		fmt.Print(tr("  ℹ️  Managed by systemd unit %s, up %s\n", evidence.Unit, time.Since(started).Round(time.Minute)))
+		return
+	}
+	fmt.Print(tr("  ℹ️  Managed by systemd unit %s\n", evidence.Unit))
+}
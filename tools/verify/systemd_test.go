@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestParseSystemdShow(t *testing.T) {
+	props := parseSystemdShow("MainPID=1234\nActiveState=active\nExecMainStatus=\n")
+	if props["MainPID"] != "1234" || props["ActiveState"] != "active" {
+		t.Errorf("props = %v", props)
+	}
+	if v, ok := props["ExecMainStatus"]; !ok || v != "" {
+		t.Errorf("empty value: %q, %v", v, ok)
+	}
+
+	// A unit that doesn't exist still has properties, just no main PID
+	props = parseSystemdShow("MainPID=0\nActiveState=inactive\n")
+	if props["MainPID"] != "0" || props["ActiveState"] != "inactive" {
+		t.Errorf("missing unit: props = %v", props)
+	}
+}
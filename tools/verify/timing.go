@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StepTimings are how long the steps of a run took, in milliseconds. They
+// go with the confirm request so the map can spot slow or overloaded nodes,
+// and tell the operator whether the API or the local checks are the
+// bottleneck.
+type StepTimings struct {
+	InitMs         int64 `json:"initMs"`
+	ProcessCheckMs int64 `json:"processCheckMs"`
+	PortCheckMs    int64 `json:"portCheckMs"`
+	// All of step 2, the process and port checks included
+	ChecksMs int64 `json:"checksMs"`
+	// Only in the result: the request is sent before it is known
+	ConfirmMs int64 `json:"confirmMs,omitempty"`
+}
+
+// printStepTime shows how long a step took
+func printStepTime(d time.Duration) {
+	fmt.Printf("  ⏱️  %s\n", d.Round(100*time.Millisecond))
+}
+
+// spinnerFrames are ASCII so --no-color output keeps them
+const spinnerFrames = `|/-\`
+
+// startSpinner animates label on the terminal until stop is called, which
+// clears the line again. Without a terminal it does nothing.
+func startSpinner(label string) (stop func()) {
+	if !isTerminal(os.Stdout) {
+		return func() {}
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			fmt.Printf("\r  %c %s", spinnerFrames[i%len(spinnerFrames)], label)
+			select {
+			case <-done:
+				fmt.Printf("\r%s\r", strings.Repeat(" ", len(label)+4))
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// The backend's zod schema accepts exactly these keys (validations.ts)
+func TestStepTimingsJSON(t *testing.T) {
+	data, err := json.Marshal(StepTimings{InitMs: 420, ProcessCheckMs: 35, PortCheckMs: 12, ChecksMs: 1800})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"initMs":420,"processCheckMs":35,"portCheckMs":12,"checksMs":1800}`; string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
+
+func TestSpinnerWithoutTerminal(t *testing.T) {
+	// Tests don't run on a terminal: nothing is drawn and stop returns
+	startSpinner("waiting")()
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// A tip older than this is stale. It is long enough that normal block time
+// variance on the supported chains rarely reaches it.
+const staleTipAge = time.Hour
+
+// TipStatus is the age of the daemon's chain tip and how it compares to
+// what the connected peers have
+type TipStatus struct {
+	Height        int64     `json:"height"`
+	LastBlockTime time.Time `json:"lastBlockTime"`
+	TipAgeSeconds int64     `json:"tipAgeSeconds"`
+	// Best block each peer has announced (getpeerinfo synced_blocks)
+	PeerTips   []int64 `json:"peerTips"`
+	PeersAhead int     `json:"peersAhead"`
+	// Set when the tip is stale: "behind-peers", "no-peers" or
+	// "network-stalled"
+	Stale string `json:"stale,omitempty"`
+}
+
+// BlockHeader is the subset of getblockheader the tip check uses
+type BlockHeader struct {
+	Time int64 `json:"time"`
+}
+
+// assessTip classifies a stale tip. A node behind its peers has a local
+// problem; when no peer is ahead either, the whole network may have stopped
+// producing blocks.
+func assessTip(height int64, blockTime, now time.Time, peers []PeerInfo) TipStatus {
+	status := TipStatus{
+		Height:        height,
+		LastBlockTime: blockTime.UTC(),
+		TipAgeSeconds: int64(now.Sub(blockTime) / time.Second),
+		PeerTips:      []int64{},
+	}
+	for _, peer := range peers {
+		// -1 until the peer has announced a block
+		if peer.SyncedBlocks < 0 {
+			continue
+		}
+		status.PeerTips = append(status.PeerTips, peer.SyncedBlocks)
+		if peer.SyncedBlocks > height {
+			status.PeersAhead++
+		}
+	}
+
+	if now.Sub(blockTime) <= staleTipAge {
+		return status
+	}
+	switch {
+	case status.PeersAhead > 0:
+		status.Stale = "behind-peers"
+	case len(status.PeerTips) == 0:
+		status.Stale = "no-peers"
+	default:
+		status.Stale = "network-stalled"
+	}
+	return status
+}
+
+// checkTip reads the tip and the peers' tips over RPC
+func checkTip(info BlockchainInfo) (TipStatus, error) {
+	var header BlockHeader
+	if err := rpcCall("getblockheader", &header, info.BestBlockHash); err != nil {
+		return TipStatus{}, err
+	}
+	var peers []PeerInfo
+	if err := rpcCall("getpeerinfo", &peers); err != nil {
+		return TipStatus{}, err
+	}
+	return assessTip(info.Blocks, time.Unix(header.Time, 0), time.Now(), peers), nil
+}
+
+func printTip(status TipStatus) {
+	age := (time.Duration(status.TipAgeSeconds) * time.Second).Round(time.Minute)
+	switch status.Stale {
+	case "":
+		fmt.Printf("  ✅ Chain tip: block %d, %s old\n", status.Height, age)
+	case "behind-peers":
+		fmt.Printf("  ⚠️  Chain tip is %s old and %d of %d peers have newer blocks:\n", age, status.PeersAhead, len(status.PeerTips))
+		fmt.Println("     your node is falling behind (check disk, CPU and debug.log)")
+	case "no-peers":
+		fmt.Printf("  ⚠️  Chain tip is %s old and no peer has announced a block:\n", age)
+		fmt.Println("     check the node's connectivity")
+	default:
+		fmt.Printf("  ⚠️  Network may be stalled: the chain tip is %s old and none of\n", age)
+		fmt.Printf("     %d peers has a newer block. Your node looks healthy.\n", len(status.PeerTips))
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAssessTip(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	fresh := now.Add(-5 * time.Minute)
+	stale := now.Add(-3 * time.Hour)
+
+	tests := []struct {
+		name      string
+		blockTime time.Time
+		peers     []PeerInfo
+		wantStale string
+		wantAhead int
+		wantTips  int
+	}{
+		{"fresh tip", fresh, []PeerInfo{{SyncedBlocks: 100}, {SyncedBlocks: 101}}, "", 1, 2},
+		{"behind peers", stale, []PeerInfo{{SyncedBlocks: 100}, {SyncedBlocks: 140}}, "behind-peers", 1, 2},
+		{"network stalled", stale, []PeerInfo{{SyncedBlocks: 100}, {SyncedBlocks: 99}}, "network-stalled", 0, 2},
+		{"peers not synced yet", stale, []PeerInfo{{SyncedBlocks: -1}}, "no-peers", 0, 0},
+		{"no peers", stale, nil, "no-peers", 0, 0},
+	}
+
+	for _, tt := range tests {
+		got := assessTip(100, tt.blockTime, now, tt.peers)
+		if got.Stale != tt.wantStale || got.PeersAhead != tt.wantAhead || len(got.PeerTips) != tt.wantTips {
+			t.Errorf("%s: got stale %q, %d ahead, %d tips; want %q, %d, %d",
+				tt.name, got.Stale, got.PeersAhead, len(got.PeerTips), tt.wantStale, tt.wantAhead, tt.wantTips)
+		}
+		if want := int64(now.Sub(tt.blockTime) / time.Second); got.TipAgeSeconds != want {
+			t.Errorf("%s: TipAgeSeconds = %d, want %d", tt.name, got.TipAgeSeconds, want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// userAgentTokenPrefix marks uacomment entries written for the map, so a
+// later verification replaces them instead of piling up tokens
+const userAgentTokenPrefix = "nm-"
+
+// addUserAgentComment sets uacomment=token in the daemon config at path.
+// The original file is copied next to it first and the copy's path is
+// returned ("" when the config did not exist yet); older backups beyond
+// gcKeepConfigBackups are removed. Earlier map tokens are
+// replaced; the operator's own uacomment entries are kept. The config is
+// replaced atomically and keeps its owner and mode; a new one gets the
+// data directory's owner, so a daemon running as another user can read it.
+func addUserAgentComment(path, token string) (backup string, err error) {
+	mode := fs.FileMode(0600)
+	owner := filepath.Dir(path)
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		data = nil
+	case err != nil:
+		return "", err
+	default:
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+		owner = path
+		backup = path + ".bak-" + time.Now().Format("20060102-150405")
+		if err := writeFileAtomic(backup, data, mode, owner); err != nil {
+			return "", fmt.Errorf("backing up %s: %w", path, err)
+		}
+		// Every run backs up the config; only the newest few are useful
+		for _, old := range staleConfigBackups(path, gcKeepConfigBackups) {
+			os.Remove(old)
+		}
+	}
+
+	updated := setUserAgentComment(string(data), token)
+	if err := writeFileAtomic(path, []byte(updated), mode, owner); err != nil {
+		return backup, err
+	}
+	return backup, nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it into place, so a crash never leaves a truncated file. The result gets
+// mode and the owner of ownerPath.
+func writeFileAtomic(path string, data []byte, mode fs.FileMode, ownerPath string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	if err := copyOwner(tmp.Name(), ownerPath); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// setUserAgentComment returns conf with a uacomment=token line. It goes
+// before the first [section] so it applies on every network.
+func setUserAgentComment(conf, token string) string {
+	entry := "uacomment=" + token
+
+	var lines []string
+	inserted := false
+	for _, line := range strings.Split(strings.TrimRight(conf, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if key, value, ok := strings.Cut(trimmed, "="); ok && strings.TrimSpace(key) == "uacomment" &&
+			strings.HasPrefix(strings.TrimSpace(value), userAgentTokenPrefix) {
+			continue
+		}
+		if !inserted && strings.HasPrefix(trimmed, "[") {
+			lines = append(lines, entry)
+			inserted = true
+		}
+		lines = append(lines, line)
+	}
+	if !inserted {
+		if len(lines) == 1 && lines[0] == "" {
+			lines = lines[:0]
+		}
+		lines = append(lines, entry)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
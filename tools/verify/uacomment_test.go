@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetUserAgentComment(t *testing.T) {
+	tests := []struct {
+		name string
+		conf string
+		want string
+	}{
+		{"empty", "", "uacomment=nm-abc\n"},
+		{"append", "server=1\nlisten=1\n", "server=1\nlisten=1\nuacomment=nm-abc\n"},
+		{"no trailing newline", "server=1", "server=1\nuacomment=nm-abc\n"},
+		{"replace old token", "uacomment=nm-old\nserver=1\n", "server=1\nuacomment=nm-abc\n"},
+		{"keep own comment", "uacomment=mypool\n", "uacomment=mypool\nuacomment=nm-abc\n"},
+		{"before sections", "server=1\n[test]\nport=1\n", "server=1\nuacomment=nm-abc\n[test]\nport=1\n"},
+		{"old token in section", "[main]\nuacomment = nm-old\n", "uacomment=nm-abc\n[main]\n"},
+	}
+
+	for _, tt := range tests {
+		if got := setUserAgentComment(tt.conf, "nm-abc"); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAddUserAgentComment(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *string
+		mode     os.FileMode
+		want     string
+	}{
+		{"new config", nil, 0600, "uacomment=nm-abc\n"},
+		{"existing config", strPtr("server=1\nuacomment=nm-old\n"), 0640, "server=1\nuacomment=nm-abc\n"},
+	}
+
+	for _, tt := range tests {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "dingocoin.conf")
+		if tt.existing != nil {
+			if err := os.WriteFile(path, []byte(*tt.existing), tt.mode); err != nil {
+				t.Fatal(err)
+			}
+			os.Chmod(path, tt.mode)
+		}
+
+		backup, err := addUserAgentComment(path, "nm-abc")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		got, _ := os.ReadFile(path)
+		if string(got) != tt.want {
+			t.Errorf("%s: config = %q, want %q", tt.name, got, tt.want)
+		}
+		if info, err := os.Stat(path); err != nil || info.Mode().Perm() != tt.mode {
+			t.Errorf("%s: config mode = %v, want %v", tt.name, info.Mode().Perm(), tt.mode)
+		}
+
+		if tt.existing == nil {
+			if backup != "" {
+				t.Errorf("%s: backup %q made of a missing config", tt.name, backup)
+			}
+		} else if saved, err := os.ReadFile(backup); err != nil || string(saved) != *tt.existing {
+			t.Errorf("%s: backup = %q, %v, want the original config", tt.name, saved, err)
+		}
+
+		// Only the config and its backup remain, no temporary files
+		entries, _ := os.ReadDir(dir)
+		want := 1
+		if backup != "" {
+			want = 2
+		}
+		if len(entries) != want {
+			t.Errorf("%s: %d files left in the data directory, want %d", tt.name, len(entries), want)
+		}
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
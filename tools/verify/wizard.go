@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// wizardHint explains an exit code in plain language. newChallenge is set
+// when retrying needs a fresh challenge from the website.
+func wizardHint(code, port int) (hint []string, newChallenge bool) {
+	switch code {
+	case exitDaemonNotFound:
+		return []string{
+			tr("Your node software doesn't seem to be running on this computer."),
+			tr("Start it (for example: sudo systemctl start %s) and wait a minute.", primaryDaemon()),
+			tr("Run this tool on the server where the node runs, not on your own PC."),
+		}, false
+	case exitPortNotListening:
+		return []string{
+			tr("Your node is running but isn't accepting connections on its port (usually %d).", port),
+			tr("Make sure its config has listen=1 and no other port= setting, then restart it."),
+			tr("A node that has just started can take a minute before it listens."),
+		}, false
+	case exitAPIUnreachable:
+		return []string{
+			tr("The map's server couldn't be reached."),
+			tr("Check that this computer can open %s (internet access, firewall, proxy).", ApiUrl),
+		}, false
+	case exitChallengeRejected:
+		return []string{
+			tr("The map didn't recognise this challenge."),
+			tr("Copy it again from the website; it must be copied in full."),
+		}, true
+	case exitChallengeExpired:
+		return []string{
+			tr("The challenge has expired."),
+			tr("Create a new one at %s/my-nodes.", ApiUrl),
+		}, true
+	case exitChallengeUsed:
+		return []string{
+			tr("This challenge was already used, or the node is already verified."),
+			tr("Check %s/my-nodes, or create a new challenge there.", ApiUrl),
+		}, true
+	case exitChainMismatch:
+		return []string{
+			tr("The node on this computer runs on a test network, not the main network."),
+			tr("Point the tool at your main node's data directory with --datadir."),
+		}, false
+	case exitUnsupported:
+		return []string{
+			tr("This computer lacks the tools the checks need; the message above says what to install."),
+		}, false
+	}
+	return []string{tr("Something else went wrong; the message above says what.")}, false
+}
+
+// promptChallenge asks for a challenge until a valid one is entered. It
+// returns false when the operator gives up (empty line or end of input).
+func promptChallenge(stdin *bufio.Reader) (string, bool) {
+	for {
+		fmt.Print(tr("Paste the challenge from the website (or press Enter to quit): "))
+		line, err := stdin.ReadString('\n')
+		challenge := strings.TrimSpace(line)
+		if challenge == "" {
+			if err == nil {
+				fmt.Println()
+			}
+			return "", false
+		}
+		if isValidChallenge(challenge) {
+			return challenge, true
+		}
+		fmt.Println(tr("  That doesn't look like a challenge: it should be 20-128 letters and digits."))
+	}
+}
+
+// wizardChallenge pairs with the website for the challenge, falling back to
+// pasting it when the server can't pair or the code expires
+func wizardChallenge(stdin *bufio.Reader) (string, bool) {
+	challenge, err := pairChallenge()
+	if err == nil {
+		return challenge, true
+	}
+	fmt.Print(tr("⚠️  Pairing with the website didn't work: %v\n", err))
+	fmt.Println(tr("You can paste the challenge shown on the website instead."))
+	return promptChallenge(stdin)
+}
+
+// runWizard guides an operator who started the tool without a challenge.
+// Each attempt runs the tool itself with the challenge, so its progress is
+// shown live, and the exit code decides what to explain. args are the
+// options the tool was started with. Returns the exit code to use.
+func runWizard(args []string) int {
+	self, err := os.Executable()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	stdin := bufio.NewReader(os.Stdin)
+
+	fmt.Print(tr("Welcome! This checks that you run the %s node you added on the map.\n", ChainName))
+	fmt.Println()
+
+	challenge, ok := wizardChallenge(stdin)
+	if !ok {
+		return 1
+	}
+	for {
+		fmt.Println()
+		cmd := exec.Command(self, append(append([]string{}, args...), challenge)...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		code := 0
+		if err := cmd.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				fmt.Printf("❌ %v\n", err)
+				return 1
+			}
+			code = exitErr.ExitCode()
+		}
+		if code == 0 {
+			return 0
+		}
+
+		fmt.Println()
+		fmt.Println(tr("What went wrong:"))
+		hint, newChallenge := wizardHint(code, int(defaultPort))
+		for _, line := range hint {
+			fmt.Printf("  %s\n", line)
+		}
+		fmt.Println()
+
+		if newChallenge {
+			if challenge, ok = wizardChallenge(stdin); !ok {
+				return code
+			}
+			continue
+		}
+		fmt.Print(tr("Fix this, then press Enter to try again (or type q to quit): "))
+		answer, err := stdin.ReadString('\n')
+		if err != nil || strings.HasPrefix(strings.ToLower(strings.TrimSpace(answer)), "q") {
+			fmt.Println()
+			return code
+		}
+	}
+}
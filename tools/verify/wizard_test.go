@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestWizardHint(t *testing.T) {
+	tests := []struct {
+		code             int
+		wantNewChallenge bool
+	}{
+		{exitDaemonNotFound, false},
+		{exitPortNotListening, false},
+		{exitAPIUnreachable, false},
+		{exitChallengeRejected, true},
+		{exitChallengeExpired, true},
+		{exitChallengeUsed, true},
+		{exitChainMismatch, false},
+		{1, false},
+	}
+
+	seen := make(map[string]int)
+	for _, tt := range tests {
+		hint, newChallenge := wizardHint(tt.code, 33117)
+		if len(hint) == 0 {
+			t.Errorf("exit code %d has no hint", tt.code)
+			continue
+		}
+		if newChallenge != tt.wantNewChallenge {
+			t.Errorf("exit code %d: newChallenge = %v, want %v", tt.code, newChallenge, tt.wantNewChallenge)
+		}
+		if other, dup := seen[hint[0]]; dup {
+			t.Errorf("exit codes %d and %d share the hint %q", other, tt.code, hint[0])
+		}
+		seen[hint[0]] = tt.code
+	}
+}
+
+func TestPromptChallenge(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{"abcdefghij0123456789\n", "abcdefghij0123456789", true},
+		{"  abcdefghij0123456789  \r\n", "abcdefghij0123456789", true},
+		{"too-short\nabcdefghij0123456789\n", "abcdefghij0123456789", true},
+		{"\n", "", false},
+		{"", "", false},
+		{"not valid!\n", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := promptChallenge(bufio.NewReader(strings.NewReader(tt.input)))
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("promptChallenge(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}